@@ -0,0 +1,63 @@
+package pb
+
+import "wallet-user-svc/internal/app/errs"
+
+// Validate methods check the shape of requests before they reach a handler; see
+// grpc.ValidationInterceptor. They only cover fields every call must set. Rules that
+// depend on configuration or stored data stay in the service.
+
+// Validate checks that the refresh token is set
+func (r *RefreshTokenRequest) Validate() error {
+	return errs.NewValidationError(required("refresh_token", r.GetRefreshToken())...)
+}
+
+// Validate checks that the refresh token is set
+func (r *RotateRefreshTokenRequest) Validate() error {
+	return errs.NewValidationError(required("refresh_token", r.GetRefreshToken())...)
+}
+
+// Validate checks that the challenge and one of the code or backup code are set
+func (r *CompleteLoginRequest) Validate() error {
+	violations := required("challenge_id", r.GetChallengeId())
+	if r.GetCode() == "" && r.GetBackupCode() == "" {
+		violations = append(violations, errs.FieldViolation{Field: "code", Err: errs.ErrFieldRequired})
+	}
+	return errs.NewValidationError(violations...)
+}
+
+// Validate checks that the session to revoke is set
+func (r *RevokeSessionRequest) Validate() error {
+	return errs.NewValidationError(required("session_id", r.GetSessionId())...)
+}
+
+// Validate checks that the verification and its code are set
+func (r *ConfirmContactMethodRequest) Validate() error {
+	return errs.NewValidationError(append(
+		required("verification_id", r.GetVerificationId()),
+		required("code", r.GetCode())...,
+	)...)
+}
+
+// Validate checks that the session and the credential are set
+func (r *FinishWebAuthnRegistrationRequest) Validate() error {
+	return errs.NewValidationError(append(
+		required("session_id", r.GetSessionId()),
+		required("credential_json", r.GetCredentialJson())...,
+	)...)
+}
+
+// Validate checks that the session and the credential are set
+func (r *FinishWebAuthnLoginRequest) Validate() error {
+	return errs.NewValidationError(append(
+		required("session_id", r.GetSessionId()),
+		required("credential_json", r.GetCredentialJson())...,
+	)...)
+}
+
+// required returns a violation of field if value is empty
+func required(field, value string) []errs.FieldViolation {
+	if value != "" {
+		return nil
+	}
+	return []errs.FieldViolation{{Field: field, Err: errs.ErrFieldRequired}}
+}
@@ -14,19 +14,30 @@ import (
 	pb "wallet-user-svc/api/proto"
 	"wallet-user-svc/db"
 	"wallet-user-svc/internal/app/config"
+	"wallet-user-svc/internal/app/errs"
 	"wallet-user-svc/internal/app/handler"
+	"wallet-user-svc/internal/app/model/events"
 	"wallet-user-svc/internal/app/repository"
 	"wallet-user-svc/internal/app/service"
 	"wallet-user-svc/internal/workers"
 	"wallet-user-svc/pkg/migrate"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/crypt/secret"
 	"wallet-user-svc/pkg/utils/crypt/token"
 	grpcutils "wallet-user-svc/pkg/utils/grpc"
 	logutils "wallet-user-svc/pkg/utils/log"
+	"wallet-user-svc/pkg/utils/netutil"
+	"wallet-user-svc/pkg/utils/ratelimit"
 	"wallet-user-svc/pkg/utils/tx"
+	"wallet-user-svc/pkg/version"
 
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -48,42 +59,82 @@ func main() {
 		logger.Fatalf("Configuration validation failed: %v", err)
 	}
 
-	// Run database migrations
-	databaseURL := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.DBName,
-	)
+	// Errors created from here on carry a stack trace only if configured
+	errs.SetCaptureStack(cfg.Errors.CaptureStack)
 
-	migrationConfig := migrate.Config{
-		DatabaseURL:    databaseURL,
-		MigrationsPath: "./db/migrations",
+	if cfg.Database.AutoMigrate {
+		runStartupMigrations(&cfg.Database, logger)
+	} else {
+		logger.Info("Automatic migrations are disabled, run them with the migrate tool")
 	}
 
-	if err := migrate.RunMigrations(migrationConfig); err != nil {
-		logger.Fatalf("Failed to run database migrations: %v", err)
+	tokenMaker := token.NewJWTTokenMaker(cfg.JWT.SecretKey, cfg.JWT.Leeway, cfg.JWT.PreviousSecretKeys...)
+	serviceTokens, err := token.NewServiceTokens(cfg.ServiceAuth.Tokens)
+	if err != nil {
+		logger.Fatalf("Invalid service auth configuration: %v", err)
+	}
+
+	trustedProxies, err := netutil.ParseCIDRs(cfg.Server.TrustedProxies)
+	if err != nil {
+		logger.Fatalf("Invalid trusted proxies: %v", err)
 	}
-	logger.Info("Database migrations completed successfully")
 
-	// Get interceptors for exception handling
-	unaryInterceptors := grpcutils.GetUnaryInterceptors(logger)
-	streamInterceptors := grpcutils.GetStreamInterceptors(logger)
+	// Get interceptors for the version header, TLS enforcement, client IP resolution, exception handling, payload logging, compression, tenancy, authentication and request validation
+	unaryInterceptors := grpcutils.GetUnaryInterceptors(
+		logger,
+		cfg.Server.ExposePanicDetails,
+		cfg.Errors.CaptureStack,
+		grpcutils.VersionInterceptor(version.Get().String()),
+		grpcutils.RequireTLSInterceptor(cfg.Server.RequireTLS, handler.TLSExemptMethods),
+		grpcutils.ClientIPInterceptor(netutil.NewClientIPResolver(trustedProxies)),
+		grpcutils.SlowRequestInterceptor(cfg.Server.SlowRequestThreshold),
+		grpcutils.PayloadLoggingInterceptor(cfg.Log.LogPayloads, cfg.Log.PayloadMethods),
+		grpcutils.CompressionInterceptor(cfg.Server.EnableCompression),
+		grpcutils.TenantInterceptor(cfg.Tenancy.Enabled, handler.TenantExemptMethods),
+		grpcutils.AuthInterceptor(tokenMaker, serviceTokens, handler.MethodAccessPolicies, grpcutils.ExpiryGrace{
+			Grace:           cfg.JWT.ReadOnlyGrace,
+			ReadOnlyMethods: handler.ReadOnlyMethods,
+		}),
+		grpcutils.ValidationInterceptor(cfg.Server.SkipValidationMethods),
+	)
+	streamInterceptors := grpcutils.GetStreamInterceptors(logger, cfg.Server.ExposePanicDetails, cfg.Errors.CaptureStack)
 
-	// Create gRPC server with interceptors
+	// Create gRPC server with interceptors and connection lifecycle logging at Debug level
 	serverOptions := append(unaryInterceptors, streamInterceptors...)
+	serverOptions = append(serverOptions, grpc.StatsHandler(grpcutils.NewConnectionLoggingHandler(logger)))
+	if cfg.Server.TLSEnabled() {
+		creds, err := credentials.NewServerTLSFromFile(cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile)
+		if err != nil {
+			logger.Fatalf("Failed to load TLS credentials: %v", err)
+		}
+		serverOptions = append(serverOptions, grpc.Creds(creds))
+	}
 	grpcServer := grpc.NewServer(serverOptions...)
 
 	db, err := db.NewStore(&cfg.Database)
 	if err != nil {
 		logger.Fatalf("Failed to create database store: %v", err)
 	}
-	userRepo := repository.NewUserRepository(db)
+	userRepo := repository.NewUserRepository(db, cfg.Registration.UniqueUsernames)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	txManager := tx.NewTransactionManager(db.DB())
-	tokenMaker := token.NewJWTTokenMaker(cfg.JWT.SecretKey)
-	notificationEventLogRepo := repository.NewNotificationEventLogRepository(db)
+	notificationEventLogRepo := repository.NewNotificationEventLogRepository(db, repository.PriorityAging{
+		After: cfg.Worker.Notification.PriorityAging.After,
+		Boost: cfg.Worker.Notification.PriorityAging.Boost,
+	})
+
+	secretCipher, err := secret.NewCipher(cfg.MFA.EncryptionKey, cfg.MFA.PreviousEncryptionKeys...)
+	if err != nil {
+		logger.Fatalf("Failed to create MFA secret cipher: %v", err)
+	}
+
+	webAuthn, err := service.NewWebAuthn(&cfg.WebAuthn)
+	if err != nil {
+		logger.Fatalf("Failed to create WebAuthn relying party: %v", err)
+	}
+
+	rateLimitStore, closeRateLimitStore := newRateLimitStore(cfg, logger)
+	defer closeRateLimitStore()
 
 	userService := service.NewUserService(
 		cfg,
@@ -92,12 +143,31 @@ func main() {
 		txManager,
 		tokenMaker,
 		notificationEventLogRepo,
+		ratelimit.NewStoreLimiter(rateLimitStore, "verify_password", cfg.RateLimit.VerifyPassword.Limit, cfg.RateLimit.VerifyPassword.Window),
+		ratelimit.NewStoreLimiter(rateLimitStore, "login", cfg.RateLimit.Login.Limit, cfg.RateLimit.Login.Window),
+		ratelimit.NewStoreLimiter(rateLimitStore, "login_account", cfg.RateLimit.LoginAccount.Limit, cfg.RateLimit.LoginAccount.Window),
+		audit.NewLogger(),
+		repository.NewMFAChallengeRepository(db),
+		repository.NewMFABackupCodeRepository(db),
+		secretCipher,
+		repository.NewNotificationPreferenceRepository(db),
+		ratelimit.NewStoreLimiter(rateLimitStore, "refresh_token", cfg.RateLimit.RefreshToken.Limit, cfg.RateLimit.RefreshToken.Window),
+		repository.NewContactVerificationRepository(db),
+		webAuthn,
+		repository.NewWebAuthnCredentialRepository(db),
+		repository.NewWebAuthnSessionRepository(db),
 	)
-	userHandler := handler.NewUserHandler(userService)
+	diagnosticsService := service.NewDiagnosticsService(cfg, db.DB(), userService)
+	userHandler := handler.NewUserHandler(userService, diagnosticsService)
 
 	// Register services
 	pb.RegisterUserServiceServer(grpcServer, userHandler)
 
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	// The server reports SERVING once it starts accepting connections
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
 	// Enable reflection for development
 	reflection.Register(grpcServer)
 
@@ -108,6 +178,12 @@ func main() {
 		logger.Fatalf("Failed to listen: %v", err)
 	}
 
+	connectionAllowlist, err := netutil.ParseCIDRs(cfg.Server.ConnectionLimitAllowlist)
+	if err != nil {
+		logger.Fatalf("Invalid connection limit allowlist: %v", err)
+	}
+	lis = netutil.NewIPLimitListener(lis, cfg.Server.MaxConnectionsPerIP, connectionAllowlist, logger)
+
 	logger.WithFields(logrus.Fields{
 		"address":              grpcAddr,
 		"port":                 cfg.Server.Port,
@@ -117,7 +193,12 @@ func main() {
 		"jwt_access_duration":  cfg.JWT.AccessTokenDuration,
 		"jwt_refresh_duration": cfg.JWT.RefreshTokenDuration,
 		"log_level":            cfg.Log.Level,
+		"max_conns_per_ip":     cfg.Server.MaxConnectionsPerIP,
 		"reflection":           "enabled",
+		"version":              version.Get().String(),
+		"tls":                  cfg.Server.TLSEnabled(),
+		"require_tls":          cfg.Server.RequireTLS,
+		"webauthn":             cfg.WebAuthn.Enabled,
 	}).Info("gRPC server starting")
 
 	// Create main application context with cancellation
@@ -129,19 +210,42 @@ func main() {
 	var wg sync.WaitGroup
 
 	if cfg.Worker.Notification.Enabled {
-		asyncQClient := asynq.NewClient(asynq.RedisClientOpt{
-			Addr: fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
-		})
+		asyncQClient := asynq.NewClient(redisClientOpt(&cfg.Redis))
 		defer asyncQClient.Close()
 
+		// Verify connectivity with the configured credentials before starting the worker
+		if err := asyncQClient.Ping(); err != nil {
+			logger.Fatalf("Failed to connect to Redis at %s: %v", cfg.Redis.GetRedisAddr(), err)
+		}
+		logger.WithFields(logrus.Fields{
+			"address": cfg.Redis.GetRedisAddr(),
+			"db":      cfg.Redis.DB,
+		}).Info("Connected to Redis")
+
+		notifiers, err := notificationNotifiers(&cfg.Worker.Notification, asyncQClient, logger)
+		if err != nil {
+			logger.Fatalf("Invalid notification channel configuration: %v", err)
+		}
+
 		notificationWorker = workers.NewNotificationWorker(
 			logger,
-			asyncQClient,
 			notificationEventLogRepo,
 			&wg,
 			cfg.Worker.Notification.Interval,
-			cfg.Worker.Notification.MaxRetries,
 			cfg.Worker.Notification.BatchSize,
+			cfg.Worker.Notification.MaxBatchSize,
+			notifiers,
+			cfg.Worker.Notification.FailureThreshold,
+			healthServer,
+			workers.CleanupOptions{
+				Retention: cfg.Worker.Notification.Cleanup.Retention,
+				Interval:  cfg.Worker.Notification.Cleanup.Interval,
+				BatchSize: cfg.Worker.Notification.Cleanup.BatchSize,
+			},
+			notificationStartupOptions(&cfg.Worker.Notification, healthServer),
+			notificationTemplateOptions(&cfg.Worker.Notification),
+			cfg.Worker.Notification.SendTimeout,
+			notificationRetryOptions(&cfg.Worker.Notification),
 		)
 
 		// Start worker with application context
@@ -153,6 +257,7 @@ func main() {
 			"interval":    cfg.Worker.Notification.Interval,
 			"max_retries": cfg.Worker.Notification.MaxRetries,
 			"batch_size":  cfg.Worker.Notification.BatchSize,
+			"channels":    cfg.Worker.Notification.Channels,
 		}).Info("Notification worker started")
 	} else {
 		logger.Info("Notification worker disabled")
@@ -171,6 +276,7 @@ func main() {
 		}
 	}()
 
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
 	logger.Info("gRPC server is running and ready to accept connections")
 
 	// Wait for either shutdown signal or server error
@@ -218,3 +324,161 @@ func main() {
 		logger.Info("Forced shutdown completed")
 	}
 }
+
+// runStartupMigrations applies pending migrations, exiting on failure. A dirty database
+// is never repaired automatically: it needs the migrate tool and an operator.
+func runStartupMigrations(cfg *config.DatabaseConfig, logger *logrus.Logger) {
+	migrationConfig := migrate.Config{
+		DatabaseURL:    cfg.GetDSNURL(),
+		MigrationsPath: "./db/migrations",
+	}
+
+	fromVersion, dirty, err := migrate.GetMigrationStatus(migrationConfig)
+	if err != nil {
+		logger.Fatalf("Failed to get migration status: %v", err)
+	}
+	if dirty {
+		logger.Fatalf("Database is dirty at migration %d, repair it with the migrate tool before starting", fromVersion)
+	}
+
+	if err := migrate.RunMigrations(migrationConfig); err != nil {
+		logger.Fatalf("Failed to run database migrations: %v", err)
+	}
+
+	version, _, err := migrate.GetMigrationStatus(migrationConfig)
+	if err != nil {
+		logger.Fatalf("Failed to get migration status: %v", err)
+	}
+	logger.WithFields(logrus.Fields{
+		"from_version": fromVersion,
+		"version":      version,
+	}).Info("Database migrations completed successfully")
+}
+
+// redisClientOpt builds the asynq Redis connection options from configuration
+func redisClientOpt(cfg *config.RedisConfig) asynq.RedisClientOpt {
+	return asynq.RedisClientOpt{
+		Addr:         cfg.GetRedisAddr(),
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+}
+
+// newRateLimitStore creates the rate limit store selected by rate_limit.backend and a
+// function releasing it
+func newRateLimitStore(cfg *config.Config, logger *logrus.Logger) (ratelimit.Store, func()) {
+	if cfg.RateLimit.Backend != config.RateLimitBackendRedis {
+		return ratelimit.NewMemoryStore(), func() {}
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         cfg.Redis.GetRedisAddr(),
+		Password:     cfg.Redis.Password,
+		DB:           cfg.Redis.DB,
+		PoolSize:     cfg.Redis.PoolSize,
+		DialTimeout:  cfg.Redis.DialTimeout,
+		ReadTimeout:  cfg.Redis.ReadTimeout,
+		WriteTimeout: cfg.Redis.WriteTimeout,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Redis.DialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		logger.Fatalf("Failed to connect to rate limit Redis at %s: %v", cfg.Redis.GetRedisAddr(), err)
+	}
+	logger.WithField("address", cfg.Redis.GetRedisAddr()).Info("Using Redis rate limit store")
+
+	return ratelimit.NewRedisStore(client, "wallet-user-svc:ratelimit:"), func() {
+		if err := client.Close(); err != nil {
+			logger.WithError(err).Warn("Failed to close rate limit Redis client")
+		}
+	}
+}
+
+// notificationTaskOptions converts the per-event task configuration into worker task options
+func notificationTaskOptions(tasks map[string]config.NotificationTaskConfig) map[events.EventType]events.TaskOptions {
+	options := make(map[events.EventType]events.TaskOptions, len(tasks))
+	for eventType, task := range tasks {
+		options[events.EventType(eventType)] = events.TaskOptions{
+			ProcessIn: task.ProcessIn,
+			Timeout:   task.Timeout,
+			Deadline:  task.Deadline,
+			Unique:    task.Unique,
+		}
+	}
+	return options
+}
+
+// notificationStartupOptions gates the worker's first run on the configured delay and,
+// when wait_for_ready is set, on the server reporting SERVING
+func notificationStartupOptions(cfg *config.NotificationWorkerConfig, healthServer *health.Server) workers.StartupOptions {
+	options := workers.StartupOptions{Delay: cfg.StartupDelay}
+	if cfg.WaitForReady {
+		options.Readiness = healthServer
+	}
+	return options
+}
+
+// notificationTemplateOptions converts the configured sender and templates into worker options
+func notificationTemplateOptions(cfg *config.NotificationWorkerConfig) workers.TemplateOptions {
+	templates := make(map[events.EventType]string, len(cfg.Templates))
+	for eventType, templateID := range cfg.Templates {
+		templates[events.EventType(eventType)] = templateID
+	}
+	return workers.TemplateOptions{
+		SenderEmail: cfg.Sender.Email,
+		SenderName:  cfg.Sender.Name,
+		Templates:   templates,
+	}
+}
+
+// notificationRetryOptions converts the retry sweeper configuration into worker options;
+// the zero options of a disabled sweeper leave failed events pending
+func notificationRetryOptions(cfg *config.NotificationWorkerConfig) workers.RetryOptions {
+	if !cfg.Retry.Enabled {
+		return workers.RetryOptions{}
+	}
+	return workers.RetryOptions{
+		Interval:    cfg.Retry.Interval,
+		MaxAttempts: cfg.Retry.MaxAttempts,
+		Backoff:     cfg.Retry.Backoff,
+		BatchSize:   cfg.Retry.BatchSize,
+	}
+}
+
+// notificationNotifiers builds the notifiers for each event type from the configured channels
+func notificationNotifiers(
+	cfg *config.NotificationWorkerConfig,
+	asyncQClient *asynq.Client,
+	logger *logrus.Logger,
+) (map[events.EventType][]workers.Notifier, error) {
+	taskOptions := notificationTaskOptions(cfg.Tasks)
+
+	notifiers := make(map[events.EventType][]workers.Notifier, len(cfg.Channels))
+	for eventType, channels := range cfg.Channels {
+		for _, channel := range channels {
+			var notifier workers.Notifier
+			switch channel {
+			case workers.ChannelEmail:
+				notifier = workers.NewEmailNotifier(asyncQClient, cfg.Email.Queue, cfg.MaxRetries, taskOptions, logger)
+			case workers.ChannelSMS:
+				notifier = workers.NewSMSNotifier(asyncQClient, cfg.SMS.Queue, cfg.MaxRetries, taskOptions, logger)
+			case workers.ChannelWebhook:
+				if cfg.Webhook.URL == "" {
+					return nil, fmt.Errorf("event %q uses the webhook channel but no webhook url is configured", eventType)
+				}
+				notifier = workers.NewWebhookNotifier(cfg.Webhook.URL, cfg.Webhook.Timeout, logger)
+			case workers.ChannelNoop:
+				notifier = workers.NopNotifier{}
+			default:
+				return nil, fmt.Errorf("event %q has unknown notification channel %q", eventType, channel)
+			}
+			notifiers[events.EventType(eventType)] = append(notifiers[events.EventType(eventType)], notifier)
+		}
+	}
+	return notifiers, nil
+}
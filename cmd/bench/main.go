@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"wallet-user-svc/pkg/utils/crypt/password"
+)
+
+func main() {
+	var (
+		target = flag.Duration("target", 250*time.Millisecond, "Maximum acceptable hashing latency per password (e.g., 250ms)")
+	)
+	flag.Parse()
+
+	if *target <= 0 {
+		log.Fatal("Target latency must be positive. Use -target flag")
+	}
+
+	fmt.Printf("Measuring bcrypt cost for target latency %s...\n", *target)
+
+	cost := password.BenchmarkCost(*target)
+
+	hasher := password.NewHasher(cost)
+	start := time.Now()
+	if _, err := hasher.HashPassword("BenchmarkPassword123!"); err != nil {
+		log.Fatalf("Failed to hash password with cost %d: %v", cost, err)
+	}
+
+	fmt.Printf("Recommended bcrypt cost: %d (measured %s per hash)\n", cost, time.Since(start))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"wallet-user-svc/db"
+	"wallet-user-svc/internal/app/config"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/repository"
+	passwordutils "wallet-user-svc/pkg/utils/crypt/password"
+)
+
+// devDatabaseMarkers are substrings identifying a database name as safe to seed
+var devDatabaseMarkers = []string{"dev", "local", "test"}
+
+func main() {
+	var (
+		configPath = flag.String("config", "config.yaml", "Path to the configuration file")
+		count      = flag.Int("count", 10, "Number of users to create")
+		password   = flag.String("password", "Password123!", "Password shared by all seeded users")
+		domainName = flag.String("domain", "example.com", "Email domain of seeded users")
+		force      = flag.Bool("force", false, "Seed even if the database name does not look like a development database")
+	)
+	flag.Parse()
+
+	if *count <= 0 {
+		log.Fatal("Count must be positive. Use -count flag")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if !*force && !isDevDatabase(cfg.Database.DBName) {
+		log.Fatalf("Refusing to seed database %q: its name does not contain any of %v. Use -force to seed it anyway",
+			cfg.Database.DBName, devDatabaseMarkers)
+	}
+
+	store, err := db.NewStore(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to create database store: %v", err)
+	}
+	defer store.Close()
+
+	userRepo := repository.NewUserRepository(store, cfg.Registration.UniqueUsernames)
+	// Seeded users must sign in through the service, so hash with its pepper
+	hasher := passwordutils.DefaultHasher().WithPepper(cfg.Password.Pepper)
+	ctx := context.Background()
+
+	created, skipped := 0, 0
+	for i := 1; i <= *count; i++ {
+		email := fmt.Sprintf("user%03d@%s", i, *domainName)
+		username := fmt.Sprintf("devuser%03d", i)
+
+		_, err := userRepo.GetByEmail(ctx, email)
+		if err == nil {
+			skipped++
+			continue
+		}
+		if !errors.Is(err, errs.ErrUserNotFound) {
+			log.Fatalf("Failed to look up %s: %v", email, err)
+		}
+
+		user, err := domain.NewUserWithPassword(hasher, &email, *password, username, nil, nil)
+		if err != nil {
+			log.Fatalf("Failed to build user %s: %v", email, err)
+		}
+		if err := userRepo.Create(ctx, user); err != nil {
+			log.Fatalf("Failed to create user %s: %v", email, err)
+		}
+		created++
+	}
+
+	fmt.Printf("Seeded %d users (%d already existed), password %q\n", created, skipped, *password)
+}
+
+// isDevDatabase reports whether a database name marks it as a development database
+func isDevDatabase(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range devDatabaseMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDevDatabase(t *testing.T) {
+	assert.True(t, isDevDatabase("wallet-user-svc-dev"))
+	assert.True(t, isDevDatabase("wallet_local"))
+	assert.True(t, isDevDatabase("USER_SVC_TEST"))
+	assert.False(t, isDevDatabase("wallet-user-svc"))
+	assert.False(t, isDevDatabase("wallet_prod"))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"wallet-user-svc/pkg/utils/crypt/token"
+)
+
+// decodedToken is the printed form of a token's claims
+type decodedToken struct {
+	*token.Payload
+	IssuedAtTime  time.Time `json:"issued_at_time"`
+	ExpiredAtTime time.Time `json:"expired_at_time"`
+	Expired       bool      `json:"expired"`
+}
+
+func main() {
+	var (
+		tokenString = flag.String("token", "", "JWT to decode; read from stdin when empty")
+	)
+	flag.Parse()
+
+	raw := strings.TrimSpace(*tokenString)
+	if raw == "" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatal("Token is required. Use -token flag or pipe it on stdin")
+		}
+		raw = strings.TrimSpace(line)
+	}
+	raw = strings.TrimPrefix(raw, "Bearer ")
+
+	payload, err := token.ParseUnverified(raw)
+	if err != nil {
+		log.Fatalf("Failed to decode token: %v", err)
+	}
+
+	decoded := decodedToken{
+		Payload:       payload,
+		IssuedAtTime:  time.Unix(payload.IssuedAt, 0).UTC(),
+		ExpiredAtTime: time.Unix(payload.ExpiredAt, 0).UTC(),
+		Expired:       time.Now().After(time.Unix(payload.ExpiredAt, 0)),
+	}
+	output, err := json.MarshalIndent(decoded, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode claims: %v", err)
+	}
+
+	fmt.Fprintln(os.Stderr, "WARNING: the signature was NOT verified; do not trust these claims for access decisions")
+	fmt.Println(string(output))
+}
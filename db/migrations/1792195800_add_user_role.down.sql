@@ -0,0 +1,2 @@
+-- Remove role from users table
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,2 @@
+-- Add role to users table for authorization of privileged RPCs
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
@@ -0,0 +1,12 @@
+-- Restore the original lookup indexes
+CREATE INDEX IF NOT EXISTS idx_notification_event_logs_event_name_status ON notification_event_logs(event_name, status);
+DROP INDEX IF EXISTS idx_notification_event_logs_event_name_status_created_at;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token);
+DROP INDEX IF EXISTS idx_refresh_tokens_token;
+
+CREATE INDEX IF NOT EXISTS idx_users_country_code_phone ON users(country_code, phone);
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+
+DROP INDEX IF EXISTS idx_users_country_code_phone_unique;
+DROP INDEX IF EXISTS idx_users_email_unique;
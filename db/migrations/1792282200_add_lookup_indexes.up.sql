@@ -0,0 +1,17 @@
+-- Add indexes backing the user, refresh token and notification event lookups
+
+-- Unique lookups for users by email and by phone number
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_unique ON users(email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_country_code_phone_unique ON users(country_code, phone);
+
+-- The unique indexes above supersede the plain lookup indexes
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_country_code_phone;
+
+-- Refresh token lookup by token value
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
+DROP INDEX IF EXISTS idx_refresh_tokens_token_hash;
+
+-- Pending event polling filters by event name and status, ordered by creation time
+CREATE INDEX IF NOT EXISTS idx_notification_event_logs_event_name_status_created_at ON notification_event_logs(event_name, status, created_at);
+DROP INDEX IF EXISTS idx_notification_event_logs_event_name_status;
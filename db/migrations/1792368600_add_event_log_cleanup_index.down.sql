@@ -0,0 +1,2 @@
+-- Remove the published event pruning index
+DROP INDEX IF EXISTS idx_notification_event_logs_status_updated_at;
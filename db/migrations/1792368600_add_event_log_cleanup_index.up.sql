@@ -0,0 +1,2 @@
+-- Support pruning of published events by status and age
+CREATE INDEX IF NOT EXISTS idx_notification_event_logs_status_updated_at ON notification_event_logs(status, updated_at);
@@ -0,0 +1,4 @@
+-- Remove TOTP multi-factor authentication
+DROP TABLE IF EXISTS mfa_challenges;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
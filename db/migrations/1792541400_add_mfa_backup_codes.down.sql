@@ -0,0 +1,2 @@
+-- Remove two-factor backup codes
+DROP TABLE IF EXISTS mfa_backup_codes;
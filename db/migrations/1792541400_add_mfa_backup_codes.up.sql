@@ -0,0 +1,11 @@
+-- Single-use backup codes for two-factor recovery; only SHA-256 hashes are stored
+CREATE TABLE IF NOT EXISTS mfa_backup_codes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at BIGINT,
+    created_at BIGINT DEFAULT (EXTRACT(EPOCH FROM NOW()) * 1000),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mfa_backup_codes_user_id_code_hash ON mfa_backup_codes(user_id, code_hash);
@@ -0,0 +1,14 @@
+-- Usernames are unique case-insensitively when registration.unique_usernames is enabled.
+-- unique_username holds the lowercased username for users registered under that rule and
+-- stays NULL otherwise, so deployments keying only on email/phone are unaffected.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS unique_username VARCHAR(100);
+
+-- Backfill users whose username is not shared; pre-existing duplicates stay NULL
+UPDATE users u
+SET unique_username = LOWER(u.username)
+WHERE NOT EXISTS (
+    SELECT 1 FROM users o
+    WHERE LOWER(o.username) = LOWER(u.username) AND o.id <> u.id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_unique_username ON users(unique_username);
@@ -0,0 +1,3 @@
+-- SHA-256 hex of the user agent and device ID of the client a refresh token was issued
+-- to. Tokens issued before this migration stay NULL and are not bound to a device.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS fingerprint_hash VARCHAR(64);
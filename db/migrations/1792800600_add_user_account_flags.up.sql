@@ -0,0 +1,4 @@
+-- Flags set when an admin provisions an account: a pre-verified email, and a temporary
+-- password the user has to replace after signing in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
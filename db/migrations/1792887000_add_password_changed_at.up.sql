@@ -0,0 +1,6 @@
+-- When the password was last set, in Unix milliseconds; drives the optional password
+-- expiry policy. Existing users count from their account creation.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at BIGINT;
+UPDATE users SET password_changed_at = created_at WHERE password_changed_at IS NULL;
+ALTER TABLE users ALTER COLUMN password_changed_at SET NOT NULL;
+ALTER TABLE users ALTER COLUMN password_changed_at SET DEFAULT (EXTRACT(EPOCH FROM NOW()) * 1000);
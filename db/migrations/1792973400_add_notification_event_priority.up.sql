@@ -0,0 +1,3 @@
+-- Processing priority of a notification event; higher values are published first.
+-- Existing events keep the default priority.
+ALTER TABLE notification_event_logs ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Remove per-user notification preferences
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,15 @@
+-- Per-user opt-outs of notification event types; a missing row means the type's default applies
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at BIGINT DEFAULT (EXTRACT(EPOCH FROM NOW()) * 1000),
+    updated_at BIGINT DEFAULT (EXTRACT(EPOCH FROM NOW()) * 1000),
+    PRIMARY KEY (user_id, event_type),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER update_notification_preferences_updated_at
+    BEFORE UPDATE ON notification_preferences
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
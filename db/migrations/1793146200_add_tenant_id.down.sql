@@ -0,0 +1,12 @@
+-- Restore global uniqueness; fails while two tenants share an email, phone number or username
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_unique_username ON users(unique_username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_country_code_phone_unique ON users(country_code, phone);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_unique ON users(email);
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+DROP INDEX IF EXISTS idx_users_tenant_unique_username;
+DROP INDEX IF EXISTS idx_users_tenant_country_code_phone_unique;
+DROP INDEX IF EXISTS idx_users_tenant_email_unique;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_id;
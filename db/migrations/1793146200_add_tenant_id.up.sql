@@ -0,0 +1,16 @@
+-- Scope users and refresh tokens to a tenant when tenancy.enabled is set. Rows of
+-- single-tenant deployments keep the empty default tenant.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Email, phone number and username are unique per tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_email_unique ON users(tenant_id, email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_country_code_phone_unique ON users(tenant_id, country_code, phone);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_unique_username ON users(tenant_id, unique_username);
+
+-- The per-tenant indexes above supersede the global ones, including the email
+-- constraint created with the users table
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+DROP INDEX IF EXISTS idx_users_email_unique;
+DROP INDEX IF EXISTS idx_users_country_code_phone_unique;
+DROP INDEX IF EXISTS idx_users_unique_username;
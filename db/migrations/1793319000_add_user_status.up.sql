@@ -0,0 +1,3 @@
+-- Lifecycle state of an account: active, disabled or locked. Only active accounts
+-- can sign in.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active';
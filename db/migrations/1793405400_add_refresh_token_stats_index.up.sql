@@ -0,0 +1,3 @@
+-- Covers the refresh token stats queries, which count tokens by state overall and per
+-- user, so they are answered from the index alone
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_tenant_user_state ON refresh_tokens(tenant_id, user_id, is_revoked, expires_at);
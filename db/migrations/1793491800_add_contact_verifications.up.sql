@@ -0,0 +1,23 @@
+-- The contact notifications and account recovery go to: email or phone. Existing users
+-- without an email can only be reached by phone.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS primary_contact VARCHAR(16) NOT NULL DEFAULT 'email';
+UPDATE users SET primary_contact = 'phone' WHERE email IS NULL;
+
+-- Pending email or phone changes, applied once the code sent to the new contact is entered
+CREATE TABLE IF NOT EXISTS contact_verifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    method VARCHAR(16) NOT NULL,
+    email VARCHAR(255),
+    country_code VARCHAR(5),
+    phone VARCHAR(15),
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at BIGINT NOT NULL,
+    consumed_at BIGINT,
+    created_at BIGINT DEFAULT (EXTRACT(EPOCH FROM NOW()) * 1000),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_verifications_user_id ON contact_verifications(user_id);
+CREATE INDEX IF NOT EXISTS idx_contact_verifications_expires_at ON contact_verifications(expires_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notification_event_logs_status_next_retry_at;
+ALTER TABLE notification_event_logs DROP COLUMN IF EXISTS next_retry_at;
+ALTER TABLE notification_event_logs DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,6 @@
+-- Failed send attempts of a notification event and when the retry sweeper may try it
+-- again. Existing failed events have no retry time and are left alone.
+ALTER TABLE notification_event_logs ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notification_event_logs ADD COLUMN IF NOT EXISTS next_retry_at BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_notification_event_logs_status_next_retry_at ON notification_event_logs(status, next_retry_at);
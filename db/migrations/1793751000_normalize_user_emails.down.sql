@@ -0,0 +1,2 @@
+-- The original case of normalized emails is not kept, so this migration cannot be undone
+SELECT 1;
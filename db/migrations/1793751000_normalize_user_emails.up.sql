@@ -0,0 +1,25 @@
+-- Emails are stored and looked up trimmed and lowercased. Normalize emails stored before
+-- that. Emails that would then clash with another user's in the tenant cannot be merged
+-- automatically, so the migration fails, listing the clashing user IDs, before changing anything.
+DO $$
+DECLARE
+    clashes TEXT;
+BEGIN
+    SELECT string_agg(format('tenant %L email %L: %s', tenant_id, normalized, ids), '; ')
+    INTO clashes
+    FROM (
+        SELECT tenant_id, LOWER(TRIM(email)) AS normalized, string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+        FROM users
+        WHERE email IS NOT NULL
+        GROUP BY tenant_id, LOWER(TRIM(email))
+        HAVING COUNT(*) > 1
+    ) c;
+
+    IF clashes IS NOT NULL THEN
+        RAISE EXCEPTION 'users share an email once normalized, resolve them before migrating: %', clashes;
+    END IF;
+END $$;
+
+UPDATE users
+SET email = LOWER(TRIM(email))
+WHERE email <> LOWER(TRIM(email));
@@ -0,0 +1,2 @@
+-- Fails while phone-only users exist; give them an email or remove them first
+ALTER TABLE users ALTER COLUMN email SET NOT NULL;
@@ -0,0 +1,3 @@
+-- Users may register with a phone number only, so email is no longer required.
+-- The unique (tenant_id, email) index allows any number of NULL emails.
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
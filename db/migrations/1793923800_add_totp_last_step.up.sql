@@ -0,0 +1,3 @@
+-- The last TOTP time step (Unix time / 30s) a code was accepted for. Codes of that step
+-- or an earlier one are rejected, so an observed code cannot be replayed.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
@@ -0,0 +1 @@
+ALTER TABLE notification_event_logs DROP COLUMN IF EXISTS delivered_channels;
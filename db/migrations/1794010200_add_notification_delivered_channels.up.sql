@@ -0,0 +1,3 @@
+-- Channels each notification event was delivered to, so a retry resends only to the
+-- channels that failed instead of every configured one
+ALTER TABLE notification_event_logs ADD COLUMN IF NOT EXISTS delivered_channels TEXT[] NOT NULL DEFAULT '{}';
@@ -2,20 +2,37 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	Log      LogConfig      `mapstructure:"log"`
-	Worker   WorkerConfig   `mapstructure:"worker"`
+	Server       ServerConfig       `mapstructure:"server"`
+	Database     DatabaseConfig     `mapstructure:"database"`
+	JWT          JWTConfig          `mapstructure:"jwt"`
+	Redis        RedisConfig        `mapstructure:"redis"`
+	Log          LogConfig          `mapstructure:"log"`
+	Worker       WorkerConfig       `mapstructure:"worker"`
+	RateLimit    RateLimitConfig    `mapstructure:"rate_limit"`
+	MFA          MFAConfig          `mapstructure:"mfa"`
+	Registration RegistrationConfig `mapstructure:"registration"`
+	Password     PasswordConfig     `mapstructure:"password"`
+	Phone        PhoneConfig        `mapstructure:"phone"`
+	Contact      ContactConfig      `mapstructure:"contact"`
+	ServiceAuth  ServiceAuthConfig  `mapstructure:"service_auth"`
+	Tenancy      TenancyConfig      `mapstructure:"tenancy"`
+	Errors       ErrorsConfig       `mapstructure:"errors"`
+	WebAuthn     WebAuthnConfig     `mapstructure:"webauthn"`
 }
 
 // ServerConfig holds server configuration
@@ -25,6 +42,31 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	// MaxConnectionsPerIP caps concurrent connections per client IP (0 disables the limit)
+	MaxConnectionsPerIP int `mapstructure:"max_connections_per_ip"`
+	// ConnectionLimitAllowlist lists CIDRs (or IPs) exempt from the per-IP limit
+	ConnectionLimitAllowlist []string `mapstructure:"connection_limit_allowlist"`
+	// TrustedProxies lists CIDRs (or IPs) of proxies whose x-forwarded-for and
+	// x-real-ip headers are trusted to carry the client IP
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// SlowRequestThreshold logs a warning for requests slower than this (0 disables)
+	SlowRequestThreshold time.Duration `mapstructure:"slow_request_threshold"`
+	// EnableCompression gzip-compresses responses for clients that accept gzip, trading
+	// CPU for bandwidth
+	EnableCompression bool `mapstructure:"enable_compression"`
+	// ExposePanicDetails returns the value of a recovered panic to the client. Only
+	// meant for non-production environments, responses are opaque otherwise.
+	ExposePanicDetails bool `mapstructure:"expose_panic_details"`
+	// TLSCertFile and TLSKeyFile are the PEM certificate chain and private key the server
+	// terminates TLS with. Both or neither must be set; without them it serves plaintext.
+	TLSCertFile string `mapstructure:"tls_cert_file"`
+	TLSKeyFile  string `mapstructure:"tls_key_file"`
+	// RequireTLS rejects every request not received over TLS with HTTP/2 negotiated
+	// through ALPN. It needs TLSCertFile and TLSKeyFile; health checks are exempt.
+	RequireTLS bool `mapstructure:"require_tls"`
+	// SkipValidationMethods lists full gRPC method names whose requests are passed to the
+	// handler without the request validation every other method gets
+	SkipValidationMethods []string `mapstructure:"skip_validation_methods"`
 }
 
 // DatabaseConfig holds database configuration
@@ -35,13 +77,156 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"db_name"`
 	SSLMode  string `mapstructure:"ssl_mode"`
+	// AutoMigrate applies pending migrations at startup. Off by default so production
+	// deployments run migrations explicitly with the migrate tool.
+	AutoMigrate bool `mapstructure:"auto_migrate"`
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	SecretKey            string        `mapstructure:"secret_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	// PreviousSecretKeys still verify tokens signed before secret_key was rotated. New tokens
+	// are only signed with SecretKey. Empty the list once all tokens issued under the old
+	// secrets have expired, i.e. after refresh_token_duration.
+	PreviousSecretKeys   []string      `mapstructure:"previous_secret_keys"`
 	AccessTokenDuration  time.Duration `mapstructure:"access_token_duration"`
 	RefreshTokenDuration time.Duration `mapstructure:"refresh_token_duration"`
+	// Leeway tolerates clock skew between services when checking token expiry and issue times
+	Leeway time.Duration `mapstructure:"leeway"`
+	// ReadOnlyGrace accepts access tokens that expired at most this long ago on read-only
+	// methods, to smooth over clients racing their token refresh. 0 keeps every method strict.
+	ReadOnlyGrace time.Duration `mapstructure:"read_only_grace"`
+	// BindRefreshTokens rejects a refresh token presented by a client whose user agent
+	// and device ID differ from the ones it was issued to. Off by default because
+	// legitimate clients may change either, e.g. on an app update.
+	BindRefreshTokens bool `mapstructure:"bind_refresh_tokens"`
+}
+
+// MFAConfig holds two-factor authentication configuration
+type MFAConfig struct {
+	// Issuer is the account issuer shown in authenticator apps
+	Issuer string `mapstructure:"issuer"`
+	// EncryptionKey encrypts TOTP secrets at rest
+	EncryptionKey string `mapstructure:"encryption_key"`
+	// PreviousEncryptionKeys still decrypt secrets stored before encryption_key was rotated.
+	// Empty the list once ReEncryptSecrets has reported done with nothing failed; the
+	// call re-encrypts the secrets of every tenant.
+	PreviousEncryptionKeys []string `mapstructure:"previous_encryption_keys"`
+	// ChallengeTTL is how long a login MFA challenge can be completed
+	ChallengeTTL time.Duration `mapstructure:"challenge_ttl"`
+	// BackupCodeCount is the number of recovery codes generated per user
+	BackupCodeCount int `mapstructure:"backup_code_count"`
+	// BackupCodeLowThreshold flags logins once this few unused codes remain
+	BackupCodeLowThreshold int `mapstructure:"backup_code_low_threshold"`
+}
+
+// ServiceAuthConfig holds the service tokens accepted on internal RPCs
+type ServiceAuthConfig struct {
+	// Tokens maps each internal caller to the hex encoded SHA-256 hashes of its tokens;
+	// several tokens per caller allow rotation
+	Tokens map[string][]string `mapstructure:"tokens"`
+}
+
+// TenancyConfig holds multi-tenant isolation settings
+type TenancyConfig struct {
+	// Enabled requires every request to name its tenant in the x-tenant-id metadata and
+	// scopes users and refresh tokens to it; single-tenant deployments leave it off
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// ErrorsConfig holds error reporting settings
+type ErrorsConfig struct {
+	// CaptureStack records stack traces on errors created at runtime and on recovered
+	// panics and logs them. Useful in development; production leaves it off to save the
+	// overhead and keep internals out of logs.
+	CaptureStack bool `mapstructure:"capture_stack"`
+}
+
+// WebAuthnConfig holds passkey sign-in settings
+type WebAuthnConfig struct {
+	// Enabled serves the WebAuthn registration and login RPCs, which otherwise return
+	// UNIMPLEMENTED. Password sign-in is unaffected either way.
+	Enabled bool `mapstructure:"enabled"`
+	// RPID is the relying party ID, the domain passkeys are bound to, e.g. example.com
+	RPID string `mapstructure:"rp_id"`
+	// RPDisplayName is the service name authenticators show when creating a passkey
+	RPDisplayName string `mapstructure:"rp_display_name"`
+	// RPOrigins lists the web origins ceremonies may come from, e.g. https://wallet.example.com
+	RPOrigins []string `mapstructure:"rp_origins"`
+	// SessionTTL is how long a begun registration or login can be finished
+	SessionTTL time.Duration `mapstructure:"session_ttl"`
+}
+
+// RegistrationConfig holds user registration rules
+type RegistrationConfig struct {
+	// Enabled allows public self-registration; disable it for invite-only deployments,
+	// where accounts are created by admins through CreateUser
+	Enabled bool `mapstructure:"enabled"`
+	// UniqueUsernames rejects usernames already taken (case-insensitively); disable for
+	// deployments that identify users only by email or phone
+	UniqueUsernames bool `mapstructure:"unique_usernames"`
+	// PrimaryContact is the primary contact, "email" or "phone", of users registering
+	// with both that do not choose one
+	PrimaryContact string `mapstructure:"primary_contact"`
+}
+
+// PasswordConfig holds the password policy
+type PasswordConfig struct {
+	// MaxAge is how long a password stays valid before Login reports it as expired;
+	// 0 disables expiry
+	MaxAge time.Duration `mapstructure:"max_age"`
+	// MaxConcurrentHashes bounds how many passwords are hashed at once, so a burst of
+	// registrations cannot take the CPU away from other RPCs; 0 removes the bound
+	MaxConcurrentHashes int `mapstructure:"max_concurrent_hashes"`
+	// HashQueueTimeout is how long a registration waits for a hashing slot before it is
+	// rejected with ResourceExhausted; 0 rejects it at once
+	HashQueueTimeout time.Duration `mapstructure:"hash_queue_timeout"`
+	// Pepper is a server-side secret mixed into every password before hashing, so a
+	// leaked database alone is not enough to crack hashes offline; empty disables it.
+	// Hashes only verify with the pepper they were created with, so changing it requires
+	// every user to reset their password.
+	Pepper string `mapstructure:"pepper"`
+}
+
+// PhoneConfig holds phone number input handling
+type PhoneConfig struct {
+	// DefaultDialingCode (e.g. "+886") is prefixed to phone numbers sent without the E.164
+	// "+" prefix; empty requires clients to send E.164 numbers
+	DefaultDialingCode string `mapstructure:"default_dialing_code"`
+}
+
+// ContactConfig holds the verification of contacts added after registration
+type ContactConfig struct {
+	// VerificationTTL is how long the code sent to a new email or phone stays valid
+	VerificationTTL time.Duration `mapstructure:"verification_ttl"`
+}
+
+// RateLimitConfig holds per-operation rate limits
+type RateLimitConfig struct {
+	// Backend stores the rate limit counters: "memory" keeps them per replica, "redis"
+	// shares them across replicas through the configured Redis
+	Backend        string        `mapstructure:"backend"`
+	VerifyPassword RateLimitRule `mapstructure:"verify_password"`
+	// Login limits login attempts per client IP
+	Login RateLimitRule `mapstructure:"login"`
+	// LoginAccount limits login attempts per account, keyed by a hash of the email or
+	// phone; it is checked after Login and independently of it
+	LoginAccount RateLimitRule `mapstructure:"login_account"`
+	// RefreshToken limits failed token refreshes per client IP and per user; once
+	// reached, further refreshes are rejected until the window passes
+	RefreshToken RateLimitRule `mapstructure:"refresh_token"`
+}
+
+// Rate limit backends
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+)
+
+// RateLimitRule allows Limit events per key within each Window; a zero Limit disables it
+type RateLimitRule struct {
+	Limit  int           `mapstructure:"limit"`
+	Window time.Duration `mapstructure:"window"`
 }
 
 // RedisConfig holds Redis configuration
@@ -50,12 +235,23 @@ type RedisConfig struct {
 	Port     int    `mapstructure:"port"`
 	Password string `mapstructure:"password"`
 	DB       int    `mapstructure:"db"`
+	// PoolSize is the maximum number of socket connections; 0 uses the client default
+	PoolSize     int           `mapstructure:"pool_size"`
+	DialTimeout  time.Duration `mapstructure:"dial_timeout"`
+	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 }
 
 // LogConfig holds logging configuration
 type LogConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
+	// LogPayloads logs the requests of PayloadMethods at Debug level, with secrets
+	// redacted and emails and phone numbers masked; meant for debugging only
+	LogPayloads bool `mapstructure:"log_payloads"`
+	// PayloadMethods lists the full gRPC method names (e.g. /user.UserService/Register)
+	// whose requests are logged
+	PayloadMethods []string `mapstructure:"payload_methods"`
 }
 
 // WorkerConfig holds notification worker configuration
@@ -70,6 +266,96 @@ type NotificationWorkerConfig struct {
 	MaxRetries  int           `mapstructure:"max_retries"`
 	BatchSize   int           `mapstructure:"batch_size"`
 	Concurrency int           `mapstructure:"concurrency"`
+	// MaxBatchSize caps BatchSize; larger values are clamped with a warning
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+	// FailureThreshold is the number of consecutive failed polls after which the
+	// worker reports itself unhealthy; 0 disables escalation
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// Strict fails the triggering operation (e.g. Login) when its notification event
+	// cannot be recorded; by default the failure is logged and the operation succeeds
+	Strict bool `mapstructure:"strict"`
+	// SendTimeout bounds each attempt to send a notification; an attempt that runs out
+	// of time fails like any other, leaving the event pending for the next poll or to
+	// the retry sweeper
+	SendTimeout time.Duration `mapstructure:"send_timeout"`
+	// Cleanup prunes successfully published events after a retention period
+	Cleanup NotificationCleanupConfig `mapstructure:"cleanup"`
+	// PriorityAging boosts the priority of events left pending for too long
+	PriorityAging NotificationPriorityAgingConfig `mapstructure:"priority_aging"`
+	// Retry moves failed events to a sweeper that retries them with a backoff
+	Retry NotificationRetryConfig `mapstructure:"retry"`
+	// Tasks holds per-event task options keyed by event type (e.g. "login")
+	Tasks map[string]NotificationTaskConfig `mapstructure:"tasks"`
+	// Channels lists the notification channels each event type is dispatched to
+	// (e.g. "login": ["email", "webhook"])
+	Channels map[string][]string     `mapstructure:"channels"`
+	Email    NotificationQueueConfig `mapstructure:"email"`
+	SMS      NotificationQueueConfig `mapstructure:"sms"`
+	Webhook  WebhookConfig           `mapstructure:"webhook"`
+	// Sender is the identity notifications are sent from
+	Sender NotificationSenderConfig `mapstructure:"sender"`
+	// Templates maps event types to the template the notification consumer renders
+	Templates map[string]string `mapstructure:"templates"`
+	// StartupDelay postpones the first processing run after the worker starts
+	StartupDelay time.Duration `mapstructure:"startup_delay"`
+	// WaitForReady holds the first processing run until the gRPC health service
+	// reports the server as SERVING
+	WaitForReady bool `mapstructure:"wait_for_ready"`
+}
+
+// NotificationCleanupConfig holds published event retention configuration
+type NotificationCleanupConfig struct {
+	// Retention is how long published events are kept; 0 disables the cleanup
+	Retention time.Duration `mapstructure:"retention"`
+	Interval  time.Duration `mapstructure:"interval"`
+	BatchSize int           `mapstructure:"batch_size"`
+}
+
+// NotificationPriorityAgingConfig holds the age-based priority boost that keeps
+// low-priority events from starving under sustained high-priority load
+type NotificationPriorityAgingConfig struct {
+	// After is the age from which a pending event is boosted; 0 disables aging
+	After time.Duration `mapstructure:"after"`
+	// Boost is added to the priority of events older than After when ordering
+	Boost int `mapstructure:"boost"`
+}
+
+// NotificationRetryConfig holds the retry sweeper of failed events. When disabled a
+// failed send leaves its event pending and the next poll tries it again.
+type NotificationRetryConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Interval is how often failed events due for a retry are looked up
+	Interval time.Duration `mapstructure:"interval"`
+	// MaxAttempts is the number of failed attempts after which an event stays failed
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// Backoff is the wait before the first retry; it doubles with every further failure
+	Backoff   time.Duration `mapstructure:"backoff"`
+	BatchSize int           `mapstructure:"batch_size"`
+}
+
+// NotificationQueueConfig holds the asynq queue used by a task based notification channel
+type NotificationQueueConfig struct {
+	Queue string `mapstructure:"queue"`
+}
+
+// NotificationSenderConfig holds the sender identity attached to notification tasks
+type NotificationSenderConfig struct {
+	Email string `mapstructure:"email"`
+	Name  string `mapstructure:"name"`
+}
+
+// WebhookConfig holds webhook notification channel configuration
+type WebhookConfig struct {
+	URL     string        `mapstructure:"url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// NotificationTaskConfig holds asynq scheduling options for a single event type
+type NotificationTaskConfig struct {
+	ProcessIn time.Duration `mapstructure:"process_in"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+	Deadline  time.Duration `mapstructure:"deadline"`
+	Unique    time.Duration `mapstructure:"unique"`
 }
 
 // LoadConfig loads configuration using Viper
@@ -108,6 +394,16 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.read_timeout", "30s")
 	v.SetDefault("server.write_timeout", "30s")
 	v.SetDefault("server.idle_timeout", "60s")
+	v.SetDefault("server.max_connections_per_ip", 0)
+	v.SetDefault("server.connection_limit_allowlist", []string{})
+	v.SetDefault("server.trusted_proxies", []string{})
+	v.SetDefault("server.slow_request_threshold", "1s")
+	v.SetDefault("server.enable_compression", true)
+	v.SetDefault("server.expose_panic_details", false)
+	v.SetDefault("server.tls_cert_file", "")
+	v.SetDefault("server.tls_key_file", "")
+	v.SetDefault("server.require_tls", false)
+	v.SetDefault("server.skip_validation_methods", []string{})
 
 	// Database defaults
 	v.SetDefault("database.host", "localhost")
@@ -116,28 +412,122 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.password", "password")
 	v.SetDefault("database.db_name", "user_svc")
 	v.SetDefault("database.ssl_mode", "disable")
+	v.SetDefault("database.auto_migrate", false)
 
 	// JWT defaults
 	v.SetDefault("jwt.secret_key", "your-secret-key-change-in-production")
+	v.SetDefault("jwt.previous_secret_keys", []string{})
 	v.SetDefault("jwt.access_token_duration", "15m")
 	v.SetDefault("jwt.refresh_token_duration", "168h") // 7 days
+	v.SetDefault("jwt.leeway", "30s")
+	v.SetDefault("jwt.read_only_grace", "0s")
+	v.SetDefault("jwt.bind_refresh_tokens", false)
+
+	// MFA defaults
+	v.SetDefault("mfa.issuer", "wallet-user-svc")
+	v.SetDefault("mfa.encryption_key", "your-mfa-encryption-key-change-in-production")
+	v.SetDefault("mfa.previous_encryption_keys", []string{})
+	v.SetDefault("mfa.challenge_ttl", "5m")
+	v.SetDefault("mfa.backup_code_count", 10)
+	v.SetDefault("mfa.backup_code_low_threshold", 3)
+
+	// Registration defaults
+	v.SetDefault("registration.enabled", true)
+	v.SetDefault("registration.unique_usernames", true)
+	v.SetDefault("registration.primary_contact", "email")
+
+	// Password policy defaults
+	v.SetDefault("password.max_age", "0s")
+	v.SetDefault("password.max_concurrent_hashes", runtime.NumCPU())
+	v.SetDefault("password.hash_queue_timeout", "2s")
+	v.SetDefault("password.pepper", "")
+
+	// Phone defaults
+	v.SetDefault("phone.default_dialing_code", "")
+
+	// Contact defaults
+	v.SetDefault("contact.verification_ttl", "10m")
+
+	// Service auth defaults
+	v.SetDefault("service_auth.tokens", map[string][]string{})
+
+	// Tenancy defaults
+	v.SetDefault("tenancy.enabled", false)
+
+	// Error defaults
+	v.SetDefault("errors.capture_stack", false)
+
+	// WebAuthn defaults
+	v.SetDefault("webauthn.enabled", false)
+	v.SetDefault("webauthn.rp_id", "")
+	v.SetDefault("webauthn.rp_display_name", "Wallet")
+	v.SetDefault("webauthn.rp_origins", []string{})
+	v.SetDefault("webauthn.session_ttl", "5m")
+
+	// Rate limit defaults
+	v.SetDefault("rate_limit.backend", RateLimitBackendMemory)
+	v.SetDefault("rate_limit.verify_password.limit", 5)
+	v.SetDefault("rate_limit.verify_password.window", "15m")
+	v.SetDefault("rate_limit.login.limit", 20)
+	v.SetDefault("rate_limit.login.window", "1m")
+	v.SetDefault("rate_limit.login_account.limit", 10)
+	v.SetDefault("rate_limit.login_account.window", "15m")
+	v.SetDefault("rate_limit.refresh_token.limit", 10)
+	v.SetDefault("rate_limit.refresh_token.window", "15m")
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
 	v.SetDefault("redis.port", 6379)
 	v.SetDefault("redis.password", "")
 	v.SetDefault("redis.db", 0)
+	v.SetDefault("redis.pool_size", 10)
+	v.SetDefault("redis.dial_timeout", "5s")
+	v.SetDefault("redis.read_timeout", "3s")
+	v.SetDefault("redis.write_timeout", "3s")
 
 	// Log defaults
 	v.SetDefault("log.level", "info")
 	v.SetDefault("log.format", "json")
+	v.SetDefault("log.log_payloads", false)
+	v.SetDefault("log.payload_methods", []string{})
 
 	// Worker defaults
 	v.SetDefault("worker.notification.enabled", true)
 	v.SetDefault("worker.notification.interval", "10s")
 	v.SetDefault("worker.notification.max_retries", 5)
 	v.SetDefault("worker.notification.batch_size", 1000)
+	v.SetDefault("worker.notification.max_batch_size", 5000)
 	v.SetDefault("worker.notification.concurrency", 1)
+	v.SetDefault("worker.notification.failure_threshold", 5)
+	v.SetDefault("worker.notification.strict", false)
+	v.SetDefault("worker.notification.send_timeout", "5s")
+	v.SetDefault("worker.notification.startup_delay", "0s")
+	v.SetDefault("worker.notification.wait_for_ready", false)
+	v.SetDefault("worker.notification.cleanup.retention", "720h") // 30 days
+	v.SetDefault("worker.notification.cleanup.interval", "1h")
+	v.SetDefault("worker.notification.cleanup.batch_size", 1000)
+	v.SetDefault("worker.notification.priority_aging.after", "10m")
+	v.SetDefault("worker.notification.priority_aging.boost", 10)
+	v.SetDefault("worker.notification.retry.enabled", false)
+	v.SetDefault("worker.notification.retry.interval", "1m")
+	v.SetDefault("worker.notification.retry.max_attempts", 5)
+	v.SetDefault("worker.notification.retry.backoff", "30s")
+	v.SetDefault("worker.notification.retry.batch_size", 100)
+	v.SetDefault("worker.notification.channels", map[string][]string{
+		"login":              {"email"},
+		"email_verification": {"email"},
+		"phone_verification": {"sms"},
+	})
+	v.SetDefault("worker.notification.email.queue", "default")
+	v.SetDefault("worker.notification.sms.queue", "sms")
+	v.SetDefault("worker.notification.webhook.timeout", "5s")
+	v.SetDefault("worker.notification.sender.email", "")
+	v.SetDefault("worker.notification.sender.name", "")
+	v.SetDefault("worker.notification.templates", map[string]string{
+		"login":              "login_notification",
+		"email_verification": "email_verification",
+		"phone_verification": "phone_verification",
+	})
 }
 
 // GetDSN returns the database connection string
@@ -146,6 +536,22 @@ func (c *DatabaseConfig) GetDSN() string {
 		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode)
 }
 
+// GetDSNURL returns the database connection string as a postgres:// URL. Unlike GetDSN,
+// special characters in the credentials and database name (e.g. '@', '/' or spaces) are
+// escaped, so any password connects.
+func (c *DatabaseConfig) GetDSNURL() string {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(c.User, c.Password),
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:   "/" + c.DBName,
+	}
+	if c.SSLMode != "" {
+		dsn.RawQuery = url.Values{"sslmode": {c.SSLMode}}.Encode()
+	}
+	return dsn.String()
+}
+
 // GetRedisAddr returns the Redis address
 func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
@@ -156,17 +562,319 @@ func (c *ServerConfig) GetServerAddr() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
 
-// Validate validates the configuration
+// TLSEnabled reports whether the server terminates TLS itself
+func (c *ServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != ""
+}
+
+// dialingCodePattern matches an international dialing code such as "+1" or "+886"
+var dialingCodePattern = regexp.MustCompile(`^\+[1-9]\d{0,3}$`)
+
+// postgresSSLModes are the database ssl modes supported by the Postgres driver
+var postgresSSLModes = map[string]bool{"disable": true, "require": true, "verify-ca": true, "verify-full": true}
+
+// Log formats
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// Validate checks every section of the configuration and returns the first problem
+// found, so misconfigurations fail at startup rather than when the setting is first
+// used. Sections that are turned off, such as a disabled notification worker, are only
+// checked where they are still read.
 func (c *Config) Validate() error {
-	if c.Server.Port == "" {
+	for _, validate := range []func() error{
+		c.Server.validate,
+		c.Database.validate,
+		c.JWT.validate,
+		c.MFA.validate,
+		c.Password.validate,
+		c.Phone.validate,
+		c.Contact.validate,
+		c.Registration.validate,
+		c.WebAuthn.validate,
+		c.RateLimit.validate,
+		c.Log.validate,
+		c.Worker.Notification.validate,
+	} {
+		if err := validate(); err != nil {
+			return err
+		}
+	}
+
+	// Redis backs the notification worker queues and the shared rate limit counters
+	if c.Worker.Notification.Enabled || c.RateLimit.Backend == RateLimitBackendRedis {
+		if err := c.Redis.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *ServerConfig) validate() error {
+	if c.Port == "" {
 		return fmt.Errorf("server port is required")
 	}
-	if c.Database.Host == "" {
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("server port must be a number between 1 and 65535, got %q", c.Port)
+	}
+	if c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 {
+		return fmt.Errorf("server read, write and idle timeouts must not be negative")
+	}
+	if c.MaxConnectionsPerIP < 0 {
+		return fmt.Errorf("server max connections per IP must not be negative")
+	}
+	if c.SlowRequestThreshold < 0 {
+		return fmt.Errorf("server slow request threshold must not be negative")
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("server tls_cert_file and tls_key_file must be set together")
+	}
+	// Without credentials the server only accepts plaintext connections, all of which
+	// RequireTLS would reject
+	if c.RequireTLS && c.TLSCertFile == "" {
+		return fmt.Errorf("server require_tls needs tls_cert_file and tls_key_file")
+	}
+	return nil
+}
+
+func (c *DatabaseConfig) validate() error {
+	if c.Host == "" {
 		return fmt.Errorf("database host is required")
 	}
-	if c.JWT.SecretKey == "" {
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("database port must be between 1 and 65535, got %d", c.Port)
+	}
+	if c.User == "" {
+		return fmt.Errorf("database user is required")
+	}
+	if c.DBName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if c.SSLMode != "" && !postgresSSLModes[c.SSLMode] {
+		return fmt.Errorf("database ssl mode must be disable, require, verify-ca or verify-full, got %q", c.SSLMode)
+	}
+	return nil
+}
+
+func (c *JWTConfig) validate() error {
+	if c.SecretKey == "" {
 		return fmt.Errorf("JWT secret key is required")
 	}
+	for _, previous := range c.PreviousSecretKeys {
+		if len(previous) < 32 {
+			return fmt.Errorf("JWT previous secret keys must be at least 32 characters")
+		}
+	}
+	if c.AccessTokenDuration <= 0 {
+		return fmt.Errorf("JWT access token duration must be positive")
+	}
+	if c.RefreshTokenDuration <= c.AccessTokenDuration {
+		return fmt.Errorf("JWT refresh token duration (%s) must be longer than the access token duration (%s)", c.RefreshTokenDuration, c.AccessTokenDuration)
+	}
+	if c.Leeway < 0 {
+		return fmt.Errorf("JWT leeway must not be negative")
+	}
+	if c.ReadOnlyGrace < 0 {
+		return fmt.Errorf("JWT read-only grace must not be negative")
+	}
+	return nil
+}
+
+func (c *MFAConfig) validate() error {
+	if c.Issuer == "" {
+		return fmt.Errorf("MFA issuer is required")
+	}
+	if c.EncryptionKey == "" {
+		return fmt.Errorf("MFA encryption key is required")
+	}
+	for _, previous := range c.PreviousEncryptionKeys {
+		if previous == "" {
+			return fmt.Errorf("MFA previous encryption keys must not be empty")
+		}
+	}
+	if c.ChallengeTTL <= 0 {
+		return fmt.Errorf("MFA challenge TTL must be positive")
+	}
+	if c.BackupCodeCount <= 0 {
+		return fmt.Errorf("MFA backup code count must be positive")
+	}
+	if c.BackupCodeLowThreshold < 0 || c.BackupCodeLowThreshold >= c.BackupCodeCount {
+		return fmt.Errorf("MFA backup code low threshold must be between 0 and the backup code count (%d), got %d", c.BackupCodeCount, c.BackupCodeLowThreshold)
+	}
+	return nil
+}
+
+func (c *PasswordConfig) validate() error {
+	if c.MaxAge < 0 {
+		return fmt.Errorf("password max age must not be negative")
+	}
+	if c.MaxConcurrentHashes < 0 {
+		return fmt.Errorf("password max concurrent hashes must not be negative")
+	}
+	if c.HashQueueTimeout < 0 {
+		return fmt.Errorf("password hash queue timeout must not be negative")
+	}
+	return nil
+}
+
+func (c *PhoneConfig) validate() error {
+	if c.DefaultDialingCode != "" && !dialingCodePattern.MatchString(c.DefaultDialingCode) {
+		return fmt.Errorf("phone default dialing code must look like +<1-4 digits>, got %q", c.DefaultDialingCode)
+	}
+	return nil
+}
+
+func (c *ContactConfig) validate() error {
+	if c.VerificationTTL <= 0 {
+		return fmt.Errorf("contact verification TTL must be positive")
+	}
+	return nil
+}
+
+func (c *RegistrationConfig) validate() error {
+	if c.PrimaryContact != "email" && c.PrimaryContact != "phone" {
+		return fmt.Errorf("registration primary contact must be \"email\" or \"phone\", got %q", c.PrimaryContact)
+	}
+	return nil
+}
 
+func (c *WebAuthnConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.RPID == "" {
+		return fmt.Errorf("WebAuthn relying party ID is required when WebAuthn is enabled")
+	}
+	if c.RPDisplayName == "" {
+		return fmt.Errorf("WebAuthn relying party display name is required when WebAuthn is enabled")
+	}
+	if len(c.RPOrigins) == 0 {
+		return fmt.Errorf("WebAuthn relying party origins are required when WebAuthn is enabled")
+	}
+	for _, origin := range c.RPOrigins {
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("WebAuthn relying party origin must be an absolute URL, got %q", origin)
+		}
+	}
+	if c.SessionTTL <= 0 {
+		return fmt.Errorf("WebAuthn session TTL must be positive")
+	}
+	return nil
+}
+
+func (c *RateLimitConfig) validate() error {
+	if c.Backend != RateLimitBackendMemory && c.Backend != RateLimitBackendRedis {
+		return fmt.Errorf("rate limit backend must be %q or %q, got %q", RateLimitBackendMemory, RateLimitBackendRedis, c.Backend)
+	}
+	if err := c.VerifyPassword.validate("verify_password"); err != nil {
+		return err
+	}
+	if err := c.Login.validate("login"); err != nil {
+		return err
+	}
+	if err := c.LoginAccount.validate("login_account"); err != nil {
+		return err
+	}
+	return c.RefreshToken.validate("refresh_token")
+}
+
+func (r RateLimitRule) validate(name string) error {
+	if r.Limit < 0 {
+		return fmt.Errorf("rate limit %s limit must not be negative", name)
+	}
+	if r.Limit > 0 && r.Window <= 0 {
+		return fmt.Errorf("rate limit %s window must be positive", name)
+	}
+	return nil
+}
+
+func (c *RedisConfig) validate() error {
+	if c.Host == "" {
+		return fmt.Errorf("redis host is required by the notification worker and the redis rate limit backend")
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("redis port must be between 1 and 65535, got %d", c.Port)
+	}
+	if c.DB < 0 {
+		return fmt.Errorf("redis db must not be negative")
+	}
+	if c.PoolSize < 0 {
+		return fmt.Errorf("redis pool size must not be negative")
+	}
+	if c.DialTimeout < 0 || c.ReadTimeout < 0 || c.WriteTimeout < 0 {
+		return fmt.Errorf("redis dial, read and write timeouts must not be negative")
+	}
+	return nil
+}
+
+func (c *LogConfig) validate() error {
+	if _, err := logrus.ParseLevel(c.Level); err != nil {
+		return fmt.Errorf("log level %q is invalid: %w", c.Level, err)
+	}
+	if c.Format != LogFormatJSON && c.Format != LogFormatText {
+		return fmt.Errorf("log format must be %q or %q, got %q", LogFormatJSON, LogFormatText, c.Format)
+	}
+	return nil
+}
+
+func (c *NotificationWorkerConfig) validate() error {
+	if sender := c.Sender.Email; sender != "" {
+		if _, err := mail.ParseAddress(sender); err != nil {
+			return fmt.Errorf("notification sender email %q is invalid: %w", sender, err)
+		}
+	}
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval <= 0 {
+		return fmt.Errorf("notification interval must be positive")
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("notification max retries must not be negative")
+	}
+	if c.BatchSize <= 0 || c.MaxBatchSize <= 0 {
+		return fmt.Errorf("notification batch size and max batch size must be positive")
+	}
+	if c.Concurrency < 1 {
+		return fmt.Errorf("notification concurrency must be at least 1")
+	}
+	if c.FailureThreshold < 0 {
+		return fmt.Errorf("notification failure threshold must not be negative")
+	}
+	if c.SendTimeout <= 0 {
+		return fmt.Errorf("notification send timeout must be positive")
+	}
+	if c.StartupDelay < 0 {
+		return fmt.Errorf("notification startup delay must not be negative")
+	}
+	if c.Cleanup.Retention < 0 {
+		return fmt.Errorf("notification cleanup retention must not be negative")
+	}
+	if c.Cleanup.Retention > 0 && (c.Cleanup.Interval <= 0 || c.Cleanup.BatchSize <= 0) {
+		return fmt.Errorf("notification cleanup interval and batch size must be positive when retention is set")
+	}
+	if c.PriorityAging.After < 0 || c.PriorityAging.Boost < 0 {
+		return fmt.Errorf("notification priority aging after and boost must not be negative")
+	}
+	if c.Retry.Enabled && (c.Retry.Interval <= 0 || c.Retry.MaxAttempts < 1 || c.Retry.Backoff <= 0 || c.Retry.BatchSize <= 0) {
+		return fmt.Errorf("notification retry interval, max attempts, backoff and batch size must be positive when retry is enabled")
+	}
+	for eventType, task := range c.Tasks {
+		if task.ProcessIn < 0 || task.Timeout < 0 || task.Deadline < 0 || task.Unique < 0 {
+			return fmt.Errorf("notification task options of event %q must not be negative", eventType)
+		}
+	}
+	if c.Webhook.URL != "" {
+		if webhookURL, err := url.Parse(c.Webhook.URL); err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+			return fmt.Errorf("notification webhook url must be an absolute http(s) URL, got %q", c.Webhook.URL)
+		}
+		if c.Webhook.Timeout <= 0 {
+			return fmt.Errorf("notification webhook timeout must be positive")
+		}
+	}
 	return nil
 }
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// redactedValue replaces secret values in redacted configuration
+const redactedValue = "[REDACTED]"
+
+// Redacted returns a copy of the configuration with all secrets masked.
+// The copy is safe to log or expose through diagnostics endpoints.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+
+	redacted.Database.Password = redact(c.Database.Password)
+	redacted.JWT.SecretKey = redact(c.JWT.SecretKey)
+	redacted.JWT.PreviousSecretKeys = make([]string, len(c.JWT.PreviousSecretKeys))
+	for i, key := range c.JWT.PreviousSecretKeys {
+		redacted.JWT.PreviousSecretKeys[i] = redact(key)
+	}
+	redacted.Redis.Password = redact(c.Redis.Password)
+	redacted.Password.Pepper = redact(c.Password.Pepper)
+	redacted.MFA.EncryptionKey = redact(c.MFA.EncryptionKey)
+	redacted.MFA.PreviousEncryptionKeys = make([]string, len(c.MFA.PreviousEncryptionKeys))
+	for i, key := range c.MFA.PreviousEncryptionKeys {
+		redacted.MFA.PreviousEncryptionKeys[i] = redact(key)
+	}
+
+	redacted.ServiceAuth.Tokens = make(map[string][]string, len(c.ServiceAuth.Tokens))
+	for caller, hashes := range c.ServiceAuth.Tokens {
+		masked := make([]string, len(hashes))
+		for i, hash := range hashes {
+			masked[i] = redact(hash)
+		}
+		redacted.ServiceAuth.Tokens[caller] = masked
+	}
+
+	return &redacted
+}
+
+// Flatten returns the configuration as dotted keys (matching the YAML layout) mapped to
+// their string values, e.g. "jwt.access_token_duration" -> "15m0s".
+// Call it on a Redacted copy before exposing the result.
+func (c *Config) Flatten() map[string]string {
+	values := make(map[string]string)
+	flattenValue("", reflect.ValueOf(*c), values)
+	return values
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+func flattenValue(prefix string, v reflect.Value, values map[string]string) {
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		values[prefix] = time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			key := field.Tag.Get("mapstructure")
+			if key == "" || !field.IsExported() {
+				continue
+			}
+			flattenValue(joinKey(prefix, key), v.Field(i), values)
+		}
+	case v.Kind() == reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			flattenValue(joinKey(prefix, key.String()), v.MapIndex(key), values)
+		}
+	default:
+		values[prefix] = fmt.Sprint(v.Interface())
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := &Config{
+		Database: DatabaseConfig{Host: "localhost", Password: "db-secret"},
+		JWT:      JWTConfig{SecretKey: "jwt-secret", PreviousSecretKeys: []string{"old-jwt-secret"}, AccessTokenDuration: 15 * time.Minute},
+		Redis:    RedisConfig{Password: ""},
+		Password: PasswordConfig{Pepper: "pepper-secret"},
+		MFA:      MFAConfig{EncryptionKey: "mfa-secret", PreviousEncryptionKeys: []string{"old-mfa-secret"}},
+		ServiceAuth: ServiceAuthConfig{Tokens: map[string][]string{
+			"wallet-api": {"hash-of-secret-token"},
+		}},
+	}
+
+	redacted := cfg.Redacted()
+
+	assert.Equal(t, redactedValue, redacted.Database.Password)
+	assert.Equal(t, redactedValue, redacted.JWT.SecretKey)
+	assert.Equal(t, []string{redactedValue}, redacted.JWT.PreviousSecretKeys)
+	assert.Equal(t, redactedValue, redacted.MFA.EncryptionKey)
+	assert.Equal(t, []string{redactedValue}, redacted.MFA.PreviousEncryptionKeys)
+	assert.Equal(t, redactedValue, redacted.Password.Pepper)
+	assert.Empty(t, redacted.Redis.Password, "empty secrets stay empty")
+	assert.Equal(t, []string{redactedValue}, redacted.ServiceAuth.Tokens["wallet-api"])
+	assert.Equal(t, "db-secret", cfg.Database.Password, "original config must not be modified")
+	assert.Equal(t, "hash-of-secret-token", cfg.ServiceAuth.Tokens["wallet-api"][0], "original config must not be modified")
+	assert.Equal(t, "old-jwt-secret", cfg.JWT.PreviousSecretKeys[0], "original config must not be modified")
+	assert.Equal(t, "old-mfa-secret", cfg.MFA.PreviousEncryptionKeys[0], "original config must not be modified")
+
+	flat := redacted.Flatten()
+	assert.Equal(t, "localhost", flat["database.host"])
+	assert.Equal(t, "15m0s", flat["jwt.access_token_duration"])
+	assert.Equal(t, redactedValue, flat["jwt.secret_key"])
+	for key, value := range flat {
+		assert.NotContains(t, value, "secret", "key %s leaks a secret", key)
+	}
+}
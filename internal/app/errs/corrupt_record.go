@@ -0,0 +1,40 @@
+package errs
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/status"
+)
+
+// CorruptRecordError reports a stored row that cannot be mapped to its domain model, e.g.
+// a user row whose id is not a UUID. It matches ErrCorruptRecord with errors.Is and is
+// returned to gRPC clients as a plain Internal status; the table, row and cause are only
+// meant for logs.
+type CorruptRecordError struct {
+	// Table is the table the row was read from
+	Table string
+	// RowID is the id of the row as stored
+	RowID string
+	// Err is the mapping failure
+	Err error
+}
+
+// NewCorruptRecordError returns a CorruptRecordError for the row of table with id rowID
+func NewCorruptRecordError(table, rowID string, err error) error {
+	return &CorruptRecordError{Table: table, RowID: rowID, Err: err}
+}
+
+// Error implements the error interface
+func (e *CorruptRecordError) Error() string {
+	return fmt.Sprintf("%s: %s row %q: %v", ErrCorruptRecord.Message, e.Table, e.RowID, e.Err)
+}
+
+// Unwrap returns ErrCorruptRecord so errors.Is matches it, and the mapping failure
+func (e *CorruptRecordError) Unwrap() []error {
+	return []error{ErrCorruptRecord, e.Err}
+}
+
+// GRPCStatus returns the Internal status of ErrCorruptRecord, without the row details
+func (e *CorruptRecordError) GRPCStatus() *status.Status {
+	return ErrCorruptRecord.GRPCStatus()
+}
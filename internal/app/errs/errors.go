@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -16,18 +17,58 @@ var (
 	ErrInvalidPassword      = NewError(codes.InvalidArgument, "invalid password")
 	ErrUserNotFound         = NewError(codes.NotFound, "user not found")
 	ErrUserExists           = NewError(codes.AlreadyExists, "user already exists")
+	ErrUsernameTaken        = NewError(codes.AlreadyExists, "username is already taken")
 	ErrInvalidToken         = NewError(codes.InvalidArgument, "invalid token")
 	ErrTokenExpired         = NewError(codes.Unauthenticated, "token expired")
 	ErrTokenRevoked         = NewError(codes.Unauthenticated, "token revoked")
 	ErrTokenNotFound        = NewError(codes.NotFound, "token not found")
 	ErrTokenIsRequired      = NewError(codes.InvalidArgument, "token is required")
+	ErrFieldRequired        = NewError(codes.InvalidArgument, "value is required")
 	ErrInvalidCredentials   = NewError(codes.Unauthenticated, "invalid credentials")
 	ErrEmailIsRequired      = NewError(codes.InvalidArgument, "email is required")
 	ErrEmailOrPhoneRequired = NewError(codes.InvalidArgument, "either email or both country code and phone are required")
 	ErrInvalidPhoneNumber   = NewError(codes.InvalidArgument, "invalid phone number")
 	ErrInvalidCountryCode   = NewError(codes.InvalidArgument, "invalid country code")
+	ErrMalformedToken       = NewError(codes.Unauthenticated, "malformed token")
+	ErrTokenMissingClaims   = NewError(codes.Unauthenticated, "token is missing required claims")
+	ErrInvalidTokenID       = NewError(codes.Unauthenticated, "token has an invalid id")
+	ErrInvalidRole          = NewError(codes.InvalidArgument, "invalid role")
+	ErrUnauthenticated      = NewError(codes.Unauthenticated, "authentication required")
+	ErrPermissionDenied     = NewError(codes.PermissionDenied, "permission denied")
+	ErrTooManyRequests      = NewError(codes.ResourceExhausted, "too many requests")
+	ErrTOTPAlreadyEnabled   = NewError(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled      = NewError(codes.FailedPrecondition, "two-factor authentication enrollment has not been started")
+	ErrInvalidTOTPCode      = NewError(codes.Unauthenticated, "invalid two-factor authentication code")
+	ErrInvalidMFAChallenge  = NewError(codes.Unauthenticated, "invalid or expired two-factor challenge")
+	ErrInvalidBackupCode    = NewError(codes.Unauthenticated, "invalid or already used backup code")
+	ErrRegistrationDisabled = NewError(codes.PermissionDenied, "registration is disabled").WithReason("registration_disabled")
+	ErrInvalidID            = NewError(codes.InvalidArgument, "invalid id: must be a UUID")
+	ErrInvalidIdentifier    = NewError(codes.InvalidArgument, "exactly one of email, country code and phone, or username is required")
+	ErrSessionNotFound      = NewError(codes.NotFound, "session not found")
+	ErrUnknownEventType     = NewError(codes.InvalidArgument, "unknown notification event type")
+	ErrPanicRecovered       = NewError(codes.Internal, "Internal server error occurred").WithReason("panic_recovered")
+	ErrTenantRequired       = NewError(codes.InvalidArgument, "x-tenant-id metadata is required").WithReason("tenant_required")
+	ErrInvalidTenant        = NewError(codes.InvalidArgument, "invalid tenant id")
+	ErrServerBusy           = NewError(codes.ResourceExhausted, "server is busy, please retry later").WithReason("server_busy")
+	ErrAccountDisabled      = NewError(codes.PermissionDenied, "account is disabled").WithReason("account_disabled")
+	ErrAccountLocked        = NewError(codes.PermissionDenied, "account is locked").WithReason("account_locked")
+	ErrTLSRequired          = NewError(codes.PermissionDenied, "a TLS connection is required").WithReason("tls_required")
+	ErrInvalidAccountStatus = NewError(codes.InvalidArgument, "invalid account status")
+	ErrInvalidContact       = NewError(codes.InvalidArgument, "exactly one of email, or country code and phone, is required")
+	ErrInvalidContactMethod = NewError(codes.InvalidArgument, "invalid contact method: must be email or phone")
+	ErrContactInUse         = NewError(codes.AlreadyExists, "contact is already in use").WithReason("contact_in_use")
+	ErrContactMethodMissing = NewError(codes.FailedPrecondition, "no contact of this method is set").WithReason("contact_method_missing")
+	ErrInvalidVerification  = NewError(codes.FailedPrecondition, "invalid or expired contact verification")
+	ErrInvalidContactCode   = NewError(codes.InvalidArgument, "invalid verification code")
+	ErrCorruptRecord        = NewError(codes.Internal, "stored record is corrupt").WithReason("corrupt_record")
+	ErrWebAuthnDisabled     = NewError(codes.Unimplemented, "WebAuthn sign-in is not enabled").WithReason("webauthn_disabled")
+	ErrInvalidCeremony      = NewError(codes.FailedPrecondition, "invalid or expired WebAuthn session")
+	ErrInvalidPasskey       = NewError(codes.InvalidArgument, "WebAuthn credential could not be verified")
 )	
 
+// ErrorDomain is the domain of the errdetails.ErrorInfo attached to errors with a reason
+const ErrorDomain = "wallet-user-svc"
+
 // ErrorWrapper is a customizable error wrapper with rich metadata
 type ErrorWrapper struct {
 	Code       codes.Code
@@ -39,6 +80,9 @@ type ErrorWrapper struct {
 	Operation  string
 	Err        error
 	StackTrace string
+	// Reason is a stable machine-readable identifier of the error, sent to clients
+	// as an errdetails.ErrorInfo detail
+	Reason string
 }
 
 // Error implements the error interface
@@ -54,9 +98,35 @@ func (e *ErrorWrapper) Unwrap() error {
 	return e.Err
 }
 
-// GRPCStatus returns the gRPC status
+// GRPCStatus returns the gRPC status, carrying an errdetails.ErrorInfo if the error has a reason.
+// The operation and the details are sent as its metadata, so details must be safe to show to clients.
 func (e *ErrorWrapper) GRPCStatus() *status.Status {
-	return status.New(e.Code, e.Message)
+	st := status.New(e.Code, e.Message)
+	if e.Reason == "" {
+		return st
+	}
+
+	info := &errdetails.ErrorInfo{Reason: e.Reason, Domain: ErrorDomain}
+	if e.Operation != "" || len(e.Details) > 0 {
+		info.Metadata = make(map[string]string, len(e.Details)+1)
+	}
+	for key, value := range e.Details {
+		info.Metadata[key] = fmt.Sprint(value)
+	}
+	if e.Operation != "" {
+		info.Metadata["operation"] = e.Operation
+	}
+	detailed, err := st.WithDetails(info)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// WithReason sets the machine-readable reason of the error
+func (e *ErrorWrapper) WithReason(reason string) *ErrorWrapper {
+	e.Reason = reason
+	return e
 }
 
 // WithDetail adds a key-value detail to the error
@@ -80,6 +150,17 @@ func (e *ErrorWrapper) WithUserID(userID string) *ErrorWrapper {
 	return e
 }
 
+// Clone returns a copy of the error that can be modified without affecting the original,
+// e.g. to annotate one of the shared sentinel errors for a single request
+func (e *ErrorWrapper) Clone() *ErrorWrapper {
+	clone := *e
+	clone.Details = make(map[string]interface{}, len(e.Details))
+	for key, value := range e.Details {
+		clone.Details[key] = value
+	}
+	return &clone
+}
+
 // WithOperation adds an operation name to the error
 func (e *ErrorWrapper) WithOperation(operation string) *ErrorWrapper {
 	e.Operation = operation
@@ -106,24 +187,28 @@ func (e *ErrorWrapper) GetDetails() map[string]interface{} {
 	return e.Details
 }
 
-// NewError creates a new error wrapper
+// NewError creates a new error wrapper, with the caller's stack trace when capture is
+// enabled through SetCaptureStack
 func NewError(code codes.Code, message string) *ErrorWrapper {
 	return &ErrorWrapper{
-		Code:      code,
-		Message:   message,
-		Timestamp: time.Now(),
-		Details:   make(map[string]interface{}),
+		Code:       code,
+		Message:    message,
+		Timestamp:  time.Now(),
+		Details:    make(map[string]interface{}),
+		StackTrace: currentStack(),
 	}
 }
 
-// WrapError wraps an existing error with additional context
+// WrapError wraps an existing error with additional context, with the caller's stack
+// trace when capture is enabled through SetCaptureStack
 func WrapError(err error, code codes.Code, message string) *ErrorWrapper {
 	return &ErrorWrapper{
-		Code:      code,
-		Message:   message,
-		Timestamp: time.Now(),
-		Err:       err,
-		Details:   make(map[string]interface{}),
+		Code:       code,
+		Message:    message,
+		Timestamp:  time.Now(),
+		Err:        err,
+		Details:    make(map[string]interface{}),
+		StackTrace: currentStack(),
 	}
 }
 
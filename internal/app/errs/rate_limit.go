@@ -0,0 +1,48 @@
+package errs
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimitError reports a request refused by a rate limit. It matches ErrTooManyRequests
+// with errors.Is and is returned to gRPC clients as ResourceExhausted with an
+// errdetails.RetryInfo detail telling them when to retry.
+type RateLimitError struct {
+	// Limit is the number of requests allowed per window
+	Limit int
+	// RetryAfter is the longest the caller has to wait before the limit allows it again
+	RetryAfter time.Duration
+}
+
+// NewRateLimitError returns a RateLimitError for a rule allowing limit requests per window
+func NewRateLimitError(limit int, window time.Duration) error {
+	return &RateLimitError{Limit: limit, RetryAfter: window}
+}
+
+// Error implements the error interface
+func (e *RateLimitError) Error() string {
+	return ErrTooManyRequests.Error()
+}
+
+// Unwrap returns ErrTooManyRequests so errors.Is matches it
+func (e *RateLimitError) Unwrap() error {
+	return ErrTooManyRequests
+}
+
+// GRPCStatus returns the ResourceExhausted status, carrying the retry delay if known
+func (e *RateLimitError) GRPCStatus() *status.Status {
+	st := ErrTooManyRequests.GRPCStatus()
+	if e.RetryAfter <= 0 {
+		return st
+	}
+
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
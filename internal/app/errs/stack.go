@@ -0,0 +1,24 @@
+package errs
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// captureStack makes NewError and WrapError record the stack trace of their caller
+var captureStack atomic.Bool
+
+// SetCaptureStack turns stack trace capture on or off for errors created afterwards.
+// Sentinel errors are created during package initialization, before it can be turned
+// on, so they never carry a stack.
+func SetCaptureStack(enabled bool) {
+	captureStack.Store(enabled)
+}
+
+// currentStack returns the stack trace of the calling goroutine, or "" when capture is off
+func currentStack() string {
+	if !captureStack.Load() {
+		return ""
+	}
+	return string(debug.Stack())
+}
@@ -0,0 +1,23 @@
+package errs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestSetCaptureStack(t *testing.T) {
+	t.Cleanup(func() { SetCaptureStack(false) })
+
+	assert.Empty(t, ErrUserNotFound.StackTrace, "sentinels never carry a stack")
+	assert.Empty(t, NewError(codes.Internal, "disabled").StackTrace)
+
+	SetCaptureStack(true)
+	assert.Contains(t, NewError(codes.Internal, "enabled").StackTrace, "TestSetCaptureStack")
+	assert.Contains(t, WrapError(errors.New("cause"), codes.Internal, "enabled").StackTrace, "TestSetCaptureStack")
+
+	SetCaptureStack(false)
+	assert.Empty(t, WrapError(errors.New("cause"), codes.Internal, "disabled").StackTrace)
+}
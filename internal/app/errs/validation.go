@@ -0,0 +1,91 @@
+package errs
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FieldViolation ties a validation error to the request field that caused it
+type FieldViolation struct {
+	// Field is the path of the offending field, using the proto field name
+	Field string
+	Err   error
+}
+
+// ValidationError reports every invalid field of a request. It matches each of its
+// violation errors with errors.Is and is returned to gRPC clients as InvalidArgument
+// with an errdetails.BadRequest detail listing the field violations.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+// NewValidationError returns a ValidationError for the given violations, or nil if there are none
+func NewValidationError(violations ...FieldViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Field+": "+v.Err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the violation errors so errors.Is matches any of them
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		errs = append(errs, v.Err)
+	}
+	return errs
+}
+
+// GRPCStatus returns an InvalidArgument status carrying the field violations
+func (e *ValidationError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Err.Error(),
+		})
+	}
+
+	detailed, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// FieldViolations extracts the field violations from err, whether it is a
+// ValidationError or a gRPC status error carrying an errdetails.BadRequest
+func FieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		err = validationErr.GRPCStatus().Err()
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = append(violations, badRequest.GetFieldViolations()...)
+		}
+	}
+	return violations
+}
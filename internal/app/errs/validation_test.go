@@ -0,0 +1,32 @@
+package errs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidationError_GRPCStatus(t *testing.T) {
+	err := NewValidationError(
+		FieldViolation{Field: "email", Err: ErrInvalidEmail},
+		FieldViolation{Field: "password", Err: ErrInvalidPassword},
+	)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	violations := FieldViolations(st.Err())
+	require.Len(t, violations, 2)
+	assert.Equal(t, "email", violations[0].GetField())
+	assert.Equal(t, "invalid email", violations[0].GetDescription())
+	assert.Equal(t, "password", violations[1].GetField())
+	assert.Equal(t, "invalid password", violations[1].GetDescription())
+}
+
+func TestNewValidationError_NoViolations(t *testing.T) {
+	assert.NoError(t, NewValidationError())
+}
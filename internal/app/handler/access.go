@@ -0,0 +1,43 @@
+package handler
+
+import (
+	pb "wallet-user-svc/api/proto"
+	grpcutils "wallet-user-svc/pkg/utils/grpc"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// MethodAccessPolicies defines the access level of each gRPC method served.
+// Methods that are not listed require an authenticated caller.
+var MethodAccessPolicies = map[string]grpcutils.AccessLevel{
+	pb.UserService_Register_FullMethodName:            grpcutils.AccessPublic,
+	pb.UserService_Login_FullMethodName:               grpcutils.AccessPublic,
+	pb.UserService_RefreshToken_FullMethodName:        grpcutils.AccessPublic,
+	pb.UserService_RotateRefreshToken_FullMethodName:  grpcutils.AccessPublic,
+	pb.UserService_CompleteLogin_FullMethodName:       grpcutils.AccessPublic,
+	pb.UserService_BeginWebAuthnLogin_FullMethodName:  grpcutils.AccessPublic,
+	pb.UserService_FinishWebAuthnLogin_FullMethodName: grpcutils.AccessPublic,
+	pb.UserService_GetRuntimeInfo_FullMethodName:      grpcutils.AccessAdmin,
+	pb.UserService_CreateUser_FullMethodName:          grpcutils.AccessAdmin,
+	pb.UserService_ReEncryptSecrets_FullMethodName:    grpcutils.AccessAdmin,
+	pb.UserService_GetTokenStats_FullMethodName:       grpcutils.AccessAdmin,
+	healthpb.Health_Check_FullMethodName:              grpcutils.AccessPublic,
+}
+
+// ReadOnlyMethods lists the low-risk methods that only read the caller's own data. They
+// accept an access token within jwt.read_only_grace of its expiry.
+var ReadOnlyMethods = map[string]bool{
+	pb.UserService_ListSessions_FullMethodName:               true,
+	pb.UserService_GetNotificationPreferences_FullMethodName: true,
+}
+
+// TenantExemptMethods lists the methods served without a tenant when tenancy is enabled
+var TenantExemptMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+}
+
+// TLSExemptMethods lists the methods served over plaintext connections when
+// server.require_tls is set, so load balancer and orchestrator probes keep working
+var TLSExemptMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+}
@@ -2,31 +2,60 @@ package handler
 
 import (
 	"context"
+	"strings"
 
 	pb "wallet-user-svc/api/proto"
+	"wallet-user-svc/internal/app/model/domain"
 	"wallet-user-svc/internal/app/model/dto"
 	logutils "wallet-user-svc/pkg/utils/log"
 
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
 )
 
 // UserHandler handles gRPC requests for user operations
 type UserHandler struct {
 	pb.UnimplementedUserServiceServer
-	userService UserService
+	userService        UserService
+	diagnosticsService DiagnosticsService
 }
 
 // UserServiceInterface defines the methods that the user service should implement
 type UserService interface {
 	Register(ctx context.Context, req dto.RegisterReq) (*dto.RegisterResp, error)
+	AdminCreateUser(ctx context.Context, req dto.AdminCreateUserReq) (*dto.AdminCreateUserResp, error)
 	Login(ctx context.Context, req dto.LoginReq) (*dto.LoginResp, error)
 	RefreshToken(ctx context.Context, req dto.RefreshTokenReq) (*dto.RefreshTokenResp, error)
+	RotateRefreshToken(ctx context.Context, req dto.RotateRefreshReq) (*dto.RotateRefreshResp, error)
+	EnrollTOTP(ctx context.Context) (*dto.EnrollTOTPResp, error)
+	VerifyTOTP(ctx context.Context, req dto.VerifyTOTPReq) error
+	CompleteLogin(ctx context.Context, req dto.CompleteLoginReq) (*dto.LoginResp, error)
+	RegenerateBackupCodes(ctx context.Context, req dto.RegenerateBackupCodesReq) (*dto.RegenerateBackupCodesResp, error)
+	ListSessions(ctx context.Context) (*dto.ListSessionsResp, error)
+	RevokeSession(ctx context.Context, req dto.RevokeSessionReq) error
+	GetNotificationPreferences(ctx context.Context) (*dto.GetNotificationPreferencesResp, error)
+	UpdateNotificationPreferences(ctx context.Context, req dto.UpdateNotificationPreferencesReq) (*dto.UpdateNotificationPreferencesResp, error)
+	ReEncryptSecrets(ctx context.Context, req dto.ReEncryptSecretsReq) (*dto.ReEncryptSecretsResp, error)
+	GetTokenStats(ctx context.Context, req dto.GetTokenStatsReq) (*dto.GetTokenStatsResp, error)
+	AddContactMethod(ctx context.Context, req dto.AddContactMethodReq) (*dto.AddContactMethodResp, error)
+	ConfirmContactMethod(ctx context.Context, req dto.ConfirmContactMethodReq) (*dto.ContactResp, error)
+	SetPrimaryContact(ctx context.Context, req dto.SetPrimaryContactReq) (*dto.ContactResp, error)
+	BeginWebAuthnRegistration(ctx context.Context) (*dto.BeginWebAuthnResp, error)
+	FinishWebAuthnRegistration(ctx context.Context, req dto.FinishWebAuthnRegistrationReq) (*dto.FinishWebAuthnRegistrationResp, error)
+	BeginWebAuthnLogin(ctx context.Context) (*dto.BeginWebAuthnResp, error)
+	FinishWebAuthnLogin(ctx context.Context, req dto.FinishWebAuthnLoginReq) (*dto.LoginResp, error)
+}
+
+// DiagnosticsService defines the methods that the diagnostics service should implement
+type DiagnosticsService interface {
+	GetRuntimeInfo(ctx context.Context) (*dto.RuntimeInfoResp, error)
 }
 
 // NewUserHandler creates a new UserHandler instance
-func NewUserHandler(userService UserService) *UserHandler {
+func NewUserHandler(userService UserService, diagnosticsService DiagnosticsService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:        userService,
+		diagnosticsService: diagnosticsService,
 	}
 }
 
@@ -42,15 +71,17 @@ func (h *UserHandler) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 		"phone":        req.Phone,
 	}).Info("User registration request received")
 
-	// Create RegisterReq with proper handling of optional fields
+	// Create RegisterReq with proper handling of optional fields. Identifiers are
+	// trimmed; the password is kept verbatim as whitespace in it may be intentional.
 	registerReq := dto.RegisterReq{
-		Username: req.Username,
+		Username: strings.TrimSpace(req.Username),
 		Password: req.Password,
+		Device:   clientDevice(ctx, req.DeviceId),
 	}
 
 	// Handle email (can be empty if using phone)
-	if req.Email != "" {
-		registerReq.Email = &req.Email
+	if email := strings.TrimSpace(req.Email); email != "" {
+		registerReq.Email = &email
 	}
 
 	// Handle country code and phone (can be empty if using email)
@@ -60,6 +91,9 @@ func (h *UserHandler) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 	if req.Phone != "" {
 		registerReq.Phone = &req.Phone
 	}
+	if req.PrimaryContact != "" {
+		registerReq.PrimaryContact = &req.PrimaryContact
+	}
 
 	resp, err := h.userService.Register(ctx, registerReq)
 	if err != nil {
@@ -72,31 +106,89 @@ func (h *UserHandler) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 		"username": resp.User.Username.String(),
 	}).Info("User registration successful")
 
-	user := &pb.User{
-		Id:       resp.User.ID.String(),
-		Username: resp.User.Username.String(),
+	return &pb.RegisterResponse{
+		User:                  toPBUser(resp.User),
+		AccessToken:           resp.AccessToken,
+		RefreshToken:          resp.RefreshToken,
+		Warnings:              toPBWarnings(resp.Warnings),
+		AccessTokenExpiresAt:  resp.AccessTokenExpiresAt,
+		RefreshTokenExpiresAt: resp.RefreshTokenExpiresAt,
+	}, nil
+}
+
+// CreateUser handles account creation by an admin
+func (h *UserHandler) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	createUserReq := dto.AdminCreateUserReq{
+		RegisterReq: dto.RegisterReq{
+			Username: strings.TrimSpace(req.Username),
+			Password: req.Password,
+		},
+		Role:               req.Role,
+		EmailVerified:      req.EmailVerified,
+		MustChangePassword: req.MustChangePassword,
+	}
+	if email := strings.TrimSpace(req.Email); email != "" {
+		createUserReq.Email = &email
+	}
+	if req.CountryCode != "" {
+		createUserReq.CountryCode = &req.CountryCode
+	}
+	if req.Phone != "" {
+		createUserReq.Phone = &req.Phone
+	}
+	if req.PrimaryContact != "" {
+		createUserReq.PrimaryContact = &req.PrimaryContact
 	}
 
-	// Handle optional email
-	if resp.User.Email != nil {
-		user.Email = resp.User.Email.ToPtrString()
+	resp, err := h.userService.AdminCreateUser(ctx, createUserReq)
+	if err != nil {
+		logger.WithError(err).Error("User creation failed")
+		return nil, err
 	}
 
-	// Handle optional country code
-	if resp.User.CountryCode != nil {
-		user.CountryCode = resp.User.CountryCode.ToPtrString()
+	return &pb.CreateUserResponse{
+		User:     toPBUser(resp.User),
+		Warnings: toPBWarnings(resp.Warnings),
+	}, nil
+}
+
+// toPBUser converts a domain user to its protobuf representation. Every RPC returning a
+// user goes through it, so optional fields are populated the same way everywhere.
+func toPBUser(u *domain.User) *pb.User {
+	return &pb.User{
+		Id:             u.ID.String(),
+		Email:          optionalString(u.Email),
+		Username:       u.Username.String(),
+		CountryCode:    optionalString(u.CountryCode),
+		Phone:          optionalString(u.Phone),
+		PrimaryContact: u.PrimaryContact.String(),
 	}
+}
 
-	// Handle optional phone
-	if resp.User.Phone != nil {
-		user.Phone = resp.User.Phone.ToPtrString()
+// optionalString converts an optional value object into an optional proto field,
+// leaving the field unset when the value is absent or empty
+func optionalString[T ~string](value *T) *string {
+	if value == nil || *value == "" {
+		return nil
 	}
+	s := string(*value)
+	return &s
+}
 
-	return &pb.RegisterResponse{
-		User:         user,
-		AccessToken:  resp.AccessToken,
-		RefreshToken: resp.RefreshToken,
-	}, nil
+// toPBWarnings converts registration warnings to their protobuf representation
+func toPBWarnings(warnings []dto.Warning) []*pb.Warning {
+	pbWarnings := make([]*pb.Warning, 0, len(warnings))
+	for _, warning := range warnings {
+		pbWarnings = append(pbWarnings, &pb.Warning{
+			Field:   warning.Field,
+			Code:    warning.Code,
+			Message: warning.Message,
+		})
+	}
+	return pbWarnings
 }
 
 // Login handles user login
@@ -105,30 +197,422 @@ func (h *UserHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Logi
 	logger := logutils.GetLoggerOrDefault(ctx)
 
 	resp, err := h.userService.Login(ctx, dto.LoginReq{
-		Password: req.Password,
-		Email:    req.Email,
+		Password:    req.Password,
+		Email:       strings.TrimSpace(req.Email),
+		CountryCode: strings.TrimSpace(req.CountryCode),
+		Phone:       strings.TrimSpace(req.Phone),
+		Device:      clientDevice(ctx, req.DeviceId),
 	})
 	if err != nil {
 		logger.WithError(err).Error("User login failed")
 		return nil, err
 	}
 
-	return &pb.LoginResponse{
-		AccessToken:  resp.AccessToken,
-		RefreshToken: resp.RefreshToken,
+	return toLoginResponse(resp), nil
+}
+
+// CompleteLogin handles the second step of a login with two-factor authentication
+func (h *UserHandler) CompleteLogin(ctx context.Context, req *pb.CompleteLoginRequest) (*pb.LoginResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	resp, err := h.userService.CompleteLogin(ctx, dto.CompleteLoginReq{
+		ChallengeID: req.ChallengeId,
+		Code:        req.Code,
+		BackupCode:  req.BackupCode,
+		Device:      clientDevice(ctx, req.DeviceId),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Completing login failed")
+		return nil, err
+	}
+
+	return toLoginResponse(resp), nil
+}
+
+// EnrollTOTP handles two-factor enrollment for the authenticated user
+func (h *UserHandler) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	resp, err := h.userService.EnrollTOTP(ctx)
+	if err != nil {
+		logger.WithError(err).Error("TOTP enrollment failed")
+		return nil, err
+	}
+
+	return &pb.EnrollTOTPResponse{
+		Secret:      resp.Secret,
+		Uri:         resp.URI,
+		BackupCodes: resp.BackupCodes,
 	}, nil
 }
 
+// RegenerateBackupCodes handles replacement of two-factor backup codes
+func (h *UserHandler) RegenerateBackupCodes(ctx context.Context, req *pb.RegenerateBackupCodesRequest) (*pb.RegenerateBackupCodesResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	resp, err := h.userService.RegenerateBackupCodes(ctx, dto.RegenerateBackupCodesReq{Code: req.Code})
+	if err != nil {
+		logger.WithError(err).Error("Backup code regeneration failed")
+		return nil, err
+	}
+
+	return &pb.RegenerateBackupCodesResponse{
+		BackupCodes: resp.BackupCodes,
+	}, nil
+}
+
+// VerifyTOTP handles confirmation of two-factor enrollment
+func (h *UserHandler) VerifyTOTP(ctx context.Context, req *pb.VerifyTOTPRequest) (*pb.VerifyTOTPResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	if err := h.userService.VerifyTOTP(ctx, dto.VerifyTOTPReq{Code: req.Code}); err != nil {
+		logger.WithError(err).Error("TOTP verification failed")
+		return nil, err
+	}
+
+	return &pb.VerifyTOTPResponse{}, nil
+}
+
+func toLoginResponse(resp *dto.LoginResp) *pb.LoginResponse {
+	loginResp := &pb.LoginResponse{
+		AccessToken:           resp.AccessToken,
+		RefreshToken:          resp.RefreshToken,
+		MfaRequired:           resp.MFARequired,
+		ChallengeId:           resp.ChallengeID,
+		BackupCodesLow:        resp.BackupCodesLow,
+		AccessTokenExpiresAt:  resp.AccessTokenExpiresAt,
+		RefreshTokenExpiresAt: resp.RefreshTokenExpiresAt,
+		PasswordExpired:       resp.PasswordExpired,
+	}
+	if resp.BackupCodesRemaining != nil {
+		remaining := int32(*resp.BackupCodesRemaining)
+		loginResp.BackupCodesRemaining = &remaining
+	}
+	if resp.User != nil {
+		loginResp.MustChangePassword = resp.User.MustChangePassword
+	}
+	return loginResp
+}
+
 // RefreshToken handles token refresh
 func (h *UserHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
 	resp, err := h.userService.RefreshToken(ctx, dto.RefreshTokenReq{
 		RefreshToken: req.RefreshToken,
+		Device:       clientDevice(ctx, req.DeviceId),
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &pb.RefreshTokenResponse{
-		AccessToken: resp.AccessToken,
+		AccessToken:          resp.AccessToken,
+		AccessTokenExpiresAt: resp.AccessTokenExpiresAt,
+	}, nil
+}
+
+// RotateRefreshToken handles refresh token rotation
+func (h *UserHandler) RotateRefreshToken(ctx context.Context, req *pb.RotateRefreshTokenRequest) (*pb.RotateRefreshTokenResponse, error) {
+	resp, err := h.userService.RotateRefreshToken(ctx, dto.RotateRefreshReq{
+		RefreshToken: req.RefreshToken,
+		Device:       clientDevice(ctx, req.DeviceId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RotateRefreshTokenResponse{
+		AccessToken:           resp.AccessToken,
+		RefreshToken:          resp.RefreshToken,
+		AccessTokenExpiresAt:  resp.AccessTokenExpiresAt,
+		RefreshTokenExpiresAt: resp.RefreshTokenExpiresAt,
+	}, nil
+}
+
+// GetRuntimeInfo handles operator diagnostics requests
+func (h *UserHandler) GetRuntimeInfo(ctx context.Context, req *pb.GetRuntimeInfoRequest) (*pb.GetRuntimeInfoResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	resp, err := h.diagnosticsService.GetRuntimeInfo(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get runtime info")
+		return nil, err
+	}
+
+	return &pb.GetRuntimeInfoResponse{
+		Build: &pb.BuildInfo{
+			Version:   resp.Build.Version,
+			GoVersion: resp.Build.GoVersion,
+			Revision:  resp.Build.Revision,
+			BuildTime: resp.Build.BuildTime,
+		},
+		Config: resp.Config,
+		DatabasePool: &pb.DatabasePoolStats{
+			MaxOpenConnections: int32(resp.DatabasePool.MaxOpenConnections),
+			OpenConnections:    int32(resp.DatabasePool.OpenConnections),
+			InUse:              int32(resp.DatabasePool.InUse),
+			Idle:               int32(resp.DatabasePool.Idle),
+			WaitCount:          resp.DatabasePool.WaitCount,
+			WaitDurationMs:     resp.DatabasePool.WaitDuration.Milliseconds(),
+		},
+		Notifications: &pb.NotificationStats{
+			Dropped: resp.Notifications.Dropped,
+		},
+		StartedAt:     resp.StartedAt.UnixMilli(),
+		UptimeSeconds: int64(resp.Uptime.Seconds()),
+	}, nil
+}
+
+// GetTokenStats handles refresh token stats requests
+func (h *UserHandler) GetTokenStats(ctx context.Context, req *pb.GetTokenStatsRequest) (*pb.GetTokenStatsResponse, error) {
+	resp, err := h.userService.GetTokenStats(ctx, dto.GetTokenStatsReq{
+		PageSize: int(req.PageSize),
+		Offset:   int(req.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*pb.UserTokenCounts, 0, len(resp.Users))
+	for _, user := range resp.Users {
+		users = append(users, &pb.UserTokenCounts{
+			UserId: user.UserID,
+			Counts: tokenCountsToProto(user.Counts),
+		})
+	}
+
+	return &pb.GetTokenStatsResponse{
+		Total:      tokenCountsToProto(resp.Total),
+		Users:      users,
+		NextOffset: int32(resp.NextOffset),
+	}, nil
+}
+
+func tokenCountsToProto(counts dto.TokenCounts) *pb.TokenCounts {
+	return &pb.TokenCounts{
+		Active:  counts.Active,
+		Revoked: counts.Revoked,
+		Expired: counts.Expired,
+	}
+}
+
+// clientDevice identifies the calling client by its user agent metadata and the
+// device ID it sent in the request
+func clientDevice(ctx context.Context, deviceID string) dto.ClientDevice {
+	device := dto.ClientDevice{DeviceID: deviceID}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			device.UserAgent = values[0]
+		}
+	}
+	return device
+}
+
+// ListSessions handles listing the caller's active sessions
+func (h *UserHandler) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	resp, err := h.userService.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*pb.Session, 0, len(resp.Sessions))
+	for _, session := range resp.Sessions {
+		sessions = append(sessions, &pb.Session{
+			Id:        session.ID,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+
+	return &pb.ListSessionsResponse{Sessions: sessions}, nil
+}
+
+// RevokeSession handles signing out of one of the caller's sessions
+func (h *UserHandler) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	if err := h.userService.RevokeSession(ctx, dto.RevokeSessionReq{SessionID: req.SessionId}); err != nil {
+		return nil, err
+	}
+
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+// GetNotificationPreferences handles reading the caller's notification preferences
+func (h *UserHandler) GetNotificationPreferences(ctx context.Context, req *pb.GetNotificationPreferencesRequest) (*pb.GetNotificationPreferencesResponse, error) {
+	resp, err := h.userService.GetNotificationPreferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetNotificationPreferencesResponse{Preferences: toPBNotificationPreferences(resp.Preferences)}, nil
+}
+
+// UpdateNotificationPreferences handles changing the caller's notification preferences
+func (h *UserHandler) UpdateNotificationPreferences(ctx context.Context, req *pb.UpdateNotificationPreferencesRequest) (*pb.UpdateNotificationPreferencesResponse, error) {
+	updateReq := dto.UpdateNotificationPreferencesReq{
+		Preferences: make([]dto.NotificationPreference, 0, len(req.Preferences)),
+	}
+	for _, preference := range req.Preferences {
+		updateReq.Preferences = append(updateReq.Preferences, dto.NotificationPreference{
+			EventType: strings.TrimSpace(preference.EventType),
+			Enabled:   preference.Enabled,
+		})
+	}
+
+	resp, err := h.userService.UpdateNotificationPreferences(ctx, updateReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateNotificationPreferencesResponse{Preferences: toPBNotificationPreferences(resp.Preferences)}, nil
+}
+
+// ReEncryptSecrets handles moving stored secrets to the current encryption key
+func (h *UserHandler) ReEncryptSecrets(ctx context.Context, req *pb.ReEncryptSecretsRequest) (*pb.ReEncryptSecretsResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	resp, err := h.userService.ReEncryptSecrets(ctx, dto.ReEncryptSecretsReq{
+		AfterUserID: strings.TrimSpace(req.AfterUserId),
+		BatchSize:   int(req.BatchSize),
+		MaxBatches:  int(req.MaxBatches),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Secret re-encryption failed")
+		return nil, err
+	}
+
+	return &pb.ReEncryptSecretsResponse{
+		Processed:   int32(resp.Processed),
+		ReEncrypted: int32(resp.ReEncrypted),
+		Failed:      int32(resp.Failed),
+		LastUserId:  resp.LastUserID,
+		Done:        resp.Done,
 	}, nil
 }
+
+func toPBNotificationPreferences(preferences []dto.NotificationPreference) []*pb.NotificationPreference {
+	pbPreferences := make([]*pb.NotificationPreference, 0, len(preferences))
+	for _, preference := range preferences {
+		pbPreferences = append(pbPreferences, &pb.NotificationPreference{
+			EventType: preference.EventType,
+			Enabled:   preference.Enabled,
+		})
+	}
+	return pbPreferences
+}
+
+// AddContactMethod handles starting the verification of a new email or phone
+func (h *UserHandler) AddContactMethod(ctx context.Context, req *pb.AddContactMethodRequest) (*pb.AddContactMethodResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	addReq := dto.AddContactMethodReq{Email: strings.TrimSpace(req.Email)}
+	if req.CountryCode != nil && *req.CountryCode != "" {
+		addReq.CountryCode = req.CountryCode
+	}
+	if req.Phone != nil && *req.Phone != "" {
+		addReq.Phone = req.Phone
+	}
+
+	resp, err := h.userService.AddContactMethod(ctx, addReq)
+	if err != nil {
+		logger.WithError(err).Error("Adding contact method failed")
+		return nil, err
+	}
+
+	return &pb.AddContactMethodResponse{
+		VerificationId: resp.VerificationID,
+		Method:         resp.Method.String(),
+		ExpiresAt:      resp.ExpiresAt.Millis(),
+	}, nil
+}
+
+// ConfirmContactMethod handles entering the code sent to a new contact
+func (h *UserHandler) ConfirmContactMethod(ctx context.Context, req *pb.ConfirmContactMethodRequest) (*pb.ConfirmContactMethodResponse, error) {
+	resp, err := h.userService.ConfirmContactMethod(ctx, dto.ConfirmContactMethodReq{
+		VerificationID: strings.TrimSpace(req.VerificationId),
+		Code:           strings.TrimSpace(req.Code),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ConfirmContactMethodResponse{User: toPBUser(resp.User)}, nil
+}
+
+// SetPrimaryContact handles choosing the contact notifications go to
+func (h *UserHandler) SetPrimaryContact(ctx context.Context, req *pb.SetPrimaryContactRequest) (*pb.SetPrimaryContactResponse, error) {
+	resp, err := h.userService.SetPrimaryContact(ctx, dto.SetPrimaryContactReq{Method: strings.TrimSpace(req.Method)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SetPrimaryContactResponse{User: toPBUser(resp.User)}, nil
+}
+
+// BeginWebAuthnRegistration handles starting passkey registration for the authenticated user
+func (h *UserHandler) BeginWebAuthnRegistration(ctx context.Context, req *pb.BeginWebAuthnRegistrationRequest) (*pb.BeginWebAuthnRegistrationResponse, error) {
+	resp, err := h.userService.BeginWebAuthnRegistration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.BeginWebAuthnRegistrationResponse{
+		SessionId:   resp.SessionID,
+		OptionsJson: string(resp.Options),
+		ExpiresAt:   resp.ExpiresAt.Millis(),
+	}, nil
+}
+
+// FinishWebAuthnRegistration handles storing a passkey from the authenticator's response
+func (h *UserHandler) FinishWebAuthnRegistration(ctx context.Context, req *pb.FinishWebAuthnRegistrationRequest) (*pb.FinishWebAuthnRegistrationResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	resp, err := h.userService.FinishWebAuthnRegistration(ctx, dto.FinishWebAuthnRegistrationReq{
+		SessionID:  strings.TrimSpace(req.SessionId),
+		Credential: []byte(req.CredentialJson),
+	})
+	if err != nil {
+		logger.WithError(err).Error("WebAuthn registration failed")
+		return nil, err
+	}
+
+	return &pb.FinishWebAuthnRegistrationResponse{CredentialId: resp.CredentialID}, nil
+}
+
+// BeginWebAuthnLogin handles starting a password-less login
+func (h *UserHandler) BeginWebAuthnLogin(ctx context.Context, req *pb.BeginWebAuthnLoginRequest) (*pb.BeginWebAuthnLoginResponse, error) {
+	resp, err := h.userService.BeginWebAuthnLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.BeginWebAuthnLoginResponse{
+		SessionId:   resp.SessionID,
+		OptionsJson: string(resp.Options),
+		ExpiresAt:   resp.ExpiresAt.Millis(),
+	}, nil
+}
+
+// FinishWebAuthnLogin handles signing in with a passkey
+func (h *UserHandler) FinishWebAuthnLogin(ctx context.Context, req *pb.FinishWebAuthnLoginRequest) (*pb.LoginResponse, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	resp, err := h.userService.FinishWebAuthnLogin(ctx, dto.FinishWebAuthnLoginReq{
+		SessionID:  strings.TrimSpace(req.SessionId),
+		Credential: []byte(req.CredentialJson),
+		Device:     clientDevice(ctx, req.DeviceId),
+	})
+	if err != nil {
+		logger.WithError(err).Error("WebAuthn login failed")
+		return nil, err
+	}
+
+	return toLoginResponse(resp), nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	pb "wallet-user-svc/api/proto"
+	"wallet-user-svc/internal/app/errs"
 	"wallet-user-svc/internal/app/model/domain"
 	"wallet-user-svc/internal/app/model/dto"
 
@@ -28,6 +29,14 @@ func (m *MockUserService) Register(ctx context.Context, req dto.RegisterReq) (*d
 	return args.Get(0).(*dto.RegisterResp), args.Error(1)
 }
 
+func (m *MockUserService) AdminCreateUser(ctx context.Context, req dto.AdminCreateUserReq) (*dto.AdminCreateUserResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AdminCreateUserResp), args.Error(1)
+}
+
 func (m *MockUserService) Login(ctx context.Context, req dto.LoginReq) (*dto.LoginResp, error) {
 	args := m.Called(ctx, req)
 	if args.Get(0) == nil {
@@ -44,6 +53,157 @@ func (m *MockUserService) RefreshToken(ctx context.Context, req dto.RefreshToken
 	return args.Get(0).(*dto.RefreshTokenResp), args.Error(1)
 }
 
+func (m *MockUserService) RotateRefreshToken(ctx context.Context, req dto.RotateRefreshReq) (*dto.RotateRefreshResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RotateRefreshResp), args.Error(1)
+}
+
+func (m *MockUserService) EnrollTOTP(ctx context.Context) (*dto.EnrollTOTPResp, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.EnrollTOTPResp), args.Error(1)
+}
+
+func (m *MockUserService) VerifyTOTP(ctx context.Context, req dto.VerifyTOTPReq) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) CompleteLogin(ctx context.Context, req dto.CompleteLoginReq) (*dto.LoginResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoginResp), args.Error(1)
+}
+
+func (m *MockUserService) RegenerateBackupCodes(ctx context.Context, req dto.RegenerateBackupCodesReq) (*dto.RegenerateBackupCodesResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RegenerateBackupCodesResp), args.Error(1)
+}
+
+func (m *MockUserService) ListSessions(ctx context.Context) (*dto.ListSessionsResp, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ListSessionsResp), args.Error(1)
+}
+
+func (m *MockUserService) RevokeSession(ctx context.Context, req dto.RevokeSessionReq) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) GetNotificationPreferences(ctx context.Context) (*dto.GetNotificationPreferencesResp, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetNotificationPreferencesResp), args.Error(1)
+}
+
+func (m *MockUserService) UpdateNotificationPreferences(ctx context.Context, req dto.UpdateNotificationPreferencesReq) (*dto.UpdateNotificationPreferencesResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.UpdateNotificationPreferencesResp), args.Error(1)
+}
+
+func (m *MockUserService) ReEncryptSecrets(ctx context.Context, req dto.ReEncryptSecretsReq) (*dto.ReEncryptSecretsResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ReEncryptSecretsResp), args.Error(1)
+}
+
+func (m *MockUserService) GetTokenStats(ctx context.Context, req dto.GetTokenStatsReq) (*dto.GetTokenStatsResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GetTokenStatsResp), args.Error(1)
+}
+
+func (m *MockUserService) AddContactMethod(ctx context.Context, req dto.AddContactMethodReq) (*dto.AddContactMethodResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AddContactMethodResp), args.Error(1)
+}
+
+func (m *MockUserService) ConfirmContactMethod(ctx context.Context, req dto.ConfirmContactMethodReq) (*dto.ContactResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ContactResp), args.Error(1)
+}
+
+func (m *MockUserService) SetPrimaryContact(ctx context.Context, req dto.SetPrimaryContactReq) (*dto.ContactResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ContactResp), args.Error(1)
+}
+
+func (m *MockUserService) BeginWebAuthnRegistration(ctx context.Context) (*dto.BeginWebAuthnResp, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BeginWebAuthnResp), args.Error(1)
+}
+
+func (m *MockUserService) FinishWebAuthnRegistration(ctx context.Context, req dto.FinishWebAuthnRegistrationReq) (*dto.FinishWebAuthnRegistrationResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FinishWebAuthnRegistrationResp), args.Error(1)
+}
+
+func (m *MockUserService) BeginWebAuthnLogin(ctx context.Context) (*dto.BeginWebAuthnResp, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BeginWebAuthnResp), args.Error(1)
+}
+
+func (m *MockUserService) FinishWebAuthnLogin(ctx context.Context, req dto.FinishWebAuthnLoginReq) (*dto.LoginResp, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoginResp), args.Error(1)
+}
+
+// MockDiagnosticsService is a mock implementation of DiagnosticsService for testing
+type MockDiagnosticsService struct {
+	mock.Mock
+}
+
+func (m *MockDiagnosticsService) GetRuntimeInfo(ctx context.Context) (*dto.RuntimeInfoResp, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RuntimeInfoResp), args.Error(1)
+}
+
 func TestUserHandler_Register(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -83,14 +243,14 @@ func TestUserHandler_Register(t *testing.T) {
 			request: &pb.RegisterRequest{
 				Username:    "testuser",
 				Password:    "password123",
-				CountryCode: "+1",
-				Phone:       "1234567890",
+				CountryCode: "US",
+				Phone:       "+11234567890",
 			},
 			mockResponse: &dto.RegisterResp{
 				User: &domain.User{
 					ID:          uuid.New(),
 					Username:    func() domain.Username { u, _ := domain.NewUsername("testuser"); return u }(),
-					CountryCode: func() *domain.CountryCode { c, _ := domain.NewCountryCode("+1"); return &c }(),
+					CountryCode: func() *domain.CountryCode { c, _ := domain.NewCountryCode("US"); return &c }(),
 					Phone:       func() *domain.PhoneNumber { p, _ := domain.NewPhoneNumber("+11234567890"); return &p }(),
 				},
 				AccessToken:  "access_token_123",
@@ -100,7 +260,7 @@ func TestUserHandler_Register(t *testing.T) {
 			expectedError: false,
 			expectedFields: map[string]interface{}{
 				"username":      "testuser",
-				"country_code":  "+1",
+				"country_code":  "US",
 				"phone":         "+11234567890",
 				"access_token":  "access_token_123",
 				"refresh_token": "refresh_token_123",
@@ -123,7 +283,7 @@ func TestUserHandler_Register(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock service
 			mockService := new(MockUserService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			// Set up mock expectations
 			if tt.mockResponse != nil {
@@ -203,8 +363,8 @@ func TestUserHandler_Login(t *testing.T) {
 		{
 			name: "successful login with phone",
 			request: &pb.LoginRequest{
-				CountryCode: "+1",
-				Phone:       "1234567890",
+				CountryCode: "US",
+				Phone:       "+11234567890",
 				Password:    "password123",
 			},
 			mockResponse: &dto.LoginResp{
@@ -234,7 +394,7 @@ func TestUserHandler_Login(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock service
 			mockService := new(MockUserService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			// Set up mock expectations
 			if tt.mockResponse != nil {
@@ -269,6 +429,65 @@ func TestUserHandler_Login(t *testing.T) {
 	}
 }
 
+func TestUserHandler_LoginWithMFA(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, nil)
+	ctx := context.Background()
+
+	challengeID := uuid.New().String()
+	mockService.On("Login", mock.Anything, mock.Anything).Return(&dto.LoginResp{
+		MFARequired: true,
+		ChallengeID: challengeID,
+	}, nil)
+	mockService.On("CompleteLogin", mock.Anything, dto.CompleteLoginReq{ChallengeID: challengeID, Code: "123456"}).Return(&dto.LoginResp{
+		AccessToken:  "access_token_123",
+		RefreshToken: "refresh_token_123",
+	}, nil)
+
+	loginResp, err := handler.Login(ctx, &pb.LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.True(t, loginResp.MfaRequired)
+	assert.Equal(t, challengeID, loginResp.ChallengeId)
+	assert.Empty(t, loginResp.AccessToken, "no tokens before the second factor")
+
+	completeResp, err := handler.CompleteLogin(ctx, &pb.CompleteLoginRequest{ChallengeId: challengeID, Code: "123456"})
+	require.NoError(t, err)
+	assert.False(t, completeResp.MfaRequired)
+	assert.Equal(t, "access_token_123", completeResp.AccessToken)
+	assert.Equal(t, "refresh_token_123", completeResp.RefreshToken)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_TrimsIdentifiers(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, nil)
+	ctx := context.Background()
+
+	email := "test@example.com"
+	mockService.On("Register", mock.Anything, dto.RegisterReq{
+		Username: "testuser",
+		Password: " pass word123 ",
+		Email:    &email,
+	}).Return(&dto.RegisterResp{User: &domain.User{ID: uuid.New(), Username: "testuser"}}, nil)
+	mockService.On("Login", mock.Anything, dto.LoginReq{
+		Email:    email,
+		Password: " pass word123 ",
+	}).Return(&dto.LoginResp{}, nil)
+
+	_, err := handler.Register(ctx, &pb.RegisterRequest{
+		Email:    " test@example.com\t",
+		Username: "  testuser ",
+		Password: " pass word123 ",
+	})
+	require.NoError(t, err)
+
+	_, err = handler.Login(ctx, &pb.LoginRequest{Email: "\ntest@example.com ", Password: " pass word123 "})
+	require.NoError(t, err)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestUserHandler_RefreshToken(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -307,7 +526,7 @@ func TestUserHandler_RefreshToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock service
 			mockService := new(MockUserService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			// Set up mock expectations
 			if tt.mockResponse != nil {
@@ -341,6 +560,261 @@ func TestUserHandler_RefreshToken(t *testing.T) {
 	}
 }
 
+func TestUserHandler_RotateRefreshToken(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, nil)
+
+	mockService.On("RotateRefreshToken", mock.Anything, mock.MatchedBy(func(req dto.RotateRefreshReq) bool {
+		return req.RefreshToken == "valid_refresh_token" && req.Device.DeviceID == "device-a"
+	})).Return(&dto.RotateRefreshResp{
+		AccessToken:           "new_access_token_123",
+		RefreshToken:          "new_refresh_token_123",
+		AccessTokenExpiresAt:  1767225600000,
+		RefreshTokenExpiresAt: 1767830400000,
+	}, nil)
+
+	response, err := handler.RotateRefreshToken(context.Background(), &pb.RotateRefreshTokenRequest{
+		RefreshToken: "valid_refresh_token",
+		DeviceId:     "device-a",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "new_access_token_123", response.AccessToken)
+	assert.Equal(t, "new_refresh_token_123", response.RefreshToken)
+	assert.Equal(t, int64(1767225600000), response.AccessTokenExpiresAt)
+	assert.Equal(t, int64(1767830400000), response.RefreshTokenExpiresAt)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_GetRuntimeInfo(t *testing.T) {
+	startedAt := time.Now().Add(-time.Hour)
+
+	t.Run("successful runtime info", func(t *testing.T) {
+		mockDiagnostics := new(MockDiagnosticsService)
+		handler := NewUserHandler(new(MockUserService), mockDiagnostics)
+
+		mockDiagnostics.On("GetRuntimeInfo", mock.Anything).Return(&dto.RuntimeInfoResp{
+			Build:  dto.BuildInfo{GoVersion: "go1.24.4", Revision: "abc123"},
+			Config: map[string]string{"jwt.access_token_duration": "15m0s", "jwt.secret_key": "[REDACTED]"},
+			DatabasePool: dto.DatabasePoolStats{
+				OpenConnections: 3,
+				InUse:           1,
+				Idle:            2,
+				WaitDuration:    1500 * time.Millisecond,
+			},
+			Notifications: dto.NotificationStats{Dropped: 2},
+			StartedAt:     startedAt,
+			Uptime:        time.Hour,
+		}, nil)
+
+		response, err := handler.GetRuntimeInfo(context.Background(), &pb.GetRuntimeInfoRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", response.Build.Revision)
+		assert.Equal(t, "[REDACTED]", response.Config["jwt.secret_key"])
+		assert.Equal(t, int32(3), response.DatabasePool.OpenConnections)
+		assert.Equal(t, int64(1500), response.DatabasePool.WaitDurationMs)
+		assert.Equal(t, int64(2), response.Notifications.Dropped)
+		assert.Equal(t, startedAt.UnixMilli(), response.StartedAt)
+		assert.Equal(t, int64(3600), response.UptimeSeconds)
+		mockDiagnostics.AssertExpectations(t)
+	})
+
+	t.Run("diagnostics service error", func(t *testing.T) {
+		mockDiagnostics := new(MockDiagnosticsService)
+		handler := NewUserHandler(new(MockUserService), mockDiagnostics)
+
+		mockDiagnostics.On("GetRuntimeInfo", mock.Anything).Return(nil, assert.AnError)
+
+		response, err := handler.GetRuntimeInfo(context.Background(), &pb.GetRuntimeInfoRequest{})
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		mockDiagnostics.AssertExpectations(t)
+	})
+}
+
+func TestUserHandler_ReEncryptSecrets(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, new(MockDiagnosticsService))
+	afterUserID := uuid.New().String()
+	lastUserID := uuid.New().String()
+
+	mockService.On("ReEncryptSecrets", mock.Anything, dto.ReEncryptSecretsReq{
+		AfterUserID: afterUserID,
+		BatchSize:   50,
+		MaxBatches:  2,
+	}).Return(&dto.ReEncryptSecretsResp{Processed: 100, ReEncrypted: 40, Failed: 1, LastUserID: lastUserID}, nil)
+
+	response, err := handler.ReEncryptSecrets(context.Background(), &pb.ReEncryptSecretsRequest{
+		AfterUserId: " " + afterUserID + " ",
+		BatchSize:   50,
+		MaxBatches:  2,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(100), response.Processed)
+	assert.Equal(t, int32(40), response.ReEncrypted)
+	assert.Equal(t, int32(1), response.Failed)
+	assert.Equal(t, lastUserID, response.LastUserId)
+	assert.False(t, response.Done)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_GetTokenStats(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, new(MockDiagnosticsService))
+	userID := uuid.New().String()
+
+	mockService.On("GetTokenStats", mock.Anything, dto.GetTokenStatsReq{PageSize: 1, Offset: 2}).Return(&dto.GetTokenStatsResp{
+		Total: dto.TokenCounts{Active: 7, Revoked: 3, Expired: 2},
+		Users: []dto.UserTokenCounts{
+			{UserID: userID, Counts: dto.TokenCounts{Active: 5, Revoked: 1}},
+		},
+		NextOffset: 3,
+	}, nil)
+
+	response, err := handler.GetTokenStats(context.Background(), &pb.GetTokenStatsRequest{PageSize: 1, Offset: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), response.Total.Active)
+	assert.Equal(t, int64(3), response.Total.Revoked)
+	assert.Equal(t, int64(2), response.Total.Expired)
+	require.Len(t, response.Users, 1)
+	assert.Equal(t, userID, response.Users[0].UserId)
+	assert.Equal(t, int64(5), response.Users[0].Counts.Active)
+	assert.Equal(t, int64(1), response.Users[0].Counts.Revoked)
+	assert.Equal(t, int32(3), response.NextOffset)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_AddContactMethod(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, new(MockDiagnosticsService))
+	countryCode, phone := "TW", "+886912345678"
+	verificationID := uuid.New().String()
+
+	mockService.On("AddContactMethod", mock.Anything, dto.AddContactMethodReq{CountryCode: &countryCode, Phone: &phone}).Return(&dto.AddContactMethodResp{
+		VerificationID: verificationID,
+		Method:         domain.ContactMethodPhone,
+		ExpiresAt:      domain.Timestamp(1700000000000),
+	}, nil)
+
+	response, err := handler.AddContactMethod(context.Background(), &pb.AddContactMethodRequest{Email: " ", CountryCode: &countryCode, Phone: &phone})
+
+	require.NoError(t, err)
+	assert.Equal(t, verificationID, response.VerificationId)
+	assert.Equal(t, "phone", response.Method)
+	assert.Equal(t, int64(1700000000000), response.ExpiresAt)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_SetPrimaryContact(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, new(MockDiagnosticsService))
+	user, err := domain.NewUser("test@example.com", "$2a$10$hash", "testuser", nil, nil)
+	require.NoError(t, err)
+
+	mockService.On("SetPrimaryContact", mock.Anything, dto.SetPrimaryContactReq{Method: "email"}).Return(&dto.ContactResp{User: user}, nil)
+	mockService.On("SetPrimaryContact", mock.Anything, dto.SetPrimaryContactReq{Method: "phone"}).Return(nil, errs.ErrContactMethodMissing)
+
+	response, err := handler.SetPrimaryContact(context.Background(), &pb.SetPrimaryContactRequest{Method: "email"})
+	require.NoError(t, err)
+	assert.Equal(t, "email", response.User.PrimaryContact)
+
+	_, err = handler.SetPrimaryContact(context.Background(), &pb.SetPrimaryContactRequest{Method: "phone"})
+	assert.ErrorIs(t, err, errs.ErrContactMethodMissing)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_WebAuthnLogin(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, new(MockDiagnosticsService))
+	sessionID := uuid.New().String()
+	credential := `{"id":"abc","type":"public-key"}`
+
+	mockService.On("BeginWebAuthnLogin", mock.Anything).Return(&dto.BeginWebAuthnResp{
+		SessionID: sessionID,
+		Options:   []byte(`{"publicKey":{}}`),
+		ExpiresAt: domain.Timestamp(1700000000000),
+	}, nil)
+	mockService.On("FinishWebAuthnLogin", mock.Anything, dto.FinishWebAuthnLoginReq{
+		SessionID:  sessionID,
+		Credential: []byte(credential),
+		Device:     dto.ClientDevice{DeviceID: "device-1"},
+	}).Return(&dto.LoginResp{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	begin, err := handler.BeginWebAuthnLogin(context.Background(), &pb.BeginWebAuthnLoginRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, begin.SessionId)
+	assert.JSONEq(t, `{"publicKey":{}}`, begin.OptionsJson)
+	assert.Equal(t, int64(1700000000000), begin.ExpiresAt)
+
+	response, err := handler.FinishWebAuthnLogin(context.Background(), &pb.FinishWebAuthnLoginRequest{
+		SessionId:      " " + sessionID + " ",
+		CredentialJson: credential,
+		DeviceId:       "device-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "access", response.AccessToken)
+	assert.Equal(t, "refresh", response.RefreshToken)
+	mockService.AssertExpectations(t)
+}
+
+func TestToPBUser(t *testing.T) {
+	email := domain.Email("test@example.com")
+	countryCode := domain.CountryCode("TW")
+	phone := domain.PhoneNumber("+886912345678")
+	empty := domain.Email("")
+
+	tests := []struct {
+		name        string
+		email       *domain.Email
+		countryCode *domain.CountryCode
+		phone       *domain.PhoneNumber
+	}{
+		{name: "no optional fields"},
+		{name: "email only", email: &email},
+		{name: "phone only", countryCode: &countryCode, phone: &phone},
+		{name: "email and phone", email: &email, countryCode: &countryCode, phone: &phone},
+		{name: "country code without phone", countryCode: &countryCode},
+		{name: "phone without country code", phone: &phone},
+		{name: "empty email", email: &empty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{
+				ID:             uuid.New(),
+				Username:       "testuser",
+				Email:          tt.email,
+				CountryCode:    tt.countryCode,
+				Phone:          tt.phone,
+				PrimaryContact: domain.ContactMethodEmail,
+			}
+
+			pbUser := toPBUser(user)
+
+			assert.Equal(t, user.ID.String(), pbUser.Id)
+			assert.Equal(t, "testuser", pbUser.Username)
+			assert.Equal(t, "email", pbUser.PrimaryContact)
+			assertOptional(t, tt.email, pbUser.Email)
+			assertOptional(t, tt.countryCode, pbUser.CountryCode)
+			assertOptional(t, tt.phone, pbUser.Phone)
+		})
+	}
+}
+
+// assertOptional checks that an optional proto field is set exactly when the domain value is
+func assertOptional[T ~string](t *testing.T, expected *T, actual *string) {
+	t.Helper()
+	if expected == nil || *expected == "" {
+		assert.Nil(t, actual)
+		return
+	}
+	require.NotNil(t, actual)
+	assert.Equal(t, string(*expected), *actual)
+}
+
 // Integration test helper functions
 func TestUserHandler_Integration(t *testing.T) {
 	t.Skip("Integration test - requires running service and database")
@@ -361,7 +835,7 @@ func TestUserHandler_Integration(t *testing.T) {
 // Benchmark tests
 func BenchmarkUserHandler_Register(b *testing.B) {
 	mockService := new(MockUserService)
-	handler := NewUserHandler(mockService)
+	handler := NewUserHandler(mockService, nil)
 
 	// Set up mock response
 	mockResponse := &dto.RegisterResp{
@@ -0,0 +1,66 @@
+package domain
+
+import "wallet-user-svc/internal/app/errs"
+
+// AccountStatus is the lifecycle state of an account
+type AccountStatus string
+
+const (
+	// AccountStatusActive accounts can sign in
+	AccountStatusActive AccountStatus = "active"
+	// AccountStatusDisabled accounts were switched off by an admin
+	AccountStatusDisabled AccountStatus = "disabled"
+	// AccountStatusLocked accounts are blocked, e.g. after suspicious activity, until
+	// they are unlocked
+	AccountStatusLocked AccountStatus = "locked"
+)
+
+// NewAccountStatus creates a new AccountStatus and validates it
+func NewAccountStatus(status string) (AccountStatus, error) {
+	s := AccountStatus(status)
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// Validate checks if the status is a known account status
+func (s AccountStatus) Validate() error {
+	switch s {
+	case AccountStatusActive, AccountStatusDisabled, AccountStatusLocked:
+		return nil
+	default:
+		return errs.ErrInvalidAccountStatus
+	}
+}
+
+// String returns the account status as a string
+func (s AccountStatus) String() string {
+	return string(s)
+}
+
+// AccountState groups the flags that gate what a user may do with their account, so
+// the decisions based on them are made in one place
+type AccountState struct {
+	Status AccountStatus `json:"status" `
+	// EmailVerified is set once the email address is known to belong to the user
+	EmailVerified bool `json:"emailVerified" `
+	// MustChangePassword is set for temporary passwords the user has to replace
+	MustChangePassword bool `json:"mustChangePassword" `
+}
+
+// CanLogin reports whether the account may authenticate, with the reason as error when
+// it may not. Unverified emails and temporary passwords do not prevent signing in; the
+// client is told to complete them after login. An unset status counts as active.
+func (a AccountState) CanLogin() (bool, error) {
+	switch a.Status {
+	case AccountStatusActive, "":
+		return true, nil
+	case AccountStatusDisabled:
+		return false, errs.ErrAccountDisabled
+	case AccountStatusLocked:
+		return false, errs.ErrAccountLocked
+	default:
+		return false, errs.ErrInvalidAccountStatus
+	}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountState_CanLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   AccountState
+		allowed bool
+		err     error
+	}{
+		{name: "active", state: AccountState{Status: AccountStatusActive}, allowed: true},
+		{name: "unset status", state: AccountState{}, allowed: true},
+		{name: "unverified email and temporary password", state: AccountState{Status: AccountStatusActive, MustChangePassword: true}, allowed: true},
+		{name: "disabled", state: AccountState{Status: AccountStatusDisabled}, err: errs.ErrAccountDisabled},
+		{name: "locked", state: AccountState{Status: AccountStatusLocked, EmailVerified: true}, err: errs.ErrAccountLocked},
+		{name: "unknown status", state: AccountState{Status: "deleted"}, err: errs.ErrInvalidAccountStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := tt.state.CanLogin()
+			assert.Equal(t, tt.allowed, allowed)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+// backupCodeAlphabet omits characters that are easily confused (0/O, 1/I/L)
+const backupCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// backupCodeGroupLength is the length of each half of an "XXXXX-XXXXX" code
+const backupCodeGroupLength = 5
+
+// GenerateBackupCodes returns count random single-use recovery codes in "XXXXX-XXXXX" form
+func GenerateBackupCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	for range count {
+		var code strings.Builder
+		for i := range backupCodeGroupLength * 2 {
+			if i == backupCodeGroupLength {
+				code.WriteByte('-')
+			}
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(backupCodeAlphabet))))
+			if err != nil {
+				return nil, err
+			}
+			code.WriteByte(backupCodeAlphabet[n.Int64()])
+		}
+		codes = append(codes, code.String())
+	}
+
+	return codes, nil
+}
+
+// HashBackupCode returns the hex SHA-256 of a backup code after normalizing case,
+// spaces and dashes. Codes are random and high entropy, so a fast hash is sufficient.
+func HashBackupCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
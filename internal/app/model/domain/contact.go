@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/google/uuid"
+)
+
+// ContactMethod names how a user is reached: by email or by phone
+type ContactMethod string
+
+const (
+	ContactMethodEmail ContactMethod = "email"
+	ContactMethodPhone ContactMethod = "phone"
+)
+
+// NewContactMethod creates a new ContactMethod and validates it
+func NewContactMethod(method string) (ContactMethod, error) {
+	m := ContactMethod(method)
+	if err := m.Validate(); err != nil {
+		return "", err
+	}
+	return m, nil
+}
+
+// Validate checks if the method is a known contact method
+func (m ContactMethod) Validate() error {
+	switch m {
+	case ContactMethodEmail, ContactMethodPhone:
+		return nil
+	default:
+		return errs.ErrInvalidContactMethod
+	}
+}
+
+// String returns the contact method as a string
+func (m ContactMethod) String() string {
+	return string(m)
+}
+
+// MaxContactVerificationAttempts is the number of wrong codes after which a verification
+// is rejected
+const MaxContactVerificationAttempts = 5
+
+// verificationCodeDigits is the length of the numeric codes sent to a new contact
+const verificationCodeDigits = 6
+
+// ContactVerification is a pending change of a user's email or phone. The contact is
+// only applied once the user enters the code sent to it, proving they can receive it.
+type ContactVerification struct {
+	ID          uuid.UUID     `json:"id"`
+	UserID      uuid.UUID     `json:"userId"`
+	Method      ContactMethod `json:"method"`
+	Email       *Email        `json:"email,omitempty"`
+	CountryCode *CountryCode  `json:"countryCode,omitempty"`
+	Phone       *PhoneNumber  `json:"phone,omitempty"`
+	// CodeHash is the HashVerificationCode of the code sent to the contact
+	CodeHash   string     `json:"-"`
+	Attempts   int        `json:"attempts"`
+	ExpiresAt  Timestamp  `json:"expiresAt"`
+	ConsumedAt *Timestamp `json:"consumedAt,omitempty"`
+	CreatedAt  Timestamp  `json:"createdAt"`
+}
+
+// NewContactVerification creates a verification of either email or a country code and
+// phone pair for userID that expires after ttl. It validates the contact with the same
+// rules as registration.
+func NewContactVerification(userID uuid.UUID, email string, countryCode, phone *string, codeHash string, ttl time.Duration) (*ContactVerification, error) {
+	if userID == uuid.Nil {
+		return nil, errs.ErrInvalidVerification
+	}
+
+	hasPhone := (countryCode != nil && *countryCode != "") || (phone != nil && *phone != "")
+	if email != "" && hasPhone {
+		return nil, errs.ErrInvalidContact
+	}
+	if err := validateUserInput(email, countryCode, phone); err != nil {
+		return nil, errs.ErrInvalidContact
+	}
+
+	emailObj, countryCodeObj, phoneObj, err := createContactInfo(email, countryCode, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	method := ContactMethodEmail
+	if emailObj == nil {
+		method = ContactMethodPhone
+	}
+
+	now := time.Now()
+	return &ContactVerification{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Method:      method,
+		Email:       emailObj,
+		CountryCode: countryCodeObj,
+		Phone:       phoneObj,
+		CodeHash:    codeHash,
+		ExpiresAt:   FromTime(now.Add(ttl)),
+		CreatedAt:   FromTime(now),
+	}, nil
+}
+
+// IsValid checks that the verification can still be completed
+func (v *ContactVerification) IsValid() error {
+	if v.ConsumedAt != nil {
+		return errs.ErrInvalidVerification
+	}
+
+	if v.Attempts >= MaxContactVerificationAttempts {
+		return errs.ErrInvalidVerification
+	}
+
+	if v.ExpiresAt <= Now() {
+		return errs.ErrInvalidVerification
+	}
+
+	return nil
+}
+
+// GenerateVerificationCode returns a random numeric code to send to a new contact
+func GenerateVerificationCode() (string, error) {
+	limit := big.NewInt(1)
+	for range verificationCodeDigits {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", verificationCodeDigits, n.Int64()), nil
+}
+
+// HashVerificationCode returns the hex SHA-256 of a verification code. Codes are short,
+// but they expire within minutes and allow MaxContactVerificationAttempts guesses.
+func HashVerificationCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
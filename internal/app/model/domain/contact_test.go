@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContactVerification(t *testing.T) {
+	userID := uuid.New()
+	countryCode, phone := "TW", "+886912345678"
+
+	tests := []struct {
+		name        string
+		email       string
+		countryCode *string
+		phone       *string
+		method      ContactMethod
+		err         error
+	}{
+		{name: "email", email: "new@example.com", method: ContactMethodEmail},
+		{name: "phone", countryCode: &countryCode, phone: &phone, method: ContactMethodPhone},
+		{name: "none", err: errs.ErrInvalidContact},
+		{name: "both", email: "new@example.com", countryCode: &countryCode, phone: &phone, err: errs.ErrInvalidContact},
+		{name: "phone without country code", phone: &phone, err: errs.ErrInvalidContact},
+		{name: "invalid email", email: "not-an-email", err: errs.ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verification, err := NewContactVerification(userID, tt.email, tt.countryCode, tt.phone, "hash", time.Minute)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.method, verification.Method)
+			assert.NoError(t, verification.IsValid())
+		})
+	}
+}
+
+func TestContactVerification_IsValid(t *testing.T) {
+	verification, err := NewContactVerification(uuid.New(), "new@example.com", nil, nil, "hash", time.Minute)
+	require.NoError(t, err)
+
+	verification.Attempts = MaxContactVerificationAttempts
+	assert.ErrorIs(t, verification.IsValid(), errs.ErrInvalidVerification)
+
+	verification.Attempts = 0
+	verification.ExpiresAt = FromTime(time.Now().Add(-time.Second))
+	assert.ErrorIs(t, verification.IsValid(), errs.ErrInvalidVerification)
+}
+
+func TestGenerateVerificationCode(t *testing.T) {
+	code, err := GenerateVerificationCode()
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^\d{6}$`), code)
+	assert.Equal(t, HashVerificationCode(code), HashVerificationCode(code))
+	assert.NotEqual(t, HashVerificationCode(code), HashVerificationCode(code+"0"))
+}
+
+func TestUser_SetPrimaryContact(t *testing.T) {
+	countryCode, phone := "TW", "+886912345678"
+	user, err := NewUser("", "$2a$10$hash", "phoneuser", &countryCode, &phone)
+	require.NoError(t, err)
+	assert.Equal(t, ContactMethodPhone, user.PrimaryContact)
+
+	assert.ErrorIs(t, user.SetPrimaryContact(ContactMethodEmail), errs.ErrContactMethodMissing)
+	assert.ErrorIs(t, user.SetPrimaryContact("fax"), errs.ErrInvalidContactMethod)
+
+	verification, err := NewContactVerification(user.ID, "new@example.com", nil, nil, "hash", time.Minute)
+	require.NoError(t, err)
+	user.ApplyContactVerification(verification)
+	assert.True(t, user.EmailVerified)
+	assert.True(t, user.HasContact(ContactMethodPhone), "the phone is kept")
+
+	require.NoError(t, user.SetPrimaryContact(ContactMethodEmail))
+	assert.Equal(t, ContactMethodEmail, user.PrimaryContact)
+}
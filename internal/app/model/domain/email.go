@@ -1,13 +1,36 @@
 package domain
 
-import "wallet-user-svc/internal/app/errs"
+import (
+	"strings"
+
+	"wallet-user-svc/internal/app/errs"
+)
+
+// freeEmailProviders are domains of widely used free mailbox providers
+var freeEmailProviders = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+	"yahoo.com":      true,
+	"hotmail.com":    true,
+	"outlook.com":    true,
+	"live.com":       true,
+	"aol.com":        true,
+	"icloud.com":     true,
+	"mail.com":       true,
+	"gmx.com":        true,
+	"yandex.com":     true,
+	"proton.me":      true,
+	"protonmail.com": true,
+}
 
 // Email represents a validated email address
 type Email string
 
-// NewEmail creates a new Email and validates it
+// NewEmail creates a new Email and validates it. The email is normalized first:
+// surrounding whitespace, typically left by copy-paste or autocomplete, is trimmed and
+// the address is lowercased.
 func NewEmail(email string) (Email, error) {
-	e := Email(email)
+	e := Email(NormalizeEmail(email))
 	if err := e.Validate(); err != nil {
 		return "", err
 	}
@@ -99,6 +122,16 @@ func (e Email) hasDotInDomain(domain string) bool {
 	return false
 }
 
+// IsFreeProvider reports whether the address belongs to a free mailbox provider
+func (e Email) IsFreeProvider() bool {
+	email := string(e)
+	atIndex := e.findAtSymbol(email)
+	if atIndex == -1 {
+		return false
+	}
+	return freeEmailProviders[strings.ToLower(email[atIndex+1:])]
+}
+
 // String returns the email as a string
 func (e Email) String() string {
 	return string(e)
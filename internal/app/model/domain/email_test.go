@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmail_TrimsWhitespace(t *testing.T) {
+	for _, input := range []string{"foo@bar.com", " foo@bar.com ", "\tfoo@bar.com\n"} {
+		email, err := NewEmail(input)
+		require.NoError(t, err, "input %q", input)
+		assert.Equal(t, Email("foo@bar.com"), email)
+	}
+
+	_, err := NewEmail("   ")
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "strings"
+
+// The Normalize functions bring identifiers into the form they are stored in. Both the
+// write path (registration, adding a contact) and the read path (login and other
+// lookups) apply them, so a user is always found by the identifier they registered
+// with, whatever case or formatting they type it in. Normalizing does not validate.
+
+// phoneSeparators are the characters people group phone digits with
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// NormalizeEmail trims an email and lowercases it. Mail providers treat addresses
+// case-insensitively in practice, so two addresses differing only in case name the
+// same mailbox and the same user.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone strips whitespace and the separators of formatted numbers, such as
+// "+1 (415) 555-0123", and completes a national-format number with defaultDialingCode
+// as WithDefaultDialingCode does, so numbers end up in E.164 form
+func NormalizePhone(phone, defaultDialingCode string) string {
+	return WithDefaultDialingCode(phoneSeparators.Replace(strings.TrimSpace(phone)), defaultDialingCode)
+}
+
+// NormalizeCountryCode trims an ISO 3166-1 alpha-2 code. Its case is kept: codes are
+// required in uppercase, so a lowercase code is rejected rather than silently accepted.
+func NormalizeCountryCode(code string) string {
+	return strings.TrimSpace(code)
+}
+
+// NormalizeUsername trims a username. Its case is kept, since usernames are displayed as
+// the user chose them; UsernameKey is the form compared for uniqueness and lookups.
+func NormalizeUsername(username string) string {
+	return strings.TrimSpace(username)
+}
+
+// UsernameKey returns the case-insensitive key of a username, the value stored in
+// unique_username and looked up by
+func UsernameKey(username string) string {
+	return strings.ToLower(NormalizeUsername(username))
+}
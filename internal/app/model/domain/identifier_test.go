@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	for _, input := range []string{"alice@example.com", "Alice@Example.COM", "  ALICE@example.com\n"} {
+		assert.Equal(t, "alice@example.com", NormalizeEmail(input), "input %q", input)
+	}
+
+	email, err := NewEmail(" Alice@Example.com ")
+	assert.NoError(t, err)
+	assert.Equal(t, Email("alice@example.com"), email, "emails are stored normalized")
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name        string
+		phone       string
+		dialingCode string
+		expected    string
+	}{
+		{name: "E.164 number is kept", phone: "+14155550123", expected: "+14155550123"},
+		{name: "separators are removed", phone: " +1 (415) 555-0123 ", expected: "+14155550123"},
+		{name: "dotted number", phone: "+886.912.345.678", expected: "+886912345678"},
+		{name: "national number gets the default", phone: "912 345 678", dialingCode: "+886", expected: "+886912345678"},
+		{name: "no default keeps the national number", phone: "912-345-678", expected: "912345678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizePhone(tt.phone, tt.dialingCode))
+		})
+	}
+
+	phone, err := NewPhoneNumber("+886 912-345-678")
+	assert.NoError(t, err)
+	assert.Equal(t, PhoneNumber("+886912345678"), phone, "phones are stored normalized")
+}
+
+func TestNormalizeCountryCode(t *testing.T) {
+	assert.Equal(t, "TW", NormalizeCountryCode(" TW "))
+	assert.Equal(t, "tw", NormalizeCountryCode("tw"), "case is kept so lowercase codes stay invalid")
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	assert.Equal(t, "TestUser", NormalizeUsername("  TestUser "), "case is kept for display")
+	assert.Equal(t, "testuser", UsernameKey("  TestUser "))
+	assert.Equal(t, UsernameKey("testuser"), UsernameKey("TESTUSER"))
+}
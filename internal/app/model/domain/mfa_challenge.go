@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/google/uuid"
+)
+
+// MaxMFAChallengeAttempts is the number of wrong codes after which a challenge is rejected
+const MaxMFAChallengeAttempts = 5
+
+// MFAChallenge is a pending second-factor step between password verification and
+// token issuance for users with TOTP enabled
+type MFAChallenge struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"userId"`
+	Attempts   int        `json:"attempts"`
+	ExpiresAt  Timestamp  `json:"expiresAt"`
+	ConsumedAt *Timestamp `json:"consumedAt,omitempty"`
+	CreatedAt  Timestamp  `json:"createdAt"`
+}
+
+// NewMFAChallenge creates a challenge for userID that expires after ttl
+func NewMFAChallenge(userID uuid.UUID, ttl time.Duration) (*MFAChallenge, error) {
+	if userID == uuid.Nil {
+		return nil, errs.ErrInvalidMFAChallenge
+	}
+
+	now := time.Now()
+	return &MFAChallenge{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ExpiresAt: FromTime(now.Add(ttl)),
+		CreatedAt: FromTime(now),
+	}, nil
+}
+
+// IsValid checks that the challenge can still be completed
+func (c *MFAChallenge) IsValid() error {
+	if c.ConsumedAt != nil {
+		return errs.ErrInvalidMFAChallenge
+	}
+
+	if c.Attempts >= MaxMFAChallengeAttempts {
+		return errs.ErrInvalidMFAChallenge
+	}
+
+	if c.ExpiresAt <= Now() {
+		return errs.ErrInvalidMFAChallenge
+	}
+
+	return nil
+}
@@ -17,6 +17,11 @@ type NotificationEventLog struct {
 	EventName string                     `db:"event_name" json:"eventName"`
 	Payload   json.RawMessage            `db:"payload" json:"payload"`
 	Status    NotificationEventLogStatus `db:"status" json:"status"`
-	CreatedAt int64                      `db:"created_at" json:"createdAt"`
-	UpdatedAt int64                      `db:"updated_at" json:"updatedAt"`
+	Priority  int                        `db:"priority" json:"priority"`
+	Attempts  int                        `db:"attempts" json:"attempts"`
+	CreatedAt Timestamp                  `db:"created_at" json:"createdAt"`
+	UpdatedAt Timestamp                  `db:"updated_at" json:"updatedAt"`
+	// DeliveredChannels lists the channels the event was already delivered to; a retry
+	// only sends to the others
+	DeliveredChannels []string `db:"delivered_channels" json:"deliveredChannels,omitempty"`
 }
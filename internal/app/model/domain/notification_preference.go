@@ -0,0 +1,10 @@
+package domain
+
+import "github.com/google/uuid"
+
+// NotificationPreference records whether a user receives notifications of one event type
+type NotificationPreference struct {
+	UserID    uuid.UUID `json:"userId"`
+	EventType string    `json:"eventType"`
+	Enabled   bool      `json:"enabled"`
+}
@@ -1,6 +1,35 @@
 package domain
 
-import "wallet-user-svc/internal/app/errs"
+import (
+	"strings"
+
+	"wallet-user-svc/internal/app/errs"
+)
+
+// commonPasswords are frequently breached passwords that still satisfy the character
+// class rules, stored lowercased
+var commonPasswords = map[string]bool{
+	"password1":    true,
+	"password1!":   true,
+	"password12":   true,
+	"password123":  true,
+	"password123!": true,
+	"passw0rd":     true,
+	"p@ssw0rd":     true,
+	"p@ssword1":    true,
+	"welcome1":     true,
+	"welcome123":   true,
+	"qwerty123":    true,
+	"qwerty123!":   true,
+	"admin123":     true,
+	"abc12345":     true,
+	"abcd1234":     true,
+	"letmein1":     true,
+	"iloveyou1":    true,
+	"monkey123":    true,
+	"football1":    true,
+	"sunshine1":    true,
+}
 
 // Password represents a validated password
 type Password string
@@ -78,6 +107,11 @@ func (p Password) Validate() error {
 	return nil
 }
 
+// IsCommon reports whether the password is on the common password list, ignoring case
+func (p Password) IsCommon() bool {
+	return commonPasswords[strings.ToLower(string(p))]
+}
+
 // String returns the password as a string
 func (p Password) String() string {
 	return string(p)
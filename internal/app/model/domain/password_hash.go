@@ -17,10 +17,10 @@ func NewPasswordHash(hash string) (PasswordHash, error) {
 	return ph, nil
 }
 
-// NewPasswordHashFromPlain creates a new PasswordHash from a plain text password
-func NewPasswordHashFromPlain(plainPassword string) (PasswordHash, error) {
-	hasher := password.DefaultHasher()
-	hashedPassword, err := hasher.HashPassword(plainPassword)
+// NewPasswordHashFromPlain creates a new PasswordHash from a plain text password. A nil
+// hasher hashes with the default cost and no pepper.
+func NewPasswordHashFromPlain(hasher *password.Hasher, plainPassword string) (PasswordHash, error) {
+	hashedPassword, err := hasherOrDefault(hasher).HashPassword(plainPassword)
 	if err != nil {
 		return "", err
 	}
@@ -40,8 +40,15 @@ func (ph PasswordHash) String() string {
 	return string(ph)
 }
 
-// VerifyPassword checks if the password hash matches the provided password
-func (ph PasswordHash) VerifyPassword(plainPassword string) bool {
-	hasher := password.DefaultHasher()
-	return hasher.VerifyPassword(string(ph), plainPassword)
+// VerifyPassword checks if the password hash matches the provided password. The hasher
+// must carry the pepper the hash was created with; nil verifies without a pepper.
+func (ph PasswordHash) VerifyPassword(hasher *password.Hasher, plainPassword string) bool {
+	return hasherOrDefault(hasher).VerifyPassword(string(ph), plainPassword)
+}
+
+func hasherOrDefault(hasher *password.Hasher) *password.Hasher {
+	if hasher == nil {
+		return password.DefaultHasher()
+	}
+	return hasher
 }
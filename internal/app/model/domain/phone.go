@@ -2,13 +2,16 @@ package domain
 
 import (
 	"regexp"
+	"strings"
 	"wallet-user-svc/internal/app/errs"
 )
 
 type PhoneNumber string
 
+// NewPhoneNumber creates a new PhoneNumber from a number in E.164 form, formatting
+// separators such as spaces and dashes allowed
 func NewPhoneNumber(phone string) (PhoneNumber, error) {
-	p := PhoneNumber(phone)
+	p := PhoneNumber(NormalizePhone(phone, ""))
 	if err := p.Validate(); err != nil {
 		return "", err
 	}
@@ -62,10 +65,14 @@ func (p PhoneNumber) ToPtrString() *string {
 	return &s
 }
 
+// CountryCode is an ISO 3166-1 alpha-2 region code (e.g. "US", "TW") identifying the
+// user's country. It is not a dialing code: PhoneNumber is stored in E.164 form and
+// already carries its "+<dialing code>" prefix.
 type CountryCode string
 
+// NewCountryCode creates a new CountryCode; surrounding whitespace is trimmed
 func NewCountryCode(code string) (CountryCode, error) {
-	c := CountryCode(code)
+	c := CountryCode(NormalizeCountryCode(code))
 	if err := c.Validate(); err != nil {
 		return "", err
 	}
@@ -85,8 +92,9 @@ func NewCountryCodePtr(code *string) (*CountryCode, error) {
 	return &countryCode, nil
 }
 
+// Validate checks that the code is a known uppercase ISO 3166-1 alpha-2 code
 func (c CountryCode) Validate() error {
-	if len(c) != 2 {
+	if _, ok := isoCountryCodes[string(c)]; !ok {
 		return errs.ErrInvalidCountryCode
 	}
 
@@ -100,4 +108,13 @@ func (c CountryCode) IsSet() bool {
 func (c CountryCode) ToPtrString() *string {
 	s := string(c)
 	return &s
-}
\ No newline at end of file
+}
+// WithDefaultDialingCode prefixes a national-format phone number, i.e. one without the
+// leading "+", with defaultDialingCode (e.g. "+886"). Numbers already in E.164 form and
+// an empty defaultDialingCode leave phone unchanged. The result still has to pass Validate.
+func WithDefaultDialingCode(phone, defaultDialingCode string) string {
+	if phone == "" || defaultDialingCode == "" || strings.HasPrefix(phone, "+") {
+		return phone
+	}
+	return defaultDialingCode + phone
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDefaultDialingCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		phone       string
+		dialingCode string
+		expected    string
+	}{
+		{name: "bare number gets the default", phone: "912345678", dialingCode: "+886", expected: "+886912345678"},
+		{name: "E.164 number is kept", phone: "+14155550123", dialingCode: "+886", expected: "+14155550123"},
+		{name: "no default keeps the bare number", phone: "912345678", dialingCode: "", expected: "912345678"},
+		{name: "empty phone stays empty", phone: "", dialingCode: "+886", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, WithDefaultDialingCode(tt.phone, tt.dialingCode))
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package domain
 
 import (
-	"time"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 
 	"wallet-user-svc/internal/app/errs"
 
@@ -13,14 +15,30 @@ type RefreshToken struct {
 	ID        uuid.UUID `json:"id"`
 	UserID    uuid.UUID `json:"userId"`
 	Token     string    `json:"token"`
-	ExpiresAt int64     `json:"expiresAt"`
+	ExpiresAt Timestamp `json:"expiresAt"`
 	IsRevoked bool      `json:"isRevoked"`
-	CreatedAt int64     `json:"createdAt"`
-	UpdatedAt int64     `json:"updatedAt"`
+	CreatedAt Timestamp `json:"createdAt"`
+	UpdatedAt Timestamp `json:"updatedAt"`
+	// Fingerprint is the DeviceFingerprint of the client the token was issued to;
+	// empty for tokens issued before fingerprints were recorded
+	Fingerprint string `json:"-"`
+	// FamilyID is shared by all tokens rotated from the same login; it is the ID of
+	// the first token of the family
+	FamilyID uuid.UUID `json:"familyId"`
+	// ReplacedBy is the ID of the token issued when this one was rotated, nil until
+	// the token is rotated
+	ReplacedBy *uuid.UUID `json:"replacedBy,omitempty"`
+}
+
+// DeviceFingerprint hashes the user agent and the client-provided device ID that
+// identify the client a refresh token is issued to
+func DeviceFingerprint(userAgent, deviceID string) string {
+	sum := sha256.Sum256([]byte(userAgent + "\x00" + deviceID))
+	return hex.EncodeToString(sum[:])
 }
 
 // NewRefreshToken creates a new RefreshToken
-func NewRefreshToken(userID uuid.UUID, tokenHash string, expiresAt int64) (*RefreshToken, error) {
+func NewRefreshToken(userID uuid.UUID, tokenHash string, fingerprint string, expiresAt Timestamp) (*RefreshToken, error) {
 	if userID == uuid.Nil {
 		return nil, errs.ErrInvalidToken
 	}
@@ -29,21 +47,56 @@ func NewRefreshToken(userID uuid.UUID, tokenHash string, expiresAt int64) (*Refr
 		return nil, errs.ErrInvalidToken
 	}
 
-	if expiresAt <= time.Now().UnixMilli() {
+	now := Now()
+	if expiresAt <= now {
 		return nil, errs.ErrTokenExpired
 	}
 
+	id := uuid.New()
 	return &RefreshToken{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Token:     tokenHash,
-		ExpiresAt: expiresAt,
-		IsRevoked: false,
-		CreatedAt: time.Now().UnixMilli(),
-		UpdatedAt: time.Now().UnixMilli(),
+		ID:          id,
+		UserID:      userID,
+		Token:       tokenHash,
+		Fingerprint: fingerprint,
+		FamilyID:    id,
+		ExpiresAt:   expiresAt,
+		IsRevoked:   false,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}, nil
 }
 
+// Rotate creates the token that replaces rt. The successor joins the family of rt and
+// keeps its fingerprint; storing the successor's ID as the ReplacedBy of rt is left to
+// the repository so that concurrent rotations of rt can be detected.
+func (rt *RefreshToken) Rotate(tokenHash string, expiresAt Timestamp) (*RefreshToken, error) {
+	if rt.IsRotated() {
+		return nil, errs.ErrTokenRevoked
+	}
+
+	next, err := NewRefreshToken(rt.UserID, tokenHash, rt.Fingerprint, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	next.FamilyID = rt.FamilyID
+	return next, nil
+}
+
+// IsRotated reports whether the token has been replaced by a newer one. Presenting a
+// rotated token means it was replayed, e.g. after being stolen.
+func (rt *RefreshToken) IsRotated() bool {
+	return rt.ReplacedBy != nil
+}
+
+// MatchesFingerprint reports whether the token was issued to the client with the given
+// fingerprint. Tokens without a recorded fingerprint are not bound and always match.
+func (rt *RefreshToken) MatchesFingerprint(fingerprint string) bool {
+	if rt.Fingerprint == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(rt.Fingerprint), []byte(fingerprint)) == 1
+}
+
 // IsValid checks if the refresh token is valid
 func (rt *RefreshToken) IsValid() error {
 	if rt.ID == uuid.Nil {
@@ -62,7 +115,7 @@ func (rt *RefreshToken) IsValid() error {
 		return errs.ErrTokenRevoked
 	}
 
-	if rt.ExpiresAt <= time.Now().UnixMilli() {
+	if rt.ExpiresAt <= Now() {
 		return errs.ErrTokenExpired
 	}
 
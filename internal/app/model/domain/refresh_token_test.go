@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshToken_Rotate(t *testing.T) {
+	expiresAt := FromTime(time.Now().Add(time.Hour))
+	first, err := NewRefreshToken(uuid.New(), "first", "fingerprint", expiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, first.FamilyID, "a new token starts its own family")
+
+	second, err := first.Rotate("second", expiresAt)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.Equal(t, first.FamilyID, second.FamilyID)
+	assert.Equal(t, first.UserID, second.UserID)
+	assert.Equal(t, first.Fingerprint, second.Fingerprint)
+	assert.False(t, second.IsRotated())
+
+	first.ReplacedBy = &second.ID
+	assert.True(t, first.IsRotated())
+	_, err = first.Rotate("third", expiresAt)
+	assert.ErrorIs(t, err, errs.ErrTokenRevoked, "a rotated token cannot be rotated again")
+}
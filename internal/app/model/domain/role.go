@@ -0,0 +1,40 @@
+package domain
+
+import "wallet-user-svc/internal/app/errs"
+
+// Role represents the authorization role of a user
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// NewRole creates a new Role and validates it
+func NewRole(role string) (Role, error) {
+	r := Role(role)
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+	return r, nil
+}
+
+// Validate checks if the role is a known role
+func (r Role) Validate() error {
+	switch r {
+	case RoleUser, RoleAdmin:
+		return nil
+	default:
+		return errs.ErrInvalidRole
+	}
+}
+
+// String returns the role as a string
+func (r Role) String() string {
+	return string(r)
+}
+
+// IsAdmin reports whether the role grants administrative access
+func (r Role) IsAdmin() bool {
+	return r == RoleAdmin
+}
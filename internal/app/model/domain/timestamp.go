@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a point in time in Unix milliseconds, the unit of every timestamp
+// column. It serializes to JSON as a number of milliseconds.
+type Timestamp int64
+
+// Now returns the current time as a Timestamp
+func Now() Timestamp {
+	return FromTime(time.Now())
+}
+
+// FromTime converts t to a Timestamp, dropping precision below a millisecond
+func FromTime(t time.Time) Timestamp {
+	return Timestamp(t.UnixMilli())
+}
+
+// Time returns the Timestamp as a time.Time in the local time zone
+func (t Timestamp) Time() time.Time {
+	return time.UnixMilli(int64(t))
+}
+
+// Millis returns the Timestamp in Unix milliseconds
+func (t Timestamp) Millis() int64 {
+	return int64(t)
+}
+
+// Before reports whether t is earlier than other
+func (t Timestamp) Before(other Timestamp) bool {
+	return t < other
+}
+
+// MarshalJSON encodes the Timestamp as a number of Unix milliseconds
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(t), 10), nil
+}
+
+// UnmarshalJSON decodes a number of Unix milliseconds; null leaves the Timestamp unchanged
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var millis int64
+	if err := json.Unmarshal(data, &millis); err != nil {
+		return err
+	}
+	*t = Timestamp(millis)
+	return nil
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp_TimeRoundTrip(t *testing.T) {
+	now := time.Now()
+	ts := FromTime(now)
+
+	assert.Equal(t, now.UnixMilli(), ts.Millis())
+	assert.True(t, ts.Time().Equal(now.Truncate(time.Millisecond)))
+	assert.True(t, ts.Before(FromTime(now.Add(time.Millisecond))))
+}
+
+func TestTimestamp_JSON(t *testing.T) {
+	token := RefreshToken{ExpiresAt: Timestamp(1700000000123)}
+
+	data, err := json.Marshal(token)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"expiresAt":1700000000123`)
+
+	var decoded RefreshToken
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, token.ExpiresAt, decoded.ExpiresAt)
+
+	var ts Timestamp
+	assert.Error(t, json.Unmarshal([]byte(`"2024-01-01T00:00:00Z"`), &ts), "only milliseconds are accepted")
+}
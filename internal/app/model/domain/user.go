@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/pkg/utils/crypt/password"
 
 	"github.com/google/uuid"
 )
@@ -13,11 +14,27 @@ type User struct {
 	ID           uuid.UUID    `json:"id" `
 	Email        *Email       `json:"email" `
 	Username     Username     `json:"username" `
-	CountryCode  *CountryCode `json:"country_code,omitempty" `
+	Role         Role         `json:"role" `
+	CountryCode  *CountryCode `json:"countryCode,omitempty" `
 	Phone        *PhoneNumber `json:"phone,omitempty" `
 	PasswordHash PasswordHash `json:"-" `
-	CreatedAt    int64        `json:"created_at" `
-	UpdatedAt    int64        `json:"updated_at" `
+	// TOTPSecret is the encrypted TOTP seed, set once enrollment has started
+	TOTPSecret  *string   `json:"-" `
+	TOTPEnabled bool      `json:"totpEnabled" `
+	CreatedAt   Timestamp `json:"createdAt" `
+	UpdatedAt   Timestamp `json:"updatedAt" `
+	// AccountState holds the status and flags deciding whether the user may sign in
+	AccountState
+	// PasswordChangedAt is when the password was last set
+	PasswordChangedAt Timestamp `json:"-" `
+	// PrimaryContact is the contact notifications and account recovery go to
+	PrimaryContact ContactMethod `json:"primaryContact" `
+}
+
+// TOTPSecret is the encrypted TOTP seed stored for a user
+type TOTPSecret struct {
+	UserID     uuid.UUID
+	Ciphertext string
 }
 
 // NewUser creates a new user with generated ID and timestamps
@@ -41,18 +58,22 @@ func NewUser(email, passwordHash, username string, countryCode, phone *string) (
 		return nil, err
 	}
 
-	now := time.Now().UnixMilli()
+	now := Now()
 	id := uuid.New()
 
 	return &User{
-		ID:           id,
-		Email:        emailObj,
-		PasswordHash: passwordHashObj,
-		Username:     usernameObj,
-		CountryCode:  countryCodeObj,
-		Phone:        phoneObj,
-		CreatedAt:    now,
-		UpdatedAt:    now,
+		ID:                id,
+		Email:             emailObj,
+		PasswordHash:      passwordHashObj,
+		Username:          usernameObj,
+		Role:              RoleUser,
+		CountryCode:       countryCodeObj,
+		Phone:             phoneObj,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		PasswordChangedAt: now,
+		AccountState:      AccountState{Status: AccountStatusActive},
+		PrimaryContact:    defaultPrimaryContact(emailObj),
 	}, nil
 }
 
@@ -99,19 +120,26 @@ func createContactInfo(email string, countryCode, phone *string) (*Email, *Count
 	return emailObj, countryCodeObj, phoneObj, nil
 }
 
-// NewUserWithPassword creates a new user with password validation
+// NewUserWithPassword creates a new user from registration input. dto.RegisterReq.Validate
+// reports every malformed field to clients at once, but the constructor still owns the
+// user invariants so callers that skip the DTO, such as the seed command, cannot build
+// an invalid user: the user must be reachable through an email or a country code and
+// phone pair, every provided field must be valid, the password must meet the policy, and
+// only its hash is kept. The password is hashed with hasher, or the default hasher when
+// it is nil.
 func NewUserWithPassword(
+	hasher *password.Hasher,
 	email *string,
 	password, username string,
 	countryCode, phone *string,
 ) (*User, error) {
-	// Check if either email OR both country code and phone are provided
-	hasEmail := email != nil && *email != ""
-	hasCountryCode := countryCode != nil && *countryCode != ""
-	hasPhone := phone != nil && *phone != ""
+	if err := validateUserInput(valueOrEmpty(email), countryCode, phone); err != nil {
+		return nil, err
+	}
 
-	if !hasEmail && !hasCountryCode && !hasPhone {
-		return nil, errs.ErrEmailOrPhoneRequired
+	emailObj, countryCodeObj, phoneObj, err := createContactInfo(valueOrEmpty(email), countryCode, phone)
+	if err != nil {
+		return nil, err
 	}
 
 	usernameObj, err := NewUsername(username)
@@ -119,44 +147,98 @@ func NewUserWithPassword(
 		return nil, err
 	}
 
-	pwd, err := NewPassword(password)
-	if err != nil {
+	if _, err := NewPassword(password); err != nil {
 		return nil, err
 	}
 
 	// Hash the password
-	passwordHash, err := NewPasswordHashFromPlain(string(pwd))
+	passwordHash, err := NewPasswordHashFromPlain(hasher, password)
 	if err != nil {
 		return nil, err
 	}
 
-	emailObj, err := NewEmailPtr(email)
-	if err != nil {
-		return nil, err
+	now := Now()
+
+	return &User{
+		ID:                uuid.New(),
+		Email:             emailObj,
+		PasswordHash:      passwordHash,
+		Username:          usernameObj,
+		Role:              RoleUser,
+		CountryCode:       countryCodeObj,
+		Phone:             phoneObj,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		PasswordChangedAt: now,
+		AccountState:      AccountState{Status: AccountStatusActive},
+		PrimaryContact:    defaultPrimaryContact(emailObj),
+	}, nil
+}
+
+// defaultPrimaryContact prefers the email of a new user, falling back to the phone
+func defaultPrimaryContact(email *Email) ContactMethod {
+	if email != nil {
+		return ContactMethodEmail
+	}
+	return ContactMethodPhone
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
 	}
+	return *value
+}
 
-	countryCodeObj, err := NewCountryCodePtr(countryCode)
-	if err != nil {
-		return nil, err
+// PasswordExpired reports whether the password is older than maxAge at now. A zero
+// maxAge disables expiry.
+func (u *User) PasswordExpired(maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 {
+		return false
 	}
+	return now.Sub(u.PasswordChangedAt.Time()) > maxAge
+}
 
-	phoneObj, err := NewPhoneNumberPtr(phone)
-	if err != nil {
-		return nil, err
+// HasContact reports whether the user has a contact of the given method
+func (u *User) HasContact(method ContactMethod) bool {
+	switch method {
+	case ContactMethodEmail:
+		return u.Email != nil && u.Email.IsSet()
+	case ContactMethodPhone:
+		return u.CountryCode != nil && u.CountryCode.IsSet() && u.Phone != nil && u.Phone.IsSet()
+	default:
+		return false
+	}
+}
+
+// ApplyContactVerification sets the contact verified by v, replacing the user's current
+// contact of the same method. A verified email is marked as such. The other contact is
+// left untouched, so the user keeps every contact they could already be reached at.
+func (u *User) ApplyContactVerification(v *ContactVerification) {
+	switch v.Method {
+	case ContactMethodEmail:
+		u.Email = v.Email
+		u.EmailVerified = true
+	case ContactMethodPhone:
+		u.CountryCode = v.CountryCode
+		u.Phone = v.Phone
 	}
+	u.UpdatedAt = Now()
+}
 
-	now := time.Now().UnixMilli()
+// SetPrimaryContact makes method the user's primary contact. The user must have a
+// contact of that method.
+func (u *User) SetPrimaryContact(method ContactMethod) error {
+	if err := method.Validate(); err != nil {
+		return err
+	}
+	if !u.HasContact(method) {
+		return errs.ErrContactMethodMissing
+	}
 
-	return &User{
-		ID:           uuid.New(),
-		Email:        emailObj,
-		PasswordHash: passwordHash,
-		Username:     usernameObj,
-		CountryCode:  countryCodeObj,
-		Phone:        phoneObj,
-		CreatedAt:    now,
-		UpdatedAt:    now,
-	}, nil
+	u.PrimaryContact = method
+	u.UpdatedAt = Now()
+	return nil
 }
 
 // IsValid checks if the user data is valid
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_PasswordExpired(t *testing.T) {
+	now := time.Now()
+	user := &User{PasswordChangedAt: FromTime(now.Add(-48 * time.Hour))}
+
+	assert.False(t, user.PasswordExpired(0, now), "a zero max age disables expiry")
+	assert.False(t, user.PasswordExpired(72*time.Hour, now))
+	assert.True(t, user.PasswordExpired(24*time.Hour, now))
+}
+
+func TestNewUserWithPassword_Invariants(t *testing.T) {
+	email := "known@example.com"
+	invalidEmail := "not-an-email"
+	countryCode := "TW"
+	invalidPhone := "12345"
+
+	tests := []struct {
+		name        string
+		email       *string
+		password    string
+		username    string
+		phone       *string
+		expectedErr error
+	}{
+		{name: "invalid username", email: &email, password: "Password123!", username: "a b", expectedErr: errs.ErrInvalidUsername},
+		{name: "invalid email", email: &invalidEmail, password: "Password123!", username: "testuser", expectedErr: errs.ErrInvalidEmail},
+		{name: "invalid phone", password: "Password123!", username: "testuser", phone: &invalidPhone, expectedErr: errs.ErrInvalidPhoneNumber},
+		{name: "password below policy", email: &email, password: "short", username: "testuser", expectedErr: errs.ErrInvalidPassword},
+		{name: "no contact", password: "Password123!", username: "testuser", expectedErr: errs.ErrEmailOrPhoneRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cc *string
+			if tt.phone != nil {
+				cc = &countryCode
+			}
+			_, err := NewUserWithPassword(nil, tt.email, tt.password, tt.username, cc, tt.phone)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+
+	user, err := NewUserWithPassword(nil, &email, "Password123!", " testuser ", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Username("testuser"), user.Username)
+	assert.Equal(t, Email("known@example.com"), *user.Email)
+}
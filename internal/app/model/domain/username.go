@@ -5,9 +5,9 @@ import "wallet-user-svc/internal/app/errs"
 // Username represents a validated username
 type Username string
 
-// NewUsername creates a new Username and validates it
+// NewUsername creates a new Username and validates it. Surrounding whitespace is trimmed.
 func NewUsername(username string) (Username, error) {
-	u := Username(username)
+	u := Username(NormalizeUsername(username))
 	if err := u.Validate(); err != nil {
 		return "", err
 	}
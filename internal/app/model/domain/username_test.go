@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUsername_TrimsWhitespace(t *testing.T) {
+	for _, input := range []string{"testuser", " testuser ", "\ttestuser\n"} {
+		username, err := NewUsername(input)
+		require.NoError(t, err, "input %q", input)
+		assert.Equal(t, Username("testuser"), username)
+	}
+
+	_, err := NewUsername("test user")
+	assert.Error(t, err, "inner whitespace is still invalid")
+}
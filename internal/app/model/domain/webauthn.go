@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/google/uuid"
+)
+
+// WebAuthnCredential is a passkey or security key a user registered to sign in without
+// a password
+type WebAuthnCredential struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+	// CredentialID is the ID the authenticator assigned to the credential
+	CredentialID []byte `json:"credentialId"`
+	// PublicKey is the COSE encoded public key assertions are verified with
+	PublicKey []byte `json:"-"`
+	// SignCount is the authenticator's signature counter at the last sign-in, used to
+	// detect cloned authenticators
+	SignCount uint32 `json:"signCount"`
+	// BackupEligible is set for credentials that may be synced between devices, such as
+	// passkeys; it cannot change after registration
+	BackupEligible bool      `json:"backupEligible"`
+	CreatedAt      Timestamp `json:"createdAt"`
+	UpdatedAt      Timestamp `json:"updatedAt"`
+}
+
+// NewWebAuthnCredential creates a credential of userID from a finished registration
+func NewWebAuthnCredential(userID uuid.UUID, credentialID, publicKey []byte, signCount uint32, backupEligible bool) (*WebAuthnCredential, error) {
+	if userID == uuid.Nil || len(credentialID) == 0 || len(publicKey) == 0 {
+		return nil, errs.ErrInvalidPasskey
+	}
+
+	now := Now()
+	return &WebAuthnCredential{
+		ID:             uuid.New(),
+		UserID:         userID,
+		CredentialID:   credentialID,
+		PublicKey:      publicKey,
+		SignCount:      signCount,
+		BackupEligible: backupEligible,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// WebAuthnCeremony is the WebAuthn operation a session belongs to
+type WebAuthnCeremony string
+
+const (
+	WebAuthnCeremonyRegistration WebAuthnCeremony = "registration"
+	WebAuthnCeremonyLogin        WebAuthnCeremony = "login"
+)
+
+// String returns the ceremony as a string
+func (c WebAuthnCeremony) String() string {
+	return string(c)
+}
+
+// WebAuthnSession is a WebAuthn ceremony that was begun and not yet finished. Data holds
+// the state of the WebAuthn library, JSON encoded, including the challenge the
+// authenticator signs. Login sessions have no user until the authenticator names one.
+type WebAuthnSession struct {
+	ID         uuid.UUID        `json:"id"`
+	UserID     *uuid.UUID       `json:"userId,omitempty"`
+	Ceremony   WebAuthnCeremony `json:"ceremony"`
+	Data       []byte           `json:"-"`
+	ExpiresAt  Timestamp        `json:"expiresAt"`
+	ConsumedAt *Timestamp       `json:"consumedAt,omitempty"`
+	CreatedAt  Timestamp        `json:"createdAt"`
+}
+
+// NewWebAuthnSession creates a session of ceremony that expires after ttl. userID is nil
+// for login sessions.
+func NewWebAuthnSession(userID *uuid.UUID, ceremony WebAuthnCeremony, data []byte, ttl time.Duration) (*WebAuthnSession, error) {
+	if ceremony == WebAuthnCeremonyRegistration && (userID == nil || *userID == uuid.Nil) {
+		return nil, errs.ErrInvalidCeremony
+	}
+
+	now := time.Now()
+	return &WebAuthnSession{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Ceremony:  ceremony,
+		Data:      data,
+		ExpiresAt: FromTime(now.Add(ttl)),
+		CreatedAt: FromTime(now),
+	}, nil
+}
+
+// IsValid checks that the session belongs to ceremony and can still be finished
+func (s *WebAuthnSession) IsValid(ceremony WebAuthnCeremony) error {
+	if s.Ceremony != ceremony {
+		return errs.ErrInvalidCeremony
+	}
+
+	if s.ConsumedAt != nil {
+		return errs.ErrInvalidCeremony
+	}
+
+	if s.ExpiresAt <= Now() {
+		return errs.ErrInvalidCeremony
+	}
+
+	return nil
+}
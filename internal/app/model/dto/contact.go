@@ -0,0 +1,37 @@
+package dto
+
+import "wallet-user-svc/internal/app/model/domain"
+
+type AddContactMethodReq struct {
+	// Email, or CountryCode and Phone, is the contact to add; exactly one may be given
+	Email       string  `json:"email,omitempty"`
+	CountryCode *string `json:"countryCode,omitempty"`
+	Phone       *string `json:"phone,omitempty"`
+}
+
+// Normalize brings the contact into the form it is stored and looked up in; see
+// Identifier.Normalize
+func (r *AddContactMethodReq) Normalize(defaultDialingCode string) {
+	identifier := Identifier{Email: &r.Email, CountryCode: r.CountryCode, Phone: r.Phone}.Normalize(defaultDialingCode)
+	r.Email, r.CountryCode, r.Phone = *identifier.Email, identifier.CountryCode, identifier.Phone
+}
+
+type AddContactMethodResp struct {
+	// VerificationID is passed to ConfirmContactMethod with the code sent to the contact
+	VerificationID string               `json:"verificationId"`
+	Method         domain.ContactMethod `json:"method"`
+	ExpiresAt      domain.Timestamp     `json:"expiresAt"`
+}
+
+type ConfirmContactMethodReq struct {
+	VerificationID string `json:"verificationId"`
+	Code           string `json:"code"`
+}
+
+type SetPrimaryContactReq struct {
+	Method string `json:"method"`
+}
+
+type ContactResp struct {
+	User *domain.User `json:"user"`
+}
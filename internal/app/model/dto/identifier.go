@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+)
+
+// IdentifierKind names the kind of identifier a user is looked up by
+type IdentifierKind int
+
+const (
+	IdentifierNone IdentifierKind = iota
+	IdentifierEmail
+	IdentifierPhone
+	IdentifierUsername
+)
+
+// Identifier names a user by exactly one of: an email, a country code and phone pair,
+// or a username. Empty values count as absent.
+type Identifier struct {
+	Email       *string `json:"email,omitempty"`
+	CountryCode *string `json:"countryCode,omitempty"`
+	Phone       *string `json:"phone,omitempty"`
+	Username    *string `json:"username,omitempty"`
+}
+
+// Kind returns the kind of the populated identifier. It returns errs.ErrInvalidIdentifier
+// unless exactly one kind is populated; a country code without a phone (or the reverse)
+// is not a valid identifier.
+func (i Identifier) Kind() (IdentifierKind, error) {
+	if isProvided(i.CountryCode) != isProvided(i.Phone) {
+		return IdentifierNone, errs.ErrInvalidIdentifier
+	}
+
+	kind, populated := IdentifierNone, 0
+	if i.HasEmail() {
+		kind, populated = IdentifierEmail, populated+1
+	}
+	if i.HasPhone() {
+		kind, populated = IdentifierPhone, populated+1
+	}
+	if isProvided(i.Username) {
+		kind, populated = IdentifierUsername, populated+1
+	}
+
+	if populated != 1 {
+		return IdentifierNone, errs.ErrInvalidIdentifier
+	}
+	return kind, nil
+}
+
+// Validate checks that exactly one identifier kind is populated and that a country code
+// is in the form it is stored in, an uppercase ISO 3166-1 alpha-2 code such as "US".
+// Anything else, like the dialing code "+1", could never match a stored user.
+func (i Identifier) Validate() error {
+	kind, err := i.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == IdentifierPhone {
+		return domain.CountryCode(*i.CountryCode).Validate()
+	}
+	return nil
+}
+
+// Normalize returns the identifier in the form identifiers are stored in, see
+// domain.NormalizeEmail and its siblings. National-format phones are completed with
+// defaultDialingCode. Absent fields stay absent.
+func (i Identifier) Normalize(defaultDialingCode string) Identifier {
+	return Identifier{
+		Email:       normalized(i.Email, domain.NormalizeEmail),
+		CountryCode: normalized(i.CountryCode, domain.NormalizeCountryCode),
+		Phone: normalized(i.Phone, func(phone string) string {
+			return domain.NormalizePhone(phone, defaultDialingCode)
+		}),
+		Username: normalized(i.Username, domain.NormalizeUsername),
+	}
+}
+
+// HasEmail reports whether an email is provided
+func (i Identifier) HasEmail() bool {
+	return isProvided(i.Email)
+}
+
+// HasPhone reports whether both a country code and a phone are provided
+func (i Identifier) HasPhone() bool {
+	return isProvided(i.CountryCode) && isProvided(i.Phone)
+}
+
+// normalized applies normalize to a provided value; nil stays nil
+func normalized(value *string, normalize func(string) string) *string {
+	if value == nil {
+		return nil
+	}
+	v := normalize(*value)
+	return &v
+}
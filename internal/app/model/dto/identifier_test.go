@@ -0,0 +1,178 @@
+package dto
+
+import (
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifier_Kind(t *testing.T) {
+	tests := []struct {
+		name         string
+		identifier   Identifier
+		expectedKind IdentifierKind
+		expectedErr  error
+	}{
+		{
+			name:         "email",
+			identifier:   Identifier{Email: strPtr("test@example.com")},
+			expectedKind: IdentifierEmail,
+		},
+		{
+			name:         "phone",
+			identifier:   Identifier{CountryCode: strPtr("US"), Phone: strPtr("+11234567890")},
+			expectedKind: IdentifierPhone,
+		},
+		{
+			name:         "username",
+			identifier:   Identifier{Username: strPtr("testuser")},
+			expectedKind: IdentifierUsername,
+		},
+		{
+			name:         "empty values are absent",
+			identifier:   Identifier{Email: strPtr("test@example.com"), CountryCode: strPtr(""), Phone: strPtr("")},
+			expectedKind: IdentifierEmail,
+		},
+		{
+			name:        "nothing populated",
+			identifier:  Identifier{},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+		{
+			name:        "email and username",
+			identifier:  Identifier{Email: strPtr("test@example.com"), Username: strPtr("testuser")},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+		{
+			name:        "phone without country code",
+			identifier:  Identifier{Phone: strPtr("+11234567890")},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+		{
+			name:        "country code without phone",
+			identifier:  Identifier{CountryCode: strPtr("US")},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+		{
+			name:        "email and phone",
+			identifier:  Identifier{Email: strPtr("test@example.com"), CountryCode: strPtr("US"), Phone: strPtr("+11234567890")},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+		{
+			name:        "phone and username",
+			identifier:  Identifier{CountryCode: strPtr("US"), Phone: strPtr("+11234567890"), Username: strPtr("testuser")},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+		{
+			name:        "all kinds",
+			identifier:  Identifier{Email: strPtr("test@example.com"), CountryCode: strPtr("US"), Phone: strPtr("+11234567890"), Username: strPtr("testuser")},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+		{
+			name:        "only empty values",
+			identifier:  Identifier{Email: strPtr(""), CountryCode: strPtr(""), Phone: strPtr(""), Username: strPtr("")},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, err := tt.identifier.Kind()
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Equal(t, IdentifierNone, kind)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedKind, kind)
+		})
+	}
+}
+
+func TestIdentifier_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		countryCode string
+		expectedErr error
+	}{
+		{name: "stored ISO alpha-2 form", countryCode: "US"},
+		{name: "dialing code", countryCode: "+1", expectedErr: errs.ErrInvalidCountryCode},
+		{name: "lowercase", countryCode: "us", expectedErr: errs.ErrInvalidCountryCode},
+		{name: "unknown code", countryCode: "XX", expectedErr: errs.ErrInvalidCountryCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Identifier{CountryCode: strPtr(tt.countryCode), Phone: strPtr("+11234567890")}.Validate()
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	assert.ErrorIs(t, Identifier{CountryCode: strPtr("+1")}.Validate(), errs.ErrInvalidIdentifier, "the identifier kind is checked first")
+}
+
+func TestIdentifier_HasEmailAndHasPhone(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier Identifier
+		hasEmail   bool
+		hasPhone   bool
+	}{
+		{name: "nothing", identifier: Identifier{}},
+		{name: "email", identifier: Identifier{Email: strPtr("test@example.com")}, hasEmail: true},
+		{name: "empty email", identifier: Identifier{Email: strPtr("")}},
+		{name: "phone", identifier: Identifier{CountryCode: strPtr("US"), Phone: strPtr("+11234567890")}, hasPhone: true},
+		{name: "phone without country code", identifier: Identifier{Phone: strPtr("+11234567890")}},
+		{name: "country code without phone", identifier: Identifier{CountryCode: strPtr("US")}},
+		{
+			name:       "email and phone",
+			identifier: Identifier{Email: strPtr("test@example.com"), CountryCode: strPtr("US"), Phone: strPtr("+11234567890")},
+			hasEmail:   true,
+			hasPhone:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.hasEmail, tt.identifier.HasEmail())
+			assert.Equal(t, tt.hasPhone, tt.identifier.HasPhone())
+		})
+	}
+}
+
+func TestLoginReq_Identifier(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      LoginReq
+		expectedKind IdentifierKind
+		expectedErr  error
+	}{
+		{name: "email", request: LoginReq{Email: "test@example.com"}, expectedKind: IdentifierEmail},
+		{name: "phone", request: LoginReq{CountryCode: "US", Phone: "+11234567890"}, expectedKind: IdentifierPhone},
+		{name: "nothing", request: LoginReq{}, expectedErr: errs.ErrInvalidIdentifier},
+		{name: "phone without country code", request: LoginReq{Phone: "+11234567890"}, expectedErr: errs.ErrInvalidIdentifier},
+		{
+			name:        "email and phone",
+			request:     LoginReq{Email: "test@example.com", CountryCode: "US", Phone: "+11234567890"},
+			expectedErr: errs.ErrInvalidIdentifier,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, err := tt.request.Identifier().Kind()
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedKind, kind)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+type EnrollTOTPResp struct {
+	// Secret is the base32 TOTP seed for manual entry in an authenticator app
+	Secret string `json:"secret"`
+	// URI is the otpauth:// URI, typically rendered as a QR code
+	URI string `json:"uri"`
+	// BackupCodes are single-use recovery codes; they are only ever returned here
+	BackupCodes []string `json:"backupCodes"`
+}
+
+type VerifyTOTPReq struct {
+	Code string `json:"code"`
+}
+
+type CompleteLoginReq struct {
+	ChallengeID string `json:"challengeId"`
+	Code        string `json:"code"`
+	// BackupCode may be given instead of Code when the authenticator is unavailable
+	BackupCode string       `json:"backupCode,omitempty"`
+	Device     ClientDevice `json:"device"`
+}
+
+type RegenerateBackupCodesReq struct {
+	// Code is a current TOTP code proving possession of the second factor
+	Code string `json:"code"`
+}
+
+type RegenerateBackupCodesResp struct {
+	BackupCodes []string `json:"backupCodes"`
+}
+
+type ReEncryptSecretsReq struct {
+	// AfterUserID resumes a previous run after this user ID; empty starts from the beginning
+	AfterUserID string `json:"afterUserId,omitempty"`
+	// BatchSize is the number of users re-encrypted per transaction; 0 uses the default
+	BatchSize int `json:"batchSize,omitempty"`
+	// MaxBatches stops the run after this many batches; 0 processes every user
+	MaxBatches int `json:"maxBatches,omitempty"`
+}
+
+type ReEncryptSecretsResp struct {
+	// Processed counts the secrets looked at, ReEncrypted those moved to the current key
+	// and Failed those no configured key could decrypt
+	Processed   int `json:"processed"`
+	ReEncrypted int `json:"reEncrypted"`
+	Failed      int `json:"failed"`
+	// LastUserID is the last user processed; pass it as AfterUserID to resume
+	LastUserID string `json:"lastUserId,omitempty"`
+	// Done is set once every user has been processed
+	Done bool `json:"done"`
+}
@@ -2,9 +2,25 @@ package dto
 
 import "time"
 
+// SendLoginNotificationParams is the payload of a login event. It carries either the
+// Email or the CountryCode and Phone of the user, whichever is the primary contact.
 type SendLoginNotificationParams struct {
-	UserID   string    `json:"userID"`
-	Email    *string   `json:"email,omitempty"`
-	Username string    `json:"username"`
-	LoginAt  time.Time `json:"loginAt"`
+	UserID      string    `json:"userID"`
+	Email       *string   `json:"email,omitempty"`
+	CountryCode *string   `json:"countryCode,omitempty"`
+	Phone       *string   `json:"phone,omitempty"`
+	Username    string    `json:"username"`
+	LoginAt     time.Time `json:"loginAt"`
+}
+
+// SendContactVerificationParams is the payload of an email or phone verification event
+type SendContactVerificationParams struct {
+	UserID         string    `json:"userID"`
+	Username       string    `json:"username"`
+	VerificationID string    `json:"verificationID"`
+	Email          *string   `json:"email,omitempty"`
+	CountryCode    *string   `json:"countryCode,omitempty"`
+	Phone          *string   `json:"phone,omitempty"`
+	Code           string    `json:"code"`
+	ExpiresAt      time.Time `json:"expiresAt"`
 }
@@ -0,0 +1,19 @@
+package dto
+
+// NotificationPreference tells whether notifications of an event type are sent to the user
+type NotificationPreference struct {
+	EventType string `json:"eventType"`
+	Enabled   bool   `json:"enabled"`
+}
+
+type GetNotificationPreferencesResp struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+type UpdateNotificationPreferencesReq struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+type UpdateNotificationPreferencesResp struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
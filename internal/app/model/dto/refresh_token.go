@@ -1,10 +1,42 @@
 package dto
 
+import "wallet-user-svc/internal/app/model/domain"
+
 type RefreshTokenReq struct {
-	RefreshToken string `json:"refreshToken"`
+	RefreshToken string       `json:"refreshToken"`
+	Device       ClientDevice `json:"device"`
 }
 
 type RefreshTokenResp struct {
 	AccessToken  string `json:"accessToken"`
 	RefreshToken string `json:"refreshToken"`
-}		
\ No newline at end of file
+	// AccessTokenExpiresAt is in Unix milliseconds
+	AccessTokenExpiresAt int64 `json:"accessTokenExpiresAt"`
+}
+
+// RotateRefreshReq asks for a new refresh token in place of a valid one
+type RotateRefreshReq struct {
+	RefreshToken string       `json:"refreshToken"`
+	Device       ClientDevice `json:"device"`
+}
+
+// RotateRefreshResp carries the token pair that replaces the presented refresh token
+type RotateRefreshResp struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	// AccessTokenExpiresAt and RefreshTokenExpiresAt are in Unix milliseconds
+	AccessTokenExpiresAt  int64 `json:"accessTokenExpiresAt"`
+	RefreshTokenExpiresAt int64 `json:"refreshTokenExpiresAt"`
+}
+
+// ClientDevice identifies the client a refresh token is issued to or presented by
+type ClientDevice struct {
+	// UserAgent is taken from the request metadata rather than the request body
+	UserAgent string `json:"-"`
+	DeviceID  string `json:"deviceId,omitempty"`
+}
+
+// Fingerprint returns the device fingerprint refresh tokens are bound to
+func (d ClientDevice) Fingerprint() string {
+	return domain.DeviceFingerprint(d.UserAgent, d.DeviceID)
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	Revision  string `json:"revision"`
+	BuildTime string `json:"buildTime"`
+}
+
+type DatabasePoolStats struct {
+	MaxOpenConnections int           `json:"maxOpenConnections"`
+	OpenConnections    int           `json:"openConnections"`
+	InUse              int           `json:"inUse"`
+	Idle               int           `json:"idle"`
+	WaitCount          int64         `json:"waitCount"`
+	WaitDuration       time.Duration `json:"waitDuration"`
+}
+
+// NotificationStats counts notification events the instance failed to record
+type NotificationStats struct {
+	// Dropped is the number of events skipped since startup because recording them
+	// failed while worker.notification.strict was off
+	Dropped int64 `json:"dropped"`
+}
+
+type RuntimeInfoResp struct {
+	Build         BuildInfo         `json:"build"`
+	Config        map[string]string `json:"config"`
+	DatabasePool  DatabasePoolStats `json:"databasePool"`
+	Notifications NotificationStats `json:"notifications"`
+	StartedAt     time.Time         `json:"startedAt"`
+	Uptime        time.Duration     `json:"uptime"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+// Session describes an active refresh token of the authenticated user
+type Session struct {
+	ID string `json:"id"`
+	// CreatedAt and ExpiresAt are Unix milliseconds
+	CreatedAt int64 `json:"createdAt"`
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+type ListSessionsResp struct {
+	Sessions []Session `json:"sessions"`
+}
+
+type RevokeSessionReq struct {
+	SessionID string `json:"sessionId"`
+}
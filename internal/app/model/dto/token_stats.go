@@ -0,0 +1,30 @@
+package dto
+
+type GetTokenStatsReq struct {
+	// PageSize is the number of users returned; 0 uses the default
+	PageSize int `json:"pageSize,omitempty"`
+	// Offset skips users, the NextOffset of a previous page
+	Offset int `json:"offset,omitempty"`
+}
+
+// TokenCounts counts refresh tokens by state. Active tokens are unrevoked and
+// unexpired, Expired tokens are unrevoked but past their expiry.
+type TokenCounts struct {
+	Active  int64 `json:"active"`
+	Revoked int64 `json:"revoked"`
+	Expired int64 `json:"expired"`
+}
+
+// UserTokenCounts counts the refresh tokens of one user
+type UserTokenCounts struct {
+	UserID string      `json:"userId"`
+	Counts TokenCounts `json:"counts"`
+}
+
+type GetTokenStatsResp struct {
+	Total TokenCounts `json:"total"`
+	// Users are ordered by active tokens, most first
+	Users []UserTokenCounts `json:"users"`
+	// NextOffset is the Offset of the next page; 0 when there are no more users
+	NextOffset int `json:"nextOffset,omitempty"`
+}
@@ -11,29 +11,178 @@ type RegisterReq struct {
 	Email    *string `json:"email"`
 	CountryCode *string `json:"countryCode"`
 	Phone       *string `json:"phone"`
+	// PrimaryContact is "email" or "phone"; when nil, a user registering with both gets
+	// the configured default
+	PrimaryContact *string `json:"primaryContact"`
+	Device      ClientDevice `json:"device"`
 }
 
+// Validate owns the input-shape validation of a registration: required fields must be
+// present and every provided field must be well-formed. All failures are reported together
+// as an errs.ValidationError with one violation per offending field. User invariants are
+// enforced by domain.NewUserWithPassword.
 func (r *RegisterReq) Validate() error {
-	if r.Email == nil && (r.CountryCode == nil || r.Phone == nil) {
-		return errs.ErrEmailOrPhoneRequired
+	identifier := r.Identifier()
+	hasEmail := identifier.HasEmail()
+	hasCountryCode := isProvided(r.CountryCode)
+	hasPhone := isProvided(r.Phone)
+
+	var violations []errs.FieldViolation
+	check := func(field string, err error) {
+		if err != nil {
+			violations = append(violations, errs.FieldViolation{Field: field, Err: err})
+		}
+	}
+
+	if !hasEmail && !identifier.HasPhone() {
+		check("email", errs.ErrEmailOrPhoneRequired)
+	}
+	if hasPhone && !hasCountryCode {
+		check("country_code", errs.ErrInvalidCountryCode)
+	}
+	if hasCountryCode && !hasPhone {
+		check("phone", errs.ErrInvalidPhoneNumber)
+	}
+
+	check("username", domain.Username(r.Username).Validate())
+	check("password", domain.Password(r.Password).Validate())
+	if hasEmail {
+		check("email", domain.Email(*r.Email).Validate())
+	}
+	if hasCountryCode {
+		check("country_code", domain.CountryCode(*r.CountryCode).Validate())
+	}
+	if hasPhone {
+		check("phone", domain.PhoneNumber(*r.Phone).Validate())
 	}
+	if isProvided(r.PrimaryContact) {
+		check("primary_contact", r.validatePrimaryContact(hasEmail, identifier.HasPhone()))
+	}
+
+	return errs.NewValidationError(violations...)
+}
 
+// validatePrimaryContact checks that the requested primary contact is a known method
+// and that the registration provides a contact of that method
+func (r *RegisterReq) validatePrimaryContact(hasEmail, hasPhone bool) error {
+	method, err := domain.NewContactMethod(*r.PrimaryContact)
+	if err != nil {
+		return err
+	}
+	if (method == domain.ContactMethodEmail && !hasEmail) || (method == domain.ContactMethodPhone && !hasPhone) {
+		return errs.ErrContactMethodMissing
+	}
 	return nil
 }
 
+// Identifier returns the contact identifiers of the registration. Unlike a login, a
+// registration may provide both an email and a phone, so only the email and phone
+// helpers of the result apply; Validate rejects it if neither is complete.
+func (r *RegisterReq) Identifier() Identifier {
+	return Identifier{Email: r.Email, CountryCode: r.CountryCode, Phone: r.Phone}
+}
+
+// Normalize brings the identifiers of the registration into the form they are stored
+// and looked up in; see Identifier.Normalize
+func (r *RegisterReq) Normalize(defaultDialingCode string) {
+	identifier := r.Identifier().Normalize(defaultDialingCode)
+	r.Email, r.CountryCode, r.Phone = identifier.Email, identifier.CountryCode, identifier.Phone
+	r.Username = domain.NormalizeUsername(r.Username)
+}
+
+// Warnings returns the soft validation warnings for a valid registration: inputs that
+// pass Validate but are discouraged
+func (r *RegisterReq) Warnings() []Warning {
+	var warnings []Warning
+
+	if domain.Password(r.Password).IsCommon() {
+		warnings = append(warnings, Warning{
+			Field:   "password",
+			Code:    WarningCommonPassword,
+			Message: "password is commonly used and easy to guess",
+		})
+	}
+	if isProvided(r.Email) && domain.Email(*r.Email).IsFreeProvider() {
+		warnings = append(warnings, Warning{
+			Field:   "email",
+			Code:    WarningFreeEmailProvider,
+			Message: "email is at a free provider; consider an address you control",
+		})
+	}
+
+	return warnings
+}
+
+func isProvided(value *string) bool {
+	return value != nil && *value != ""
+}
+
 type RegisterResp struct {
 	User         *domain.User `json:"user"`
 	AccessToken  string       `json:"accessToken"`
 	RefreshToken string       `json:"refreshToken"`
+	// Warnings lists discouraged but accepted inputs
+	Warnings []Warning `json:"warnings,omitempty"`
+	// AccessTokenExpiresAt and RefreshTokenExpiresAt are Unix milliseconds
+	AccessTokenExpiresAt  int64 `json:"accessTokenExpiresAt"`
+	RefreshTokenExpiresAt int64 `json:"refreshTokenExpiresAt"`
 }	
 
+// AdminCreateUserReq is an admin request to provision an account. The account fields are
+// validated like a registration, but public registration rules such as
+// registration.enabled do not apply.
+type AdminCreateUserReq struct {
+	RegisterReq
+	// Role defaults to domain.RoleUser when empty
+	Role string `json:"role,omitempty"`
+	// EmailVerified marks the email as already verified
+	EmailVerified bool `json:"emailVerified"`
+	// MustChangePassword marks Password as temporary
+	MustChangePassword bool `json:"mustChangePassword"`
+}
+
+type AdminCreateUserResp struct {
+	User     *domain.User `json:"user"`
+	Warnings []Warning    `json:"warnings,omitempty"`
+}
+
+// LoginReq signs a user in by email, or by country code and phone
 type LoginReq struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email       string       `json:"email"`
+	CountryCode string       `json:"countryCode,omitempty"`
+	Phone       string       `json:"phone,omitempty"`
+	Password    string       `json:"password"`
+	Device      ClientDevice `json:"device"`
+}
+
+// Identifier returns the identifier the user signs in with
+func (r LoginReq) Identifier() Identifier {
+	return Identifier{Email: &r.Email, CountryCode: &r.CountryCode, Phone: &r.Phone}
+}
+
+// Normalize brings the identifier of the login into the form identifiers are stored in,
+// so it matches what registration wrote; see Identifier.Normalize
+func (r *LoginReq) Normalize(defaultDialingCode string) {
+	identifier := r.Identifier().Normalize(defaultDialingCode)
+	r.Email, r.CountryCode, r.Phone = *identifier.Email, *identifier.CountryCode, *identifier.Phone
 }
 
 type LoginResp struct {
 	User         *domain.User `json:"user"`
 	AccessToken  string       `json:"accessToken"`
 	RefreshToken string       `json:"refreshToken"`
+	// MFARequired is set instead of tokens when the user has two-factor authentication
+	// enabled; the login is finished with CompleteLogin using ChallengeID
+	MFARequired bool   `json:"mfaRequired"`
+	ChallengeID string `json:"challengeId,omitempty"`
+	// BackupCodesRemaining is set when the login consumed a backup code
+	BackupCodesRemaining *int `json:"backupCodesRemaining,omitempty"`
+	// BackupCodesLow warns that the user should regenerate backup codes
+	BackupCodesLow bool `json:"backupCodesLow,omitempty"`
+	// AccessTokenExpiresAt and RefreshTokenExpiresAt are Unix milliseconds, unset
+	// while MFARequired
+	AccessTokenExpiresAt  int64 `json:"accessTokenExpiresAt,omitempty"`
+	RefreshTokenExpiresAt int64 `json:"refreshTokenExpiresAt,omitempty"`
+	// PasswordExpired is set when the password is older than password.max_age
+	PasswordExpired bool `json:"passwordExpired,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,166 @@
+package dto
+
+import (
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestRegisterReq_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     RegisterReq
+		expectedErr error
+	}{
+		{
+			name:    "valid email registration",
+			request: RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("test@example.com")},
+		},
+		{
+			name:    "valid phone registration",
+			request: RegisterReq{Username: "testuser", Password: "Password123!", CountryCode: strPtr("US"), Phone: strPtr("+11234567890")},
+		},
+		{
+			name:    "valid email and phone registration",
+			request: RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("test@example.com"), CountryCode: strPtr("US"), Phone: strPtr("+11234567890")},
+		},
+		{
+			name:    "phone as primary contact",
+			request: RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("test@example.com"), CountryCode: strPtr("US"), Phone: strPtr("+11234567890"), PrimaryContact: strPtr("phone")},
+		},
+		{
+			name:        "unknown primary contact",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("test@example.com"), PrimaryContact: strPtr("fax")},
+			expectedErr: errs.ErrInvalidContactMethod,
+		},
+		{
+			name:        "primary contact not provided",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("test@example.com"), PrimaryContact: strPtr("phone")},
+			expectedErr: errs.ErrContactMethodMissing,
+		},
+		{
+			name:        "missing contact method",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!"},
+			expectedErr: errs.ErrEmailOrPhoneRequired,
+		},
+		{
+			name:        "empty email counts as missing",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("")},
+			expectedErr: errs.ErrEmailOrPhoneRequired,
+		},
+		{
+			name:        "phone without country code",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("test@example.com"), Phone: strPtr("+11234567890")},
+			expectedErr: errs.ErrInvalidCountryCode,
+		},
+		{
+			name:        "country code without phone",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("test@example.com"), CountryCode: strPtr("US")},
+			expectedErr: errs.ErrInvalidPhoneNumber,
+		},
+		{
+			name:        "dialing code is not a country code",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", CountryCode: strPtr("+1"), Phone: strPtr("+11234567890")},
+			expectedErr: errs.ErrInvalidCountryCode,
+		},
+		{
+			name:        "unknown country code",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", CountryCode: strPtr("XX"), Phone: strPtr("+11234567890")},
+			expectedErr: errs.ErrInvalidCountryCode,
+		},
+		{
+			name:        "invalid username",
+			request:     RegisterReq{Username: "x", Password: "Password123!", Email: strPtr("test@example.com")},
+			expectedErr: errs.ErrInvalidUsername,
+		},
+		{
+			name:        "weak password",
+			request:     RegisterReq{Username: "testuser", Password: "password", Email: strPtr("test@example.com")},
+			expectedErr: errs.ErrInvalidPassword,
+		},
+		{
+			name:        "invalid email",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("not-an-email")},
+			expectedErr: errs.ErrInvalidEmail,
+		},
+		{
+			name:        "invalid phone",
+			request:     RegisterReq{Username: "testuser", Password: "Password123!", CountryCode: strPtr("US"), Phone: strPtr("12345")},
+			expectedErr: errs.ErrInvalidPhoneNumber,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRegisterReq_ValidateReportsEveryField(t *testing.T) {
+	req := RegisterReq{Username: "x", Password: "password", Email: strPtr("not-an-email"), Phone: strPtr("12345")}
+
+	err := req.Validate()
+
+	var validationErr *errs.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	fields := make([]string, 0, len(validationErr.Violations))
+	for _, v := range validationErr.Violations {
+		fields = append(fields, v.Field)
+	}
+	assert.Equal(t, []string{"country_code", "username", "password", "email", "phone"}, fields)
+	assert.ErrorIs(t, err, errs.ErrInvalidEmail)
+	assert.ErrorIs(t, err, errs.ErrInvalidPassword)
+}
+
+func TestRegisterReq_Warnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  RegisterReq
+		expected []string
+	}{
+		{
+			name:    "no warnings",
+			request: RegisterReq{Username: "testuser", Password: "Tr1cky-Horse", Email: strPtr("test@example.com")},
+		},
+		{
+			name:     "common password",
+			request:  RegisterReq{Username: "testuser", Password: "Password123!", Email: strPtr("test@example.com")},
+			expected: []string{WarningCommonPassword},
+		},
+		{
+			name:     "free email provider",
+			request:  RegisterReq{Username: "testuser", Password: "Tr1cky-Horse", Email: strPtr("test@Gmail.com")},
+			expected: []string{WarningFreeEmailProvider},
+		},
+		{
+			name:    "phone registration has no email warning",
+			request: RegisterReq{Username: "testuser", Password: "Tr1cky-Horse", CountryCode: strPtr("US"), Phone: strPtr("+11234567890")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.request.Validate())
+
+			var codes []string
+			for _, warning := range tt.request.Warnings() {
+				codes = append(codes, warning.Code)
+			}
+			assert.Equal(t, tt.expected, codes)
+		})
+	}
+}
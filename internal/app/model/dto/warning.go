@@ -0,0 +1,16 @@
+package dto
+
+// Warning codes for inputs that are accepted but discouraged
+const (
+	WarningCommonPassword    = "common_password"
+	WarningFreeEmailProvider = "free_email_provider"
+)
+
+// Warning is a non-fatal remark about an accepted request field, returned alongside a
+// successful response to nudge the user without rejecting the request
+type Warning struct {
+	// Field is the proto field name the warning refers to
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"wallet-user-svc/internal/app/model/domain"
+)
+
+type BeginWebAuthnResp struct {
+	// SessionID is passed to the matching finish call with the authenticator's response
+	SessionID string `json:"sessionId"`
+	// Options are the JSON encoded credential creation or request options to pass to
+	// navigator.credentials.create or navigator.credentials.get
+	Options   json.RawMessage  `json:"options"`
+	ExpiresAt domain.Timestamp `json:"expiresAt"`
+}
+
+type FinishWebAuthnRegistrationReq struct {
+	SessionID string `json:"sessionId"`
+	// Credential is the JSON encoded PublicKeyCredential returned by the authenticator
+	Credential []byte `json:"credential"`
+}
+
+type FinishWebAuthnRegistrationResp struct {
+	// CredentialID is the base64url encoded ID of the registered credential
+	CredentialID string `json:"credentialId"`
+}
+
+type FinishWebAuthnLoginReq struct {
+	SessionID string `json:"sessionId"`
+	// Credential is the JSON encoded PublicKeyCredential returned by the authenticator
+	Credential []byte       `json:"credential"`
+	Device     ClientDevice `json:"device"`
+}
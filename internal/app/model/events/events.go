@@ -1,5 +1,11 @@
 package events
 
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
 type EventMetadata struct {
 	EventID     string `json:"eventID"`
 	EventName   string `json:"eventName"`
@@ -8,8 +14,52 @@ type EventMetadata struct {
 
 type EventType string
 
+// NotificationTemplate identifies how a notification consumer renders an event, so
+// template selection and the sender identity are configured here rather than hardcoded
+// in the consumer. Empty fields leave the choice to the consumer.
+type NotificationTemplate struct {
+	TemplateID  string `json:"templateId,omitempty"`
+	SenderEmail string `json:"senderEmail,omitempty"`
+	SenderName  string `json:"senderName,omitempty"`
+}
+
 const (
 	OrderCreatedEventType       EventType = "order_created"
 	OrderCreatedFailedEventType EventType = "order_created_failed"
 	LoginEventType              EventType = "login"
+	// EmailVerificationEventType and PhoneVerificationEventType carry the code proving
+	// the user can receive a new contact
+	EmailVerificationEventType EventType = "email_verification"
+	PhoneVerificationEventType EventType = "phone_verification"
 )
+
+// TaskOptions holds per-event scheduling options applied when the event task is enqueued
+type TaskOptions struct {
+	// ProcessIn delays processing of the task by the given duration
+	ProcessIn time.Duration
+	// Timeout bounds how long a single processing attempt may run
+	Timeout time.Duration
+	// Deadline is relative to enqueue time; the task is abandoned once it passes
+	Deadline time.Duration
+	// Unique deduplicates identical tasks for the given duration
+	Unique time.Duration
+}
+
+// AsynqOptions converts the configured options into asynq task options.
+// Zero values are skipped so unset options keep asynq's defaults.
+func (o TaskOptions) AsynqOptions(now time.Time) []asynq.Option {
+	opts := make([]asynq.Option, 0, 4)
+	if o.ProcessIn > 0 {
+		opts = append(opts, asynq.ProcessIn(o.ProcessIn))
+	}
+	if o.Timeout > 0 {
+		opts = append(opts, asynq.Timeout(o.Timeout))
+	}
+	if o.Deadline > 0 {
+		opts = append(opts, asynq.Deadline(now.Add(o.Deadline)))
+	}
+	if o.Unique > 0 {
+		opts = append(opts, asynq.Unique(o.Unique))
+	}
+	return opts
+}
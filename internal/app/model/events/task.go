@@ -0,0 +1,33 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+// NewTask converts payload into an asynq task of type eventType. Every event is
+// serialized the same way, as JSON, so consumers can decode any of them with DecodeTask.
+func NewTask(eventType EventType, payload any, opts ...asynq.Option) (*asynq.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	return asynq.NewTask(string(eventType), data, opts...), nil
+}
+
+// DecodeTask decodes the payload of a task created by NewTask for eventType into a T.
+// It fails for tasks of any other type, so a consumer cannot decode the wrong event.
+func DecodeTask[T any](task *asynq.Task, eventType EventType) (*T, error) {
+	if task.Type() != string(eventType) {
+		return nil, fmt.Errorf("task type %q is not a %s event", task.Type(), eventType)
+	}
+
+	var event T
+	if err := json.Unmarshal(task.Payload(), &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s event: %w", eventType, err)
+	}
+	return &event, nil
+}
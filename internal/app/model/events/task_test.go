@@ -0,0 +1,42 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginEvent_ToTaskRoundTrip(t *testing.T) {
+	email := "user@example.com"
+	event := &LoginEvent{
+		EventMetadata: EventMetadata{EventID: "event-1", EventName: string(LoginEventType), PublishedAt: 1700000000000},
+		UserID:        "user-1",
+		Email:         &email,
+		Username:      "user",
+		LoginAt:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	template := NotificationTemplate{TemplateID: "login-v2", SenderEmail: "security@example.com"}
+
+	task, err := event.ToTask(template)
+	require.NoError(t, err)
+	assert.Equal(t, string(LoginEventType), task.Type())
+
+	decoded, err := DecodeTask[LoginEvent](task, LoginEventType)
+	require.NoError(t, err)
+	assert.Equal(t, event, decoded)
+	assert.Equal(t, template, decoded.Template)
+}
+
+func TestDecodeTask_RejectsOtherTypes(t *testing.T) {
+	task, err := NewTask(OrderCreatedEventType, map[string]string{"orderId": "order-1"})
+	require.NoError(t, err)
+
+	_, err = DecodeTask[LoginEvent](task, LoginEventType)
+	assert.Error(t, err)
+
+	_, err = DecodeTask[LoginEvent](asynq.NewTask(string(LoginEventType), []byte("not json")), LoginEventType)
+	assert.Error(t, err)
+}
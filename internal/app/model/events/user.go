@@ -1,7 +1,6 @@
 package events
 
 import (
-	"encoding/json"
 	"time"
 
 	"github.com/hibiken/asynq"
@@ -11,15 +10,39 @@ type LoginEvent struct {
 	EventMetadata EventMetadata `json:"eventMetadata"`
 	UserID        string        `json:"userId"`
 	Email         *string       `json:"email,omitempty"`
+	CountryCode   *string       `json:"countryCode,omitempty"`
+	Phone         *string       `json:"phone,omitempty"`
 	Username      string        `json:"username"`
 	LoginAt       time.Time     `json:"loginAt"`
+	// Template tells the consumer which template to render and from whom
+	Template NotificationTemplate `json:"template"`
 }
 
-func (e *LoginEvent) ToTask() (*asynq.Task, error) {
-	payload, err := json.Marshal(e)
-	if err != nil {
-		return nil, err
-	}
+// ToTask attaches the template metadata to the event and converts it into an asynq task
+func (e *LoginEvent) ToTask(template NotificationTemplate, opts ...asynq.Option) (*asynq.Task, error) {
+	e.Template = template
+	return NewTask(LoginEventType, e, opts...)
+}
+
+// ContactVerificationEvent asks the consumer to send a verification code to a contact
+// the user is adding. Exactly one of Email or CountryCode and Phone is set.
+type ContactVerificationEvent struct {
+	EventMetadata  EventMetadata `json:"eventMetadata"`
+	UserID         string        `json:"userId"`
+	Username       string        `json:"username"`
+	VerificationID string        `json:"verificationId"`
+	Email          *string       `json:"email,omitempty"`
+	CountryCode    *string       `json:"countryCode,omitempty"`
+	Phone          *string       `json:"phone,omitempty"`
+	Code           string        `json:"code"`
+	ExpiresAt      time.Time     `json:"expiresAt"`
+	// Template tells the consumer which template to render and from whom
+	Template NotificationTemplate `json:"template"`
+}
 
-	return asynq.NewTask(string(LoginEventType), payload), nil
+// ToTask attaches the template metadata to the event and converts it into an asynq task
+// of the given verification event type
+func (e *ContactVerificationEvent) ToTask(eventType EventType, template NotificationTemplate, opts ...asynq.Option) (*asynq.Task, error) {
+	e.Template = template
+	return NewTask(eventType, e, opts...)
 }
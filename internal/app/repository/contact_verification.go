@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"wallet-user-svc/db"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ContactVerification struct {
+	ID          uuid.UUID           `db:"id"`
+	UserID      uuid.UUID           `db:"user_id"`
+	Method      string              `db:"method"`
+	Email       *domain.Email       `db:"email"`
+	CountryCode *domain.CountryCode `db:"country_code"`
+	Phone       *domain.PhoneNumber `db:"phone"`
+	CodeHash    string              `db:"code_hash"`
+	Attempts    int                 `db:"attempts"`
+	ExpiresAt   int64               `db:"expires_at"`
+	ConsumedAt  *int64              `db:"consumed_at"`
+	CreatedAt   int64               `db:"created_at"`
+}
+
+func (v *ContactVerification) ToDomain() *domain.ContactVerification {
+	verification := &domain.ContactVerification{
+		ID:          v.ID,
+		UserID:      v.UserID,
+		Method:      domain.ContactMethod(v.Method),
+		Email:       v.Email,
+		CountryCode: v.CountryCode,
+		Phone:       v.Phone,
+		CodeHash:    v.CodeHash,
+		Attempts:    v.Attempts,
+		ExpiresAt:   domain.Timestamp(v.ExpiresAt),
+		CreatedAt:   domain.Timestamp(v.CreatedAt),
+	}
+	if v.ConsumedAt != nil {
+		consumedAt := domain.Timestamp(*v.ConsumedAt)
+		verification.ConsumedAt = &consumedAt
+	}
+	return verification
+}
+
+type ContactVerificationRepository struct {
+	db db.Store
+}
+
+func NewContactVerificationRepository(db db.Store) *ContactVerificationRepository {
+	return &ContactVerificationRepository{
+		db: db,
+	}
+}
+
+// Create stores a new verification
+func (r *ContactVerificationRepository) Create(ctx context.Context, verification *domain.ContactVerification) error {
+	query := `
+		INSERT INTO contact_verifications (id, user_id, method, email, country_code, phone, code_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	args := []interface{}{
+		verification.ID, verification.UserID, verification.Method.String(), verification.Email, verification.CountryCode,
+		verification.Phone, verification.CodeHash, verification.Attempts, verification.ExpiresAt.Millis(), verification.CreatedAt.Millis(),
+	}
+
+	var err error
+
+	// Check if we're in a transaction
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		// Use transaction
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		// Use main database connection
+		_, err = r.db.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create contact verification: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a verification by ID
+func (r *ContactVerificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ContactVerification, error) {
+	query := `
+		SELECT id, user_id, method, email, country_code, phone, code_hash, attempts, expires_at, consumed_at, created_at
+		FROM contact_verifications
+		WHERE id = $1
+	`
+
+	var verification ContactVerification
+	if err := r.db.GetContext(ctx, &verification, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrInvalidVerification
+		}
+		return nil, fmt.Errorf("failed to get contact verification: %w", err)
+	}
+
+	return verification.ToDomain(), nil
+}
+
+// ReserveAttempt counts an attempt at the verification before its code is checked. The
+// limit, expiry and consumption checks are part of the same UPDATE, so concurrent requests
+// cannot get more than maxAttempts codes checked; a verification that is consumed, out of
+// attempts or expired at now returns ErrInvalidVerification.
+func (r *ContactVerificationRepository) ReserveAttempt(ctx context.Context, id uuid.UUID, maxAttempts int, now domain.Timestamp) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE contact_verifications SET attempts = attempts + 1
+		WHERE id = $1 AND consumed_at IS NULL AND attempts < $2 AND expires_at > $3`,
+		id, maxAttempts, now.Millis(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reserve contact verification attempt: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrInvalidVerification
+	}
+
+	return nil
+}
+
+// Consume marks the verification as used. Only the first caller succeeds, so a
+// verification can never apply its contact twice.
+func (r *ContactVerificationRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE contact_verifications SET consumed_at = $1 WHERE id = $2 AND consumed_at IS NULL`
+
+	var result sql.Result
+	var err error
+
+	// Check if we're in a transaction
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		// Use transaction
+		result, err = tx.ExecContext(ctx, query, time.Now().UnixMilli(), id)
+	} else {
+		// Use main database connection
+		result, err = r.db.ExecContext(ctx, query, time.Now().UnixMilli(), id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to consume contact verification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrInvalidVerification
+	}
+
+	return nil
+}
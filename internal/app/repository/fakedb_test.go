@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"wallet-user-svc/db"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a database/sql driver that answers every query with the same rows and
+// counts how often statements are prepared, standing in for PostgreSQL in tests and
+// benchmarks of the Go side of the repositories
+type fakeDriver struct {
+	mu       sync.Mutex
+	columns  []string
+	rows     [][]driver.Value
+	prepares atomic.Int64
+	// lastArgs holds the arguments of the most recent query
+	lastArgs []driver.Value
+	// exec, when set, decides the outcome of every statement execution
+	exec func(args []driver.Value) error
+	// lastExecInTx reports whether the most recent execution ran inside a transaction
+	lastExecInTx bool
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (d *fakeDriver) setRows(columns []string, rows ...[]driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.columns = columns
+	d.rows = rows
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+	inTx   bool
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	c.driver.prepares.Add(1)
+	return &fakeStmt{driver: c.driver, conn: c}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	return fakeTx{conn: c}, nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (t fakeTx) Commit() error {
+	t.conn.inTx = false
+	return nil
+}
+
+func (t fakeTx) Rollback() error {
+	t.conn.inTx = false
+	return nil
+}
+
+type fakeStmt struct {
+	driver *fakeDriver
+	conn   *fakeConn
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.mu.Lock()
+	s.driver.lastExecInTx = s.conn.inTx
+	s.driver.mu.Unlock()
+
+	if s.driver.exec != nil {
+		if err := s.driver.exec(args); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+	s.driver.lastArgs = args
+	return &fakeRows{columns: s.driver.columns, rows: s.driver.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var fakeDriverID atomic.Int64
+
+// newFakeStore registers a fresh fakeDriver and returns a store backed by it
+func newFakeStore(tb testing.TB) (db.Store, *fakeDriver) {
+	tb.Helper()
+
+	fake := &fakeDriver{}
+	name := fmt.Sprintf("repository-fake-%d", fakeDriverID.Add(1))
+	sql.Register(name, fake)
+
+	conn, err := sqlx.Open(name, "")
+	require.NoError(tb, err)
+	tb.Cleanup(func() { conn.Close() })
+
+	return db.WrapDB(conn), fake
+}
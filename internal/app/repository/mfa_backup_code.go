@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"wallet-user-svc/db"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type MFABackupCodeRepository struct {
+	db db.Store
+}
+
+func NewMFABackupCodeRepository(db db.Store) *MFABackupCodeRepository {
+	return &MFABackupCodeRepository{
+		db: db,
+	}
+}
+
+// Replace deletes all backup codes of a user and stores the given hashes. Run it in a
+// transaction so old codes are never invalidated without the new ones being stored.
+func (r *MFABackupCodeRepository) Replace(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	exec := r.execer(ctx)
+
+	if _, err := exec.ExecContext(ctx, `DELETE FROM mfa_backup_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete backup codes: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	for _, codeHash := range codeHashes {
+		_, err := exec.ExecContext(
+			ctx,
+			`INSERT INTO mfa_backup_codes (id, user_id, code_hash, created_at) VALUES ($1, $2, $3, $4)`,
+			uuid.New(), userID, codeHash, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create backup code: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Consume marks an unused backup code as used and reports whether one matched
+func (r *MFABackupCodeRepository) Consume(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	result, err := r.execer(ctx).ExecContext(
+		ctx,
+		`UPDATE mfa_backup_codes SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL`,
+		time.Now().UnixMilli(), userID, codeHash,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume backup code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CountRemaining returns the number of unused backup codes of a user
+func (r *MFABackupCodeRepository) CountRemaining(ctx context.Context, userID uuid.UUID) (int, error) {
+	var remaining int
+	err := r.db.GetContext(ctx, &remaining, `SELECT COUNT(*) FROM mfa_backup_codes WHERE user_id = $1 AND used_at IS NULL`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count backup codes: %w", err)
+	}
+
+	return remaining, nil
+}
+
+// execer returns the transaction from the context if present, otherwise the store
+func (r *MFABackupCodeRepository) execer(ctx context.Context) sqlx.ExecerContext {
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		return tx
+	}
+	return r.db
+}
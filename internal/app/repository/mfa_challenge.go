@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"wallet-user-svc/db"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+
+	"github.com/google/uuid"
+)
+
+type MFAChallenge struct {
+	ID         uuid.UUID `db:"id"`
+	UserID     uuid.UUID `db:"user_id"`
+	Attempts   int       `db:"attempts"`
+	ExpiresAt  int64     `db:"expires_at"`
+	ConsumedAt *int64    `db:"consumed_at"`
+	CreatedAt  int64     `db:"created_at"`
+}
+
+func (c *MFAChallenge) ToDomain() *domain.MFAChallenge {
+	challenge := &domain.MFAChallenge{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		Attempts:  c.Attempts,
+		ExpiresAt: domain.Timestamp(c.ExpiresAt),
+		CreatedAt: domain.Timestamp(c.CreatedAt),
+	}
+	if c.ConsumedAt != nil {
+		consumedAt := domain.Timestamp(*c.ConsumedAt)
+		challenge.ConsumedAt = &consumedAt
+	}
+	return challenge
+}
+
+type MFAChallengeRepository struct {
+	db db.Store
+}
+
+func NewMFAChallengeRepository(db db.Store) *MFAChallengeRepository {
+	return &MFAChallengeRepository{
+		db: db,
+	}
+}
+
+// Create stores a new challenge
+func (r *MFAChallengeRepository) Create(ctx context.Context, challenge *domain.MFAChallenge) error {
+	query := `
+		INSERT INTO mfa_challenges (id, user_id, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, challenge.ID, challenge.UserID, challenge.Attempts, challenge.ExpiresAt.Millis(), challenge.CreatedAt.Millis())
+	if err != nil {
+		return fmt.Errorf("failed to create MFA challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a challenge by ID
+func (r *MFAChallengeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MFAChallenge, error) {
+	query := `
+		SELECT id, user_id, attempts, expires_at, consumed_at, created_at
+		FROM mfa_challenges
+		WHERE id = $1
+	`
+
+	var challenge MFAChallenge
+	if err := r.db.GetContext(ctx, &challenge, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrInvalidMFAChallenge
+		}
+		return nil, fmt.Errorf("failed to get MFA challenge: %w", err)
+	}
+
+	return challenge.ToDomain(), nil
+}
+
+// ReserveAttempt counts an attempt at the challenge before its code is checked. The limit,
+// expiry and consumption checks are part of the same UPDATE, so concurrent requests cannot
+// get more than maxAttempts codes checked; a challenge that is consumed, out of attempts or
+// expired at now returns ErrInvalidMFAChallenge.
+func (r *MFAChallengeRepository) ReserveAttempt(ctx context.Context, id uuid.UUID, maxAttempts int, now domain.Timestamp) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE mfa_challenges SET attempts = attempts + 1
+		WHERE id = $1 AND consumed_at IS NULL AND attempts < $2 AND expires_at > $3`,
+		id, maxAttempts, now.Millis(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reserve MFA challenge attempt: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrInvalidMFAChallenge
+	}
+
+	return nil
+}
+
+// Consume marks the challenge as used. Only the first caller succeeds, so a
+// challenge can never issue tokens twice.
+func (r *MFAChallengeRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE mfa_challenges SET consumed_at = $1 WHERE id = $2 AND consumed_at IS NULL`,
+		time.Now().UnixMilli(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to consume MFA challenge: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrInvalidMFAChallenge
+	}
+
+	return nil
+}
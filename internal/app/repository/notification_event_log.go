@@ -3,10 +3,14 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"wallet-user-svc/db"
 	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/pkg/utils/cx"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/samber/lo"
 )
 
@@ -23,55 +27,86 @@ type NotificationEventLog struct {
 	EventName string                     `db:"event_name"`
 	Payload   json.RawMessage            `db:"payload"`
 	Status    NotificationEventLogStatus `db:"status"`
+	Priority  int                        `db:"priority"`
+	Attempts  int                        `db:"attempts"`
 	CreatedAt int64                      `db:"created_at"`
 	UpdatedAt int64                      `db:"updated_at"`
+	// DeliveredChannels lists the notification channels the event was already delivered to
+	DeliveredChannels pq.StringArray `db:"delivered_channels"`
 }
 
 func (e *NotificationEventLog) ToModel() *domain.NotificationEventLog {
 	return &domain.NotificationEventLog{
-		ID:        e.ID,
-		EventName: e.EventName,
-		Payload:   e.Payload,
-		Status:    domain.NotificationEventLogStatus(e.Status),
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
+		ID:                e.ID,
+		EventName:         e.EventName,
+		Payload:           e.Payload,
+		Status:            domain.NotificationEventLogStatus(e.Status),
+		Priority:          e.Priority,
+		Attempts:          e.Attempts,
+		CreatedAt:         domain.Timestamp(e.CreatedAt),
+		UpdatedAt:         domain.Timestamp(e.UpdatedAt),
+		DeliveredChannels: e.DeliveredChannels,
 	}
 }
 
+// PriorityAging keeps low-priority events from starving under sustained high-priority
+// load: pending events older than After are ordered as if their priority were Boost
+// higher. After <= 0 disables aging.
+type PriorityAging struct {
+	After time.Duration
+	Boost int
+}
+
 type NotificationEventLogRepository struct {
 	store db.Store
+	stmts *stmtCache
+	aging PriorityAging
 }
 
-func NewNotificationEventLogRepository(store db.Store) *NotificationEventLogRepository {
-	return &NotificationEventLogRepository{store: store}
+func NewNotificationEventLogRepository(store db.Store, aging PriorityAging) *NotificationEventLogRepository {
+	return &NotificationEventLogRepository{store: store, stmts: newStmtCache(store.DB()), aging: aging}
 }
 
+// Create records an event, inside the context's transaction when there is one so the
+// event is only stored if the change it notifies about is
 func (r *NotificationEventLogRepository) Create(ctx context.Context, event *NotificationEventLog) error {
-	_, err := r.store.ExecContext(
-		ctx,
-		`INSERT INTO notification_event_logs (id, event_name, payload, status) 
-		VALUES ($1, $2, $3, $4) RETURNING id`,
-		event.ID, event.EventName, event.Payload, event.Status,
-	)
+	query := `INSERT INTO notification_event_logs (id, event_name, payload, status, priority) 
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	args := []interface{}{event.ID, event.EventName, event.Payload, event.Status, event.Priority}
 
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	}
+
+	_, err := r.store.ExecContext(ctx, query, args...)
 	return err
 }
 
+// FindPendingEvents returns up to batchSize pending events, highest priority first and
+// oldest first within a priority. Events past the aging threshold get the aging boost.
 func (r *NotificationEventLogRepository) FindPendingEvents(
 	ctx context.Context,
 	eventName string,
 	batchSize int,
 ) ([]*domain.NotificationEventLog, error) {
+	// With aging disabled no event is created before the zero cutoff, so none is boosted
+	var agedBefore int64
+	if r.aging.After > 0 {
+		agedBefore = time.Now().Add(-r.aging.After).UnixMilli()
+	}
+
 	events := make([]*NotificationEventLog, 0)
-	err := r.store.SelectContext(
+	err := r.stmts.selectContext(
 		ctx,
+		r.store,
 		&events,
-		`SELECT id, event_name, payload, status, created_at, updated_at 
+		`SELECT id, event_name, payload, status, priority, delivered_channels, created_at, updated_at 
 		FROM notification_event_logs 
 		WHERE event_name = $1 AND status = $2 
-		ORDER BY created_at ASC 
+		ORDER BY priority + CASE WHEN created_at < $4 THEN $5 ELSE 0 END DESC, created_at ASC 
 		LIMIT $3`,
-		eventName, NotificationEventLogStatusPending, batchSize,
+		eventName, NotificationEventLogStatusPending, batchSize, agedBefore, r.aging.Boost,
 	)
 
 	return lo.Map(events, func(event *NotificationEventLog, _ int) *domain.NotificationEventLog {
@@ -92,12 +127,75 @@ func (r *NotificationEventLogRepository) UpdateStatusSuccess(ctx context.Context
 	return nil
 }
 
-func (r *NotificationEventLogRepository) UpdateStatusFailed(ctx context.Context, id string) error {
+// MarkChannelDelivered records that the event was delivered over channel, so a retry of
+// the event skips that channel and only resends the ones that failed
+func (r *NotificationEventLogRepository) MarkChannelDelivered(ctx context.Context, id string, channel string) error {
 	_, err := r.store.ExecContext(
 		ctx,
-		`UPDATE notification_event_logs SET status = $1 WHERE id = $2`,
-		NotificationEventLogStatusFailed, id,
+		`UPDATE notification_event_logs SET delivered_channels = array_append(delivered_channels, $2)
+		WHERE id = $1 AND NOT ($2 = ANY(delivered_channels))`,
+		id, channel,
+	)
+
+	return err
+}
+
+// UpdateStatusFailed marks an event failed, counts the failed attempt and schedules
+// the retry sweeper to try it again at nextRetryAt
+func (r *NotificationEventLogRepository) UpdateStatusFailed(ctx context.Context, id string, nextRetryAt time.Time) error {
+	_, err := r.store.ExecContext(
+		ctx,
+		`UPDATE notification_event_logs SET status = $1, attempts = attempts + 1, next_retry_at = $2 WHERE id = $3`,
+		NotificationEventLogStatusFailed, nextRetryAt.UnixMilli(), id,
 	)
 
 	return err
 }
+
+// FindRetryableEvents returns up to batchSize failed events with fewer than maxAttempts
+// attempts whose retry time has come, the longest waiting first. Events that used up
+// their attempts stay failed for good.
+func (r *NotificationEventLogRepository) FindRetryableEvents(
+	ctx context.Context,
+	maxAttempts int,
+	batchSize int,
+) ([]*domain.NotificationEventLog, error) {
+	events := make([]*NotificationEventLog, 0)
+	err := r.stmts.selectContext(
+		ctx,
+		r.store,
+		&events,
+		`SELECT id, event_name, payload, status, priority, attempts, delivered_channels, created_at, updated_at 
+		FROM notification_event_logs 
+		WHERE status = $1 AND attempts < $2 AND next_retry_at <= $3 
+		ORDER BY next_retry_at ASC 
+		LIMIT $4`,
+		NotificationEventLogStatusFailed, maxAttempts, time.Now().UnixMilli(), batchSize,
+	)
+
+	return lo.Map(events, func(event *NotificationEventLog, _ int) *domain.NotificationEventLog {
+		return event.ToModel()
+	}), err
+}
+
+// DeletePublishedBefore deletes up to limit successfully published events last updated
+// before cutoff and returns the number of rows removed. Deleting in bounded batches
+// keeps each statement short so it does not hold locks on the table for long.
+func (r *NotificationEventLogRepository) DeletePublishedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	result, err := r.store.ExecContext(
+		ctx,
+		`DELETE FROM notification_event_logs
+		WHERE id IN (
+			SELECT id FROM notification_event_logs
+			WHERE status = $1 AND updated_at < $2
+			ORDER BY updated_at ASC
+			LIMIT $3
+		)`,
+		NotificationEventLogStatusSuccess, cutoff.UnixMilli(), limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
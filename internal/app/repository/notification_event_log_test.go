@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var notificationEventLogColumns = []string{"id", "event_name", "payload", "status", "priority", "created_at", "updated_at"}
+
+func TestNotificationEventLogRepository_FindPendingEventsAging(t *testing.T) {
+	store, fake := newFakeStore(t)
+	now := time.Now().UnixMilli()
+	fake.setRows(notificationEventLogColumns,
+		[]driver.Value{uuid.NewString(), "user_registered", []byte(`{}`), "pending", 5, now, now},
+	)
+
+	aged := NewNotificationEventLogRepository(store, PriorityAging{After: 10 * time.Minute, Boost: 10})
+	events, err := aged.FindPendingEvents(context.Background(), "user_registered", 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, 5, events[0].Priority)
+
+	// Arguments: event name, status, batch size, aging cutoff, boost
+	require.Len(t, fake.lastArgs, 5)
+	cutoff := time.Now().Add(-10 * time.Minute).UnixMilli()
+	assert.InDelta(t, cutoff, fake.lastArgs[3], float64(time.Second.Milliseconds()))
+	assert.EqualValues(t, 10, fake.lastArgs[4])
+
+	disabled := NewNotificationEventLogRepository(store, PriorityAging{})
+	_, err = disabled.FindPendingEvents(context.Background(), "user_registered", 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, fake.lastArgs[3], "no event is older than the zero cutoff")
+}
+
+func TestNotificationEventLogRepository_FindRetryableEvents(t *testing.T) {
+	store, fake := newFakeStore(t)
+	now := time.Now().UnixMilli()
+	fake.setRows([]string{"id", "event_name", "payload", "status", "priority", "attempts", "created_at", "updated_at"},
+		[]driver.Value{uuid.NewString(), "login", []byte(`{}`), "failed", 0, 2, now, now},
+	)
+
+	repo := NewNotificationEventLogRepository(store, PriorityAging{})
+	events, err := repo.FindRetryableEvents(context.Background(), 5, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, 2, events[0].Attempts)
+
+	// Arguments: status, max attempts, now, batch size
+	require.Len(t, fake.lastArgs, 4)
+	assert.Equal(t, "failed", fake.lastArgs[0])
+	assert.EqualValues(t, 5, fake.lastArgs[1])
+	assert.InDelta(t, now, fake.lastArgs[2], float64(time.Second.Milliseconds()))
+	assert.EqualValues(t, 100, fake.lastArgs[3])
+}
+
+func BenchmarkNotificationEventLogRepository_FindPendingEvents(b *testing.B) {
+	store, fake := newFakeStore(b)
+	now := time.Now().UnixMilli()
+	rows := make([][]driver.Value, 0, 10)
+	for i := 0; i < 10; i++ {
+		rows = append(rows, []driver.Value{uuid.NewString(), "user_registered", []byte(`{"user_id":"1"}`), "pending", 0, now, now})
+	}
+	fake.setRows(notificationEventLogColumns, rows...)
+	repo := NewNotificationEventLogRepository(store, PriorityAging{After: 10 * time.Minute, Boost: 10})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.FindPendingEvents(ctx, "user_registered", 10); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(fake.prepares.Load())/float64(b.N), "prepares/op")
+}
+
+func TestNotificationEventLogRepository_CreateUsesTransaction(t *testing.T) {
+	store, fake := newFakeStore(t)
+	repo := NewNotificationEventLogRepository(store, PriorityAging{})
+	event := &NotificationEventLog{ID: uuid.NewString(), EventName: "login", Payload: []byte(`{}`), Status: NotificationEventLogStatusPending}
+
+	require.NoError(t, repo.Create(context.Background(), event))
+	assert.False(t, fake.lastExecInTx)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	defer tx.Rollback()
+	ctx := context.WithValue(context.Background(), cx.TransactionContextKey, tx)
+
+	require.NoError(t, repo.Create(ctx, event))
+	assert.True(t, fake.lastExecInTx, "the event must be inserted on the context's transaction")
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"wallet-user-svc/db"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationPreference struct {
+	UserID    uuid.UUID `db:"user_id"`
+	EventType string    `db:"event_type"`
+	Enabled   bool      `db:"enabled"`
+}
+
+func (p *NotificationPreference) ToDomain() *domain.NotificationPreference {
+	return &domain.NotificationPreference{
+		UserID:    p.UserID,
+		EventType: p.EventType,
+		Enabled:   p.Enabled,
+	}
+}
+
+type NotificationPreferenceRepository struct {
+	db db.Store
+}
+
+func NewNotificationPreferenceRepository(db db.Store) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		db: db,
+	}
+}
+
+// IsEnabled reports whether a user receives notifications of eventType, returning
+// defaultEnabled when the user has not stored a preference for it
+func (r *NotificationPreferenceRepository) IsEnabled(ctx context.Context, userID uuid.UUID, eventType string, defaultEnabled bool) (bool, error) {
+	query := `SELECT enabled FROM notification_preferences WHERE user_id = $1 AND event_type = $2`
+
+	var enabled bool
+	if err := r.db.GetContext(ctx, &enabled, query, userID, eventType); err != nil {
+		if err == sql.ErrNoRows {
+			return defaultEnabled, nil
+		}
+		return false, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+
+	return enabled, nil
+}
+
+// ListByUserID returns the preferences a user has stored, ordered by event type
+func (r *NotificationPreferenceRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.NotificationPreference, error) {
+	query := `
+		SELECT user_id, event_type, enabled
+		FROM notification_preferences
+		WHERE user_id = $1
+		ORDER BY event_type
+	`
+
+	var rows []NotificationPreference
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list notification preferences: %w", err)
+	}
+
+	preferences := make([]*domain.NotificationPreference, 0, len(rows))
+	for i := range rows {
+		preferences = append(preferences, rows[i].ToDomain())
+	}
+
+	return preferences, nil
+}
+
+// Upsert stores a preference, replacing an existing one for the same user and event type
+func (r *NotificationPreferenceRepository) Upsert(ctx context.Context, preference *domain.NotificationPreference) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, event_type, enabled)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, event_type) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	if _, err := r.execer(ctx).ExecContext(ctx, query, preference.UserID, preference.EventType, preference.Enabled); err != nil {
+		return fmt.Errorf("failed to upsert notification preference: %w", err)
+	}
+
+	return nil
+}
+
+// execer returns the transaction from the context if present, otherwise the store
+func (r *NotificationPreferenceRepository) execer(ctx context.Context) sqlx.ExecerContext {
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		return tx
+	}
+	return r.db
+}
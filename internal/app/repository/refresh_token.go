@@ -17,50 +17,92 @@ import (
 type RefreshToken struct {
 	ID        uuid.UUID `db:"id"`
 	UserID    uuid.UUID `db:"user_id"`
+	TenantID  string    `db:"tenant_id"`
 	Token     string    `db:"token"`
 	ExpiresAt int64     `db:"expires_at"`
 	IsRevoked bool      `db:"is_revoked"`
 	CreatedAt int64     `db:"created_at"`
 	UpdatedAt int64     `db:"updated_at"`
+	// FingerprintHash is NULL for tokens issued before fingerprints were recorded
+	FingerprintHash *string    `db:"fingerprint_hash"`
+	FamilyID        uuid.UUID  `db:"family_id"`
+	ReplacedBy      *uuid.UUID `db:"replaced_by"`
 }
 
 func (rt *RefreshToken) ToDomain() *domain.RefreshToken {
-	return &domain.RefreshToken{
-		ID:        rt.ID,
-		UserID:    rt.UserID,
-		Token:     rt.Token,
-		ExpiresAt: rt.ExpiresAt,
-		IsRevoked: rt.IsRevoked,
-		CreatedAt: rt.CreatedAt,
-		UpdatedAt: rt.UpdatedAt,
+	refreshToken := &domain.RefreshToken{
+		ID:         rt.ID,
+		UserID:     rt.UserID,
+		Token:      rt.Token,
+		ExpiresAt:  domain.Timestamp(rt.ExpiresAt),
+		IsRevoked:  rt.IsRevoked,
+		CreatedAt:  domain.Timestamp(rt.CreatedAt),
+		UpdatedAt:  domain.Timestamp(rt.UpdatedAt),
+		FamilyID:   rt.FamilyID,
+		ReplacedBy: rt.ReplacedBy,
 	}
+	if rt.FingerprintHash != nil {
+		refreshToken.Fingerprint = *rt.FingerprintHash
+	}
+	return refreshToken
+}
+
+// RefreshTokenCounts counts refresh tokens by state; expired tokens are unrevoked but
+// past their expiry
+type RefreshTokenCounts struct {
+	Active  int64 `db:"active"`
+	Revoked int64 `db:"revoked"`
+	Expired int64 `db:"expired"`
 }
 
+// UserRefreshTokenCounts counts the refresh tokens of one user
+type UserRefreshTokenCounts struct {
+	UserID uuid.UUID `db:"user_id"`
+	RefreshTokenCounts
+}
+
+// refreshTokenCountColumns counts the tokens of each state as of $1
+const refreshTokenCountColumns = `
+	COUNT(*) FILTER (WHERE is_revoked = FALSE AND expires_at > $1) AS active,
+	COUNT(*) FILTER (WHERE is_revoked = TRUE) AS revoked,
+	COUNT(*) FILTER (WHERE is_revoked = FALSE AND expires_at <= $1) AS expired`
+
 type RefreshTokenRepository struct {
-	db db.Store
+	db    db.Store
+	stmts *stmtCache
 }
 
 func NewRefreshTokenRepository(db db.Store) *RefreshTokenRepository {
 	return &RefreshTokenRepository{
-		db: db,
+		db:    db,
+		stmts: newStmtCache(db.DB()),
 	}
 }
 
 // Create creates a new refresh token
 func (r *RefreshTokenRepository) Create(ctx context.Context, refreshToken *domain.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, user_id, token, expires_at, is_revoked, created_at, updated_at)
-		VALUES (:id, :user_id, :token, :expires_at, :is_revoked, :created_at, :updated_at)
+		INSERT INTO refresh_tokens (id, user_id, tenant_id, token, expires_at, is_revoked, created_at, updated_at, fingerprint_hash, family_id)
+		VALUES (:id, :user_id, :tenant_id, :token, :expires_at, :is_revoked, :created_at, :updated_at, :fingerprint_hash, :family_id)
 	`
 
 	repoRefreshToken := &RefreshToken{
 		ID:        refreshToken.ID,
 		UserID:    refreshToken.UserID,
+		TenantID:  tenantID(ctx),
 		Token:     refreshToken.Token,
-		ExpiresAt: refreshToken.ExpiresAt,
+		ExpiresAt: refreshToken.ExpiresAt.Millis(),
 		IsRevoked: refreshToken.IsRevoked,
-		CreatedAt: refreshToken.CreatedAt,
-		UpdatedAt: refreshToken.UpdatedAt,
+		CreatedAt: refreshToken.CreatedAt.Millis(),
+		UpdatedAt: refreshToken.UpdatedAt.Millis(),
+		FamilyID:  refreshToken.FamilyID,
+	}
+	if refreshToken.Fingerprint != "" {
+		repoRefreshToken.FingerprintHash = &refreshToken.Fingerprint
+	}
+	// A token without a family starts its own
+	if repoRefreshToken.FamilyID == uuid.Nil {
+		repoRefreshToken.FamilyID = refreshToken.ID
 	}
 
 	// Check if we're in a transaction
@@ -85,34 +127,191 @@ func (r *RefreshTokenRepository) Create(ctx context.Context, refreshToken *domai
 // GetByTokenHash retrieves a refresh token by token hash
 func (r *RefreshTokenRepository) GetByToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token, expires_at, is_revoked, created_at, updated_at
+		SELECT id, user_id, token, expires_at, is_revoked, created_at, updated_at, fingerprint_hash, family_id, replaced_by
 		FROM refresh_tokens 
-		WHERE token = $1
+		WHERE token = $1 AND tenant_id = $2
 	`
 
 	var refreshToken RefreshToken
 
-	// Check if we're in a transaction
-	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
-		// Use transaction
-		err := tx.QueryRowContext(ctx, query, tokenHash).Scan(&refreshToken.ID, &refreshToken.UserID, &refreshToken.Token, &refreshToken.ExpiresAt, &refreshToken.IsRevoked, &refreshToken.CreatedAt, &refreshToken.UpdatedAt)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return nil, errs.ErrTokenNotFound
-			}
-			return nil, fmt.Errorf("failed to get refresh token by token: %w", err)
+	err := r.stmts.queryRowContext(ctx, r.db, query, tokenHash, tenantID(ctx)).Scan(&refreshToken.ID, &refreshToken.UserID, &refreshToken.Token, &refreshToken.ExpiresAt, &refreshToken.IsRevoked, &refreshToken.CreatedAt, &refreshToken.UpdatedAt, &refreshToken.FingerprintHash, &refreshToken.FamilyID, &refreshToken.ReplacedBy)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrTokenNotFound
 		}
-		return refreshToken.ToDomain(), nil
+		return nil, fmt.Errorf("failed to get refresh token by token: %w", err)
 	}
 
-	// Use main database connection
-	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&refreshToken.ID, &refreshToken.UserID, &refreshToken.Token, &refreshToken.ExpiresAt, &refreshToken.IsRevoked, &refreshToken.CreatedAt, &refreshToken.UpdatedAt)
+	return refreshToken.ToDomain(), nil
+}
+
+// GetByID retrieves a refresh token by its ID
+func (r *RefreshTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, token, expires_at, is_revoked, created_at, updated_at, fingerprint_hash, family_id, replaced_by
+		FROM refresh_tokens
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	var refreshToken RefreshToken
+
+	err := r.stmts.getContext(ctx, r.db, &refreshToken, query, id, tenantID(ctx))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errs.ErrTokenNotFound
 		}
-		return nil, fmt.Errorf("failed to get refresh token by token: %w", err)
+		return nil, fmt.Errorf("failed to get refresh token by id: %w", err)
 	}
 
 	return refreshToken.ToDomain(), nil
 }
+
+// ListActiveByUserID returns the unrevoked refresh tokens of a user that expire after
+// now, newest first
+func (r *RefreshTokenRepository) ListActiveByUserID(ctx context.Context, userID uuid.UUID, now domain.Timestamp) ([]*domain.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, token, expires_at, is_revoked, created_at, updated_at, fingerprint_hash, family_id, replaced_by
+		FROM refresh_tokens
+		WHERE user_id = $1 AND tenant_id = $3 AND is_revoked = FALSE AND expires_at > $2
+		ORDER BY created_at DESC
+	`
+
+	var rows []RefreshToken
+
+	err := r.stmts.selectContext(ctx, r.db, &rows, query, userID, now.Millis(), tenantID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+
+	refreshTokens := make([]*domain.RefreshToken, 0, len(rows))
+	for i := range rows {
+		refreshTokens = append(refreshTokens, rows[i].ToDomain())
+	}
+	return refreshTokens, nil
+}
+
+// RevokeByID marks a refresh token as revoked, returning ErrTokenNotFound when no
+// unrevoked token has the ID
+func (r *RefreshTokenRepository) RevokeByID(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET is_revoked = TRUE WHERE id = $1 AND tenant_id = $2 AND is_revoked = FALSE`
+
+	var result sql.Result
+	var err error
+
+	// Check if we're in a transaction
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		result, err = tx.ExecContext(ctx, query, id, tenantID(ctx))
+	} else {
+		result, err = r.db.ExecContext(ctx, query, id, tenantID(ctx))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errs.ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// MarkReplaced revokes a refresh token and links it to the token that replaced it on
+// rotation. It returns ErrTokenNotFound when no unrevoked, unrotated token has the ID,
+// e.g. because a concurrent request rotated it first.
+func (r *RefreshTokenRepository) MarkReplaced(ctx context.Context, id, replacedBy uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens SET is_revoked = TRUE, replaced_by = $2
+		WHERE id = $1 AND tenant_id = $3 AND is_revoked = FALSE AND replaced_by IS NULL
+	`
+
+	var result sql.Result
+	var err error
+
+	// Check if we're in a transaction
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		result, err = tx.ExecContext(ctx, query, id, replacedBy, tenantID(ctx))
+	} else {
+		result, err = r.db.ExecContext(ctx, query, id, replacedBy, tenantID(ctx))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark refresh token replaced: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errs.ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every unrevoked refresh token of a family and returns how many
+// were revoked
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	query := `UPDATE refresh_tokens SET is_revoked = TRUE WHERE family_id = $1 AND tenant_id = $2 AND is_revoked = FALSE`
+
+	var result sql.Result
+	var err error
+
+	// Check if we're in a transaction
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		result, err = tx.ExecContext(ctx, query, familyID, tenantID(ctx))
+	} else {
+		result, err = r.db.ExecContext(ctx, query, familyID, tenantID(ctx))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// CountByState counts the refresh tokens of the tenant by their state as of now
+func (r *RefreshTokenRepository) CountByState(ctx context.Context, now domain.Timestamp) (*RefreshTokenCounts, error) {
+	query := `SELECT` + refreshTokenCountColumns + `
+		FROM refresh_tokens
+		WHERE tenant_id = $2
+	`
+
+	var counts RefreshTokenCounts
+
+	err := r.stmts.getContext(ctx, r.db, &counts, query, now.Millis(), tenantID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count refresh tokens: %w", err)
+	}
+
+	return &counts, nil
+}
+
+// CountByUser counts the refresh tokens of each user of the tenant by their state as of
+// now. Users are ordered by active tokens, most first, and paged with limit and offset.
+func (r *RefreshTokenRepository) CountByUser(ctx context.Context, now domain.Timestamp, limit, offset int) ([]UserRefreshTokenCounts, error) {
+	query := `SELECT user_id,` + refreshTokenCountColumns + `
+		FROM refresh_tokens
+		WHERE tenant_id = $2
+		GROUP BY user_id
+		ORDER BY active DESC, user_id
+		LIMIT $3 OFFSET $4
+	`
+
+	var counts []UserRefreshTokenCounts
+
+	err := r.stmts.selectContext(ctx, r.db, &counts, query, now.Millis(), tenantID(ctx), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count refresh tokens by user: %w", err)
+	}
+
+	return counts, nil
+}
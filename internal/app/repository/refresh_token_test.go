@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/model/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var refreshTokenColumns = []string{
+	"id", "user_id", "token", "expires_at", "is_revoked", "created_at", "updated_at", "fingerprint_hash",
+	"family_id", "replaced_by",
+}
+
+func refreshTokenRow(id, userID uuid.UUID, token string) []driver.Value {
+	now := time.Now().UnixMilli()
+	return []driver.Value{
+		id.String(), userID.String(), token, now + time.Hour.Milliseconds(), false, now, now, nil, id.String(), nil,
+	}
+}
+
+func TestRefreshTokenRepository_GetByToken(t *testing.T) {
+	store, fake := newFakeStore(t)
+	id, userID := uuid.New(), uuid.New()
+	fake.setRows(refreshTokenColumns, refreshTokenRow(id, userID, "token-hash"))
+	repo := NewRefreshTokenRepository(store)
+
+	refreshToken, err := repo.GetByToken(context.Background(), "token-hash")
+	require.NoError(t, err)
+	assert.Equal(t, id, refreshToken.ID)
+	assert.Equal(t, userID, refreshToken.UserID)
+	assert.Empty(t, refreshToken.Fingerprint)
+	assert.Equal(t, id, refreshToken.FamilyID)
+	assert.False(t, refreshToken.IsRotated())
+}
+
+func BenchmarkRefreshTokenRepository_GetByToken(b *testing.B) {
+	store, fake := newFakeStore(b)
+	fake.setRows(refreshTokenColumns, refreshTokenRow(uuid.New(), uuid.New(), "token-hash"))
+	repo := NewRefreshTokenRepository(store)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetByToken(ctx, "token-hash"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(fake.prepares.Load())/float64(b.N), "prepares/op")
+}
+
+func TestRefreshTokenRepository_CountByUser(t *testing.T) {
+	store, fake := newFakeStore(t)
+	first, second := uuid.New(), uuid.New()
+	fake.setRows([]string{"user_id", "active", "revoked", "expired"},
+		[]driver.Value{first.String(), int64(5), int64(1), int64(0)},
+		[]driver.Value{second.String(), int64(2), int64(0), int64(3)},
+	)
+	repo := NewRefreshTokenRepository(store)
+
+	now := domain.Now()
+	counts, err := repo.CountByUser(context.Background(), now, 2, 4)
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, first, counts[0].UserID)
+	assert.Equal(t, RefreshTokenCounts{Active: 5, Revoked: 1}, counts[0].RefreshTokenCounts)
+	assert.Equal(t, RefreshTokenCounts{Active: 2, Expired: 3}, counts[1].RefreshTokenCounts)
+
+	// Arguments: now, tenant, limit, offset
+	require.Len(t, fake.lastArgs, 4)
+	assert.EqualValues(t, now.Millis(), fake.lastArgs[0])
+	assert.EqualValues(t, 2, fake.lastArgs[2])
+	assert.EqualValues(t, 4, fake.lastArgs[3])
+}
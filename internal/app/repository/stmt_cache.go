@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"wallet-user-svc/db"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// stmtCache lazily prepares statements on the main database connection and reuses them
+// across calls. Statements prepared on the pool cannot be used inside a transaction, so
+// queries run in one are executed ad hoc on the transaction instead.
+type stmtCache struct {
+	db    *sqlx.DB
+	mu    sync.RWMutex
+	stmts map[string]*sqlx.Stmt
+}
+
+func newStmtCache(db *sqlx.DB) *stmtCache {
+	return &stmtCache{
+		db:    db,
+		stmts: make(map[string]*sqlx.Stmt),
+	}
+}
+
+// get returns the prepared statement for query, preparing it on first use. Failed
+// preparations are not cached so the next call tries again.
+func (c *stmtCache) get(query string) (*sqlx.Stmt, error) {
+	c.mu.RLock()
+	stmt, ok := c.stmts[query]
+	c.mu.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	// Prepare without the caller's context so a cancelled request cannot poison the cache
+	stmt, err := c.db.PreparexContext(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	c.stmts[query] = stmt
+
+	return stmt, nil
+}
+
+// rowScanner is the Scan side of *sql.Row and *sqlx.Row
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryRowContext is getContext for callers that scan the columns themselves
+func (c *stmtCache) queryRowContext(ctx context.Context, store db.Store, query string, args ...interface{}) rowScanner {
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+
+	stmt, err := c.get(query)
+	if err != nil {
+		return store.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// getContext runs a single-row query into dest, inside the context's transaction when
+// there is one, and through the cached statement otherwise. When the statement cannot be
+// prepared the query runs ad hoc on store.
+func (c *stmtCache) getContext(ctx context.Context, store db.Store, dest interface{}, query string, args ...interface{}) error {
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		return tx.GetContext(ctx, dest, query, args...)
+	}
+
+	stmt, err := c.get(query)
+	if err != nil {
+		return store.GetContext(ctx, dest, query, args...)
+	}
+	return stmt.GetContext(ctx, dest, args...)
+}
+
+// selectContext is getContext for queries returning any number of rows
+func (c *stmtCache) selectContext(ctx context.Context, store db.Store, dest interface{}, query string, args ...interface{}) error {
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		return tx.SelectContext(ctx, dest, query, args...)
+	}
+
+	stmt, err := c.get(query)
+	if err != nil {
+		return store.SelectContext(ctx, dest, query, args...)
+	}
+	return stmt.SelectContext(ctx, dest, args...)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStmtCache_PreparesOncePerQuery(t *testing.T) {
+	store, fake := newFakeStore(t)
+	fake.setRows(userColumns, userRow(uuid.New(), "known@example.com"))
+	repo := NewUserRepository(store, true)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := repo.GetByEmail(ctx, "known@example.com")
+		require.NoError(t, err)
+		_, err = repo.GetByID(ctx, uuid.New())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(2), fake.prepares.Load())
+}
+
+func TestStmtCache_TransactionRunsAdHoc(t *testing.T) {
+	store, fake := newFakeStore(t)
+	fake.setRows(userColumns, userRow(uuid.New(), "known@example.com"))
+	repo := NewUserRepository(store, true)
+
+	tx, err := store.DB().Beginx()
+	require.NoError(t, err)
+	defer tx.Rollback()
+	ctx := context.WithValue(context.Background(), cx.TransactionContextKey, tx)
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetByEmail(ctx, "known@example.com")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(2), fake.prepares.Load(), "queries in a transaction are prepared on it each time")
+	assert.Empty(t, repo.stmts.stmts, "statements bound to a transaction must not be cached")
+}
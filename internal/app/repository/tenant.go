@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"wallet-user-svc/pkg/utils/cx"
+)
+
+// tenantID returns the tenant the request is scoped to. Without tenancy every row
+// belongs to the empty default tenant, so queries filter by it unconditionally.
+func tenantID(ctx context.Context) string {
+	tenantID, _ := cx.GetTenantID(ctx)
+	return tenantID
+}
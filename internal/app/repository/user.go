@@ -3,81 +3,137 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"wallet-user-svc/db"
 	"wallet-user-svc/internal/app/errs"
 	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
 	"wallet-user-svc/pkg/utils/cx"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // User domain model
 type User struct {
 	ID           string  `db:"id"`
+	TenantID     string  `db:"tenant_id"`
 	Email        *domain.Email  `db:"email"`
 	Username     string  `db:"username"`
+	// UniqueUsername is the lowercased username when usernames are unique, NULL otherwise
+	UniqueUsername *string `db:"unique_username"`
+	Role         string  `db:"role"`
 	CountryCode  *domain.CountryCode `db:"country_code"`
 	Phone        *domain.PhoneNumber `db:"phone"`
 	PasswordHash string  `db:"password_hash"`
+	TOTPSecret   *string `db:"totp_secret"`
+	TOTPEnabled  bool    `db:"totp_enabled"`
 	CreatedAt    int64   `db:"created_at"`
 	UpdatedAt    int64   `db:"updated_at"`
+	Status             string `db:"status"`
+	EmailVerified      bool `db:"email_verified"`
+	MustChangePassword bool `db:"must_change_password"`
+	PasswordChangedAt  int64 `db:"password_changed_at"`
+	PrimaryContact     string `db:"primary_contact"`
 }
 
-func (u *User) ToDomain() *domain.User {
-	username, err := domain.NewUsername(u.Username)
+// ToDomain maps the row to a domain user. A row that does not map, e.g. one whose id
+// is not a UUID or whose username fails validation, is reported as an
+// errs.CorruptRecordError rather than returned half converted.
+func (u *User) ToDomain() (*domain.User, error) {
+	id, err := uuid.Parse(u.ID)
 	if err != nil {
-		// This should not happen in normal operation since we store validated usernames
-		// But we need to handle it for backward compatibility
-		username = domain.Username(u.Username)
+		return nil, errs.NewCorruptRecordError("users", u.ID, fmt.Errorf("invalid id: %w", err))
 	}
 
-	id, err := uuid.Parse(u.ID)
-	if err != nil {
-		id = uuid.Nil
+	username := domain.Username(u.Username)
+	if err := username.Validate(); err != nil {
+		return nil, errs.NewCorruptRecordError("users", u.ID, fmt.Errorf("invalid username: %w", err))
 	}
 
-	
 	return &domain.User{
 		ID:           id,
 		Email:        u.Email,
 		Username:     username,
+		Role:         domain.Role(u.Role),
 		CountryCode:  u.CountryCode,
 		Phone:        u.Phone,
 		PasswordHash: domain.PasswordHash(u.PasswordHash),
-		CreatedAt:    u.CreatedAt,
-		UpdatedAt:    u.UpdatedAt,
-	}
+		TOTPSecret:   u.TOTPSecret,
+		TOTPEnabled:  u.TOTPEnabled,
+		CreatedAt:    domain.Timestamp(u.CreatedAt),
+		UpdatedAt:    domain.Timestamp(u.UpdatedAt),
+		AccountState: domain.AccountState{
+			Status:             domain.AccountStatus(u.Status),
+			EmailVerified:      u.EmailVerified,
+			MustChangePassword: u.MustChangePassword,
+		},
+		PasswordChangedAt:  domain.Timestamp(u.PasswordChangedAt),
+		PrimaryContact:     domain.ContactMethod(u.PrimaryContact),
+	}, nil
 }
 
+// uniqueUsernameIndex is the unique index enforcing case-insensitive usernames per tenant
+const uniqueUsernameIndex = "idx_users_tenant_unique_username"
+
+// uniqueViolationCode is the PostgreSQL error code for unique constraint violations
+const uniqueViolationCode = "23505"
+
 type UserRepository struct {
-	db db.Store
+	db              db.Store
+	stmts           *stmtCache
+	uniqueUsernames bool
 }
 
-func NewUserRepository(db db.Store) *UserRepository {
+// NewUserRepository creates a user repository. With uniqueUsernames set, new users reserve
+// their lowercased username so no two users can share it.
+func NewUserRepository(db db.Store, uniqueUsernames bool) *UserRepository {
 	return &UserRepository{
-		db: db,
+		db:              db,
+		stmts:           newStmtCache(db.DB()),
+		uniqueUsernames: uniqueUsernames,
 	}
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, username, country_code, phone, password_hash, created_at, updated_at)
-		VALUES (:id, :email, :username, :country_code, :phone, :password_hash, :created_at, :updated_at)
+		INSERT INTO users (id, tenant_id, email, username, unique_username, role, country_code, phone, password_hash, status, email_verified, must_change_password, password_changed_at, primary_contact, created_at, updated_at)
+		VALUES (:id, :tenant_id, :email, :username, :unique_username, :role, :country_code, :phone, :password_hash, :status, :email_verified, :must_change_password, :password_changed_at, :primary_contact, :created_at, :updated_at)
 	`
 
 	// Convert domain user to repository user
 	repoUser := &User{
 		ID:           user.ID.String(),
+		TenantID:     tenantID(ctx),
 		Email:        user.Email,
 		Username:     user.Username.String(),
+		Role:         user.Role.String(),
 		CountryCode:  user.CountryCode,
 		Phone:        user.Phone,	
 		PasswordHash: user.PasswordHash.String(),
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+		CreatedAt:    user.CreatedAt.Millis(),
+		UpdatedAt:    user.UpdatedAt.Millis(),
+		Status:             user.Status.String(),
+		EmailVerified:      user.EmailVerified,
+		MustChangePassword: user.MustChangePassword,
+		PasswordChangedAt:  user.PasswordChangedAt.Millis(),
+		PrimaryContact:     user.PrimaryContact.String(),
+	}
+	if repoUser.Status == "" {
+		repoUser.Status = domain.AccountStatusActive.String()
+	}
+	if repoUser.PrimaryContact == "" {
+		repoUser.PrimaryContact = domain.ContactMethodEmail.String()
+		if user.Email == nil {
+			repoUser.PrimaryContact = domain.ContactMethodPhone.String()
+		}
+	}
+	if r.uniqueUsernames {
+		uniqueUsername := domain.UsernameKey(user.Username.String())
+		repoUser.UniqueUsername = &uniqueUsername
 	}
 
 	// Check if we're in a transaction
@@ -85,7 +141,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		// Use transaction
 		_, err := tx.NamedExecContext(ctx, query, repoUser)
 		if err != nil {
-			return fmt.Errorf("failed to create user: %w", err)
+			return createUserError(err)
 		}
 		return nil
 	}
@@ -93,36 +149,61 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	// Use main database connection
 	_, err := r.db.NamedExecContext(ctx, query, repoUser)
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return createUserError(err)
 	}
 
 	return nil
 }
 
-func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	query := `
-		SELECT id, email, username, country_code, phone, password_hash, created_at, updated_at
-		FROM users 
-		WHERE id = $1
-	`
+// createUserError maps unique violations on insert to the matching domain error. Two
+// concurrent registrations with the same email or phone both pass the service checks;
+// the losing insert violates idx_users_tenant_email_unique or
+// idx_users_tenant_country_code_phone_unique and is reported as ErrUserExists rather than an internal error.
+func createUserError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		if pqErr.Constraint == uniqueUsernameIndex {
+			return errs.ErrUsernameTaken
+		}
+		return errs.ErrUserExists
+	}
+	return fmt.Errorf("failed to create user: %w", err)
+}
 
-	var user User
+// ExistsByUsername reports whether a username is already reserved in the tenant, ignoring case
+func (r *UserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE unique_username = $1 AND tenant_id = $2)`
+	username = domain.UsernameKey(username)
+
+	var exists bool
 
 	// Check if we're in a transaction
 	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
 		// Use transaction
-		err := tx.GetContext(ctx, &user, query, id.String())
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return nil, errs.ErrUserNotFound
-			}
-			return nil, fmt.Errorf("failed to get user by ID: %w", err)
+		if err := tx.GetContext(ctx, &exists, query, username, tenantID(ctx)); err != nil {
+			return false, fmt.Errorf("failed to check username: %w", err)
 		}
-		return user.ToDomain(), nil
+		return exists, nil
 	}
 
 	// Use main database connection
-	err := r.db.GetContext(ctx, &user, query, id.String())
+	if err := r.db.GetContext(ctx, &exists, query, username, tenantID(ctx)); err != nil {
+		return false, fmt.Errorf("failed to check username: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	query := `
+		SELECT id, email, username, role, country_code, phone, password_hash, totp_secret, totp_enabled, status, email_verified, must_change_password, password_changed_at, primary_contact, created_at, updated_at
+		FROM users 
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	var user User
+
+	err := r.stmts.getContext(ctx, r.db, &user, query, id.String(), tenantID(ctx))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errs.ErrUserNotFound
@@ -130,81 +211,259 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
 
-	return user.ToDomain(), nil
+	return user.ToDomain()
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, email, username, country_code, phone, password_hash, created_at, updated_at
+		SELECT id, email, username, role, country_code, phone, password_hash, totp_secret, totp_enabled, status, email_verified, must_change_password, password_changed_at, primary_contact, created_at, updated_at
 		FROM users 
-		WHERE email = $1
+		WHERE email = $1 AND tenant_id = $2
 	`
 
 	var user User
 
-	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
-		// Use transaction
-		err := tx.GetContext(ctx, &user, query, email)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return nil, errs.ErrUserNotFound
-			}
-			return nil, fmt.Errorf("failed to get user by email: %w", err)
+	err := r.stmts.getContext(ctx, r.db, &user, query, email, tenantID(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrUserNotFound
 		}
 
-		return user.ToDomain(), nil
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
 
-	// Use main database connection
-	err := r.db.GetContext(ctx, &user, query, email)
+	return user.ToDomain()
+}
+
+func (r *UserRepository) GetByPhone(ctx context.Context, countryCode, phone string) (*domain.User, error) {
+	query := `
+		SELECT id, email, username, role, country_code, phone, password_hash, totp_secret, totp_enabled, status, email_verified, must_change_password, password_changed_at, primary_contact, created_at, updated_at
+		FROM users 
+		WHERE country_code = $1 AND phone = $2 AND tenant_id = $3
+	`
+
+	var user User
+
+	err := r.stmts.getContext(ctx, r.db, &user, query, countryCode, phone, tenantID(ctx))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errs.ErrUserNotFound
 		}
 
-		return nil, fmt.Errorf("failed to get user by email: %w", err)
+		return nil, fmt.Errorf("failed to get user by phone: %w", err)
 	}
 
-	return user.ToDomain(), nil
+	return user.ToDomain()
 }
 
-func (r *UserRepository) GetByPhone(ctx context.Context, countryCode, phone string) (*domain.User, error) {
+// GetByUsername retrieves a user by username, ignoring case. Only usernames reserved while
+// usernames are unique identify a user; other users cannot be found by username.
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	query := `
-		SELECT id, email, username, country_code, phone, password_hash, created_at, updated_at
-		FROM users 
-		WHERE country_code = $1 AND phone = $2
+		SELECT id, email, username, role, country_code, phone, password_hash, totp_secret, totp_enabled, status, email_verified, must_change_password, password_changed_at, primary_contact, created_at, updated_at
+		FROM users
+		WHERE unique_username = $1 AND tenant_id = $2
 	`
+	username = domain.UsernameKey(username)
 
 	var user User
+	var err error
 
+	// Check if we're in a transaction
 	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
 		// Use transaction
-		err := tx.GetContext(ctx, &user, query, countryCode, phone)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return nil, errs.ErrUserNotFound
-			}
-			return nil, fmt.Errorf("failed to get user by phone: %w", err)
+		err = tx.GetContext(ctx, &user, query, username, tenantID(ctx))
+	} else {
+		// Use main database connection
+		err = r.db.GetContext(ctx, &user, query, username, tenantID(ctx))
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrUserNotFound
 		}
+		return nil, fmt.Errorf("failed to get user by username: %w", err)
+	}
 
-		return user.ToDomain(), nil
+	return user.ToDomain()
+}
+
+// GetByIdentifier retrieves a user by whichever identifier is populated. The identifier
+// must name exactly one kind, otherwise errs.ErrInvalidIdentifier is returned.
+func (r *UserRepository) GetByIdentifier(ctx context.Context, identifier dto.Identifier) (*domain.User, error) {
+	kind, err := identifier.Kind()
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case dto.IdentifierEmail:
+		return r.GetByEmail(ctx, *identifier.Email)
+	case dto.IdentifierPhone:
+		return r.GetByPhone(ctx, *identifier.CountryCode, *identifier.Phone)
+	case dto.IdentifierUsername:
+		return r.GetByUsername(ctx, *identifier.Username)
+	default:
+		return nil, errs.ErrInvalidIdentifier
+	}
+}
+
+// GetPasswordHash loads only the password hash of a user
+func (r *UserRepository) GetPasswordHash(ctx context.Context, id uuid.UUID) (domain.PasswordHash, error) {
+	query := `SELECT password_hash FROM users WHERE id = $1 AND tenant_id = $2`
+
+	var passwordHash string
+	var err error
+
+	// Check if we're in a transaction
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		// Use transaction
+		err = tx.GetContext(ctx, &passwordHash, query, id.String(), tenantID(ctx))
+	} else {
+		// Use main database connection
+		err = r.db.GetContext(ctx, &passwordHash, query, id.String(), tenantID(ctx))
 	}
 
-	// Use main database connection
-	err := r.db.GetContext(ctx, &user, query, countryCode, phone)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errs.ErrUserNotFound
+			return "", errs.ErrUserNotFound
 		}
+		return "", fmt.Errorf("failed to get password hash: %w", err)
+	}
 
-		return nil, fmt.Errorf("failed to get user by phone: %w", err)
+	return domain.PasswordHash(passwordHash), nil
+}
+
+// SetTOTPSecret stores a new encrypted TOTP secret and leaves TOTP disabled until the
+// enrollment is confirmed. Enabled users cannot be re-enrolled.
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, id uuid.UUID, encryptedSecret string) error {
+	query := `UPDATE users SET totp_secret = $1 WHERE id = $2 AND tenant_id = $3 AND totp_enabled = FALSE`
+	return r.execUserUpdate(ctx, query, "failed to set TOTP secret", encryptedSecret, id.String(), tenantID(ctx))
+}
+
+// EnableTOTP marks TOTP as enabled for a user with a pending secret
+func (r *UserRepository) EnableTOTP(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE users SET totp_enabled = TRUE WHERE id = $1 AND tenant_id = $2 AND totp_secret IS NOT NULL`
+	return r.execUserUpdate(ctx, query, "failed to enable TOTP", id.String(), tenantID(ctx))
+}
+
+// ConsumeTOTPStep records step as the last TOTP time step accepted from the user. It
+// reports false, without error, when a code of that step or a later one was already
+// accepted, so every code is usable only once.
+func (r *UserRepository) ConsumeTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	query := `UPDATE users SET totp_last_step = $1 WHERE id = $2 AND tenant_id = $3 AND (totp_last_step IS NULL OR totp_last_step < $1)`
+	err := r.execUserUpdate(ctx, query, "failed to record TOTP step", step, id.String(), tenantID(ctx))
+	if errors.Is(err, errs.ErrUserNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ListTOTPSecrets returns up to limit stored TOTP secrets of users whose ID sorts after
+// afterID, in ID order. Inside a transaction the rows stay locked until it ends.
+// All tenants are listed: mfa.encryption_key is shared by the whole process.
+func (r *UserRepository) ListTOTPSecrets(ctx context.Context, afterID uuid.UUID, limit int) ([]*domain.TOTPSecret, error) {
+	query := `
+		SELECT id, totp_secret
+		FROM users
+		WHERE totp_secret IS NOT NULL AND id > $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE
+	`
+
+	var rows []struct {
+		ID         uuid.UUID `db:"id"`
+		TOTPSecret string    `db:"totp_secret"`
+	}
+	var err error
+
+	// Check if we're in a transaction
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		// Use transaction
+		err = tx.SelectContext(ctx, &rows, query, afterID, limit)
+	} else {
+		// Use main database connection
+		err = r.db.SelectContext(ctx, &rows, query, afterID, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TOTP secrets: %w", err)
 	}
 
-	return user.ToDomain(), nil
+	secrets := make([]*domain.TOTPSecret, 0, len(rows))
+	for _, row := range rows {
+		secrets = append(secrets, &domain.TOTPSecret{UserID: row.ID, Ciphertext: row.TOTPSecret})
+	}
+	return secrets, nil
+}
+
+// ReplaceTOTPSecret swaps a user's encrypted TOTP secret for a re-encrypted one, returning
+// ErrUserNotFound when the stored secret is no longer the expected one. Like
+// ListTOTPSecrets it is not limited to the caller's tenant.
+func (r *UserRepository) ReplaceTOTPSecret(ctx context.Context, id uuid.UUID, currentSecret, newSecret string) error {
+	query := `UPDATE users SET totp_secret = $1 WHERE id = $2 AND totp_secret = $3`
+	return r.execUserUpdate(ctx, query, "failed to replace TOTP secret", newSecret, id.String(), currentSecret)
+}
+
+// UpdateContact stores the user's email, email verification and phone, returning
+// ErrContactInUse when another user of the tenant already has the email or phone
+func (r *UserRepository) UpdateContact(ctx context.Context, user *domain.User) error {
+	query := `
+		UPDATE users SET email = $1, email_verified = $2, country_code = $3, phone = $4, updated_at = $5
+		WHERE id = $6 AND tenant_id = $7
+	`
+	err := r.execUserUpdate(ctx, query, "failed to update contact",
+		user.Email, user.EmailVerified, user.CountryCode, user.Phone, user.UpdatedAt.Millis(), user.ID.String(), tenantID(ctx))
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return errs.ErrContactInUse
+	}
+	return err
+}
+
+// SetPrimaryContact stores the contact notifications and account recovery go to
+func (r *UserRepository) SetPrimaryContact(ctx context.Context, id uuid.UUID, method domain.ContactMethod) error {
+	query := `UPDATE users SET primary_contact = $1, updated_at = $2 WHERE id = $3 AND tenant_id = $4`
+	return r.execUserUpdate(ctx, query, "failed to set primary contact", method.String(), domain.Now().Millis(), id.String(), tenantID(ctx))
+}
+
+// execUserUpdate runs an update against a single user, returning ErrUserNotFound when no row matched
+func (r *UserRepository) execUserUpdate(ctx context.Context, query, errMessage string, args ...interface{}) error {
+	var result sql.Result
+	var err error
+
+	// Check if we're in a transaction
+	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
+		// Use transaction
+		result, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		// Use main database connection
+		result, err = r.db.ExecContext(ctx, query, args...)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", errMessage, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrUserNotFound
+	}
+
+	return nil
 }
 
 func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
+	query := `DELETE FROM users WHERE id = $1 AND tenant_id = $2`
 
 	var result sql.Result
 	var err error
@@ -212,10 +471,10 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	// Check if we're in a transaction
 	if tx, ok := ctx.Value(cx.TransactionContextKey).(*sqlx.Tx); ok {
 		// Use transaction
-		result, err = tx.ExecContext(ctx, query, id.String())
+		result, err = tx.ExecContext(ctx, query, id.String(), tenantID(ctx))
 	} else {
 		// Use main database connection
-		result, err = r.db.ExecContext(ctx, query, id.String())
+		result, err = r.db.ExecContext(ctx, query, id.String(), tenantID(ctx))
 	}
 
 	if err != nil {
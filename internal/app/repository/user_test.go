@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var userColumns = []string{
+	"id", "email", "username", "role", "country_code", "phone", "password_hash", "totp_secret", "totp_enabled",
+	"status", "email_verified", "must_change_password", "password_changed_at", "primary_contact",
+	"created_at", "updated_at",
+}
+
+func userRow(id uuid.UUID, email string) []driver.Value {
+	now := time.Now().UnixMilli()
+	return []driver.Value{
+		id.String(), email, "testuser", "user", nil, nil, "$2a$10$hash", nil, false,
+		"active", true, false, now, "email", now, now,
+	}
+}
+
+func TestUserRepository_GetByEmail(t *testing.T) {
+	store, fake := newFakeStore(t)
+	id := uuid.New()
+	fake.setRows(userColumns, userRow(id, "known@example.com"))
+	repo := NewUserRepository(store, true)
+
+	user, err := repo.GetByEmail(context.Background(), "known@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+	assert.Equal(t, "known@example.com", user.Email.String())
+	assert.Equal(t, "testuser", user.Username.String())
+	assert.True(t, user.EmailVerified)
+	assert.Equal(t, domain.AccountStatusActive, user.Status)
+	assert.Equal(t, domain.ContactMethodEmail, user.PrimaryContact)
+}
+
+func TestUserRepository_GetByEmailNotFound(t *testing.T) {
+	store, fake := newFakeStore(t)
+	fake.setRows(userColumns)
+	repo := NewUserRepository(store, true)
+
+	_, err := repo.GetByEmail(context.Background(), "missing@example.com")
+	assert.ErrorIs(t, err, errs.ErrUserNotFound)
+}
+
+func TestUserRepository_GetByEmailCorruptRow(t *testing.T) {
+	validRow := func() []driver.Value { return userRow(uuid.New(), "known@example.com") }
+
+	tests := []struct {
+		name  string
+		row   func() []driver.Value
+		cause string
+	}{
+		{
+			name:  "invalid id",
+			row:   func() []driver.Value { row := validRow(); row[0] = "not-a-uuid"; return row },
+			cause: "invalid id",
+		},
+		{
+			name:  "invalid username",
+			row:   func() []driver.Value { row := validRow(); row[2] = "bad name!"; return row },
+			cause: "invalid username",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fake := newFakeStore(t)
+			row := tt.row()
+			fake.setRows(userColumns, row)
+			repo := NewUserRepository(store, true)
+
+			user, err := repo.GetByEmail(context.Background(), "known@example.com")
+			require.Error(t, err)
+			assert.Nil(t, user)
+			assert.ErrorIs(t, err, errs.ErrCorruptRecord)
+			assert.Contains(t, err.Error(), tt.cause)
+
+			var corrupt *errs.CorruptRecordError
+			require.ErrorAs(t, err, &corrupt)
+			assert.Equal(t, "users", corrupt.Table)
+			assert.Equal(t, row[0], corrupt.RowID)
+
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, codes.Internal, st.Code())
+			assert.NotContains(t, st.Message(), corrupt.RowID, "row details are not sent to clients")
+		})
+	}
+}
+
+// uniqueTenantEmail behaves like PostgreSQL's unique index on tenant and email: the
+// first insert wins
+func uniqueTenantEmail() func(args []driver.Value) error {
+	var mu sync.Mutex
+	inserted := make(map[[2]driver.Value]bool)
+	return func(args []driver.Value) error {
+		mu.Lock()
+		defer mu.Unlock()
+		key := [2]driver.Value{args[1], args[2]}
+		if inserted[key] {
+			return &pq.Error{Code: uniqueViolationCode, Constraint: "idx_users_tenant_email_unique"}
+		}
+		inserted[key] = true
+		return nil
+	}
+}
+
+func TestUserRepository_GetByEmailScopedToTenant(t *testing.T) {
+	store, fake := newFakeStore(t)
+	fake.setRows(userColumns, userRow(uuid.New(), "known@example.com"))
+	repo := NewUserRepository(store, true)
+
+	_, err := repo.GetByEmail(context.Background(), "known@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []driver.Value{"known@example.com", ""}, fake.lastArgs, "without tenancy the default tenant is used")
+
+	_, err = repo.GetByEmail(cx.WithTenantID(context.Background(), "acme"), "known@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []driver.Value{"known@example.com", "acme"}, fake.lastArgs)
+}
+
+func TestUserRepository_ListTOTPSecretsAllTenants(t *testing.T) {
+	store, fake := newFakeStore(t)
+	id := uuid.New()
+	fake.setRows([]string{"id", "totp_secret"}, []driver.Value{id.String(), "ciphertext"})
+	repo := NewUserRepository(store, true)
+
+	secrets, err := repo.ListTOTPSecrets(cx.WithTenantID(context.Background(), "acme"), uuid.Nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.TOTPSecret{{UserID: id, Ciphertext: "ciphertext"}}, secrets)
+	assert.Equal(t, []driver.Value{uuid.Nil.String(), int64(10)}, fake.lastArgs, "the caller's tenant does not filter the listing")
+}
+
+func TestUserRepository_GetByUsernameKey(t *testing.T) {
+	store, fake := newFakeStore(t)
+	fake.setRows(userColumns, userRow(uuid.New(), "known@example.com"))
+	repo := NewUserRepository(store, true)
+
+	_, err := repo.GetByUsername(context.Background(), " TestUser ")
+	require.NoError(t, err)
+	assert.Equal(t, []driver.Value{"testuser", ""}, fake.lastArgs, "usernames are looked up by their key")
+}
+
+func TestUserRepository_CreateSameEmailInTwoTenants(t *testing.T) {
+	store, fake := newFakeStore(t)
+	fake.exec = uniqueTenantEmail()
+	repo := NewUserRepository(store, true)
+
+	for _, tenant := range []string{"acme", "globex"} {
+		user, err := domain.NewUser("shared@example.com", "$2a$10$hash", "shared", nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(cx.WithTenantID(context.Background(), tenant), user))
+	}
+
+	user, err := domain.NewUser("shared@example.com", "$2a$10$hash", "shared", nil, nil)
+	require.NoError(t, err)
+	err = repo.Create(cx.WithTenantID(context.Background(), "acme"), user)
+	assert.ErrorIs(t, err, errs.ErrUserExists)
+}
+
+func TestUserRepository_UpdateContactInUse(t *testing.T) {
+	store, fake := newFakeStore(t)
+	fake.exec = func([]driver.Value) error {
+		return &pq.Error{Code: uniqueViolationCode, Constraint: "idx_users_tenant_email_unique"}
+	}
+	repo := NewUserRepository(store, true)
+
+	user, err := domain.NewUser("taken@example.com", "$2a$10$hash", "taken", nil, nil)
+	require.NoError(t, err)
+	err = repo.UpdateContact(context.Background(), user)
+	assert.ErrorIs(t, err, errs.ErrContactInUse)
+}
+
+// benchmarkUserLookup runs lookup against a repository whose every query returns one user
+func TestUserRepository_CreateConcurrentSameEmail(t *testing.T) {
+	store, fake := newFakeStore(t)
+	fake.exec = uniqueTenantEmail()
+	repo := NewUserRepository(store, true)
+
+	const attempts = 2
+	results := make(chan error, attempts)
+	var start sync.WaitGroup
+	start.Add(1)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			user, err := domain.NewUser("race@example.com", "$2a$10$hash", "racer", nil, nil)
+			if err != nil {
+				results <- err
+				return
+			}
+			start.Wait()
+			results <- repo.Create(context.Background(), user)
+		}()
+	}
+	start.Done()
+
+	var succeeded, alreadyExists int
+	for i := 0; i < attempts; i++ {
+		err := <-results
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, errs.ErrUserExists):
+			alreadyExists++
+			assert.Equal(t, codes.AlreadyExists, status.Code(errs.ToGRPCError(err)))
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, alreadyExists)
+}
+
+func benchmarkUserLookup(b *testing.B, lookup func(ctx context.Context, repo *UserRepository) error) {
+	store, fake := newFakeStore(b)
+	fake.setRows(userColumns, userRow(uuid.New(), "known@example.com"))
+	repo := NewUserRepository(store, true)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := lookup(ctx, repo); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(fake.prepares.Load())/float64(b.N), "prepares/op")
+}
+
+func BenchmarkUserRepository_GetByEmail(b *testing.B) {
+	benchmarkUserLookup(b, func(ctx context.Context, repo *UserRepository) error {
+		_, err := repo.GetByEmail(ctx, "known@example.com")
+		return err
+	})
+}
+
+func BenchmarkUserRepository_GetByID(b *testing.B) {
+	id := uuid.New()
+	benchmarkUserLookup(b, func(ctx context.Context, repo *UserRepository) error {
+		_, err := repo.GetByID(ctx, id)
+		return err
+	})
+}
+
+func BenchmarkUserRepository_GetByPhone(b *testing.B) {
+	benchmarkUserLookup(b, func(ctx context.Context, repo *UserRepository) error {
+		_, err := repo.GetByPhone(ctx, "886", "912345678")
+		return err
+	})
+}
+
+func BenchmarkUser_ToDomain(b *testing.B) {
+	user := User{
+		ID:           uuid.New().String(),
+		Username:     "testuser",
+		Role:         "user",
+		PasswordHash: "$2a$10$hash",
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := user.ToDomain(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"wallet-user-svc/db"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type WebAuthnCredential struct {
+	ID             uuid.UUID `db:"id"`
+	UserID         uuid.UUID `db:"user_id"`
+	CredentialID   []byte    `db:"credential_id"`
+	PublicKey      []byte    `db:"public_key"`
+	SignCount      int64     `db:"sign_count"`
+	BackupEligible bool      `db:"backup_eligible"`
+	CreatedAt      int64     `db:"created_at"`
+	UpdatedAt      int64     `db:"updated_at"`
+}
+
+func (c *WebAuthnCredential) ToDomain() *domain.WebAuthnCredential {
+	return &domain.WebAuthnCredential{
+		ID:             c.ID,
+		UserID:         c.UserID,
+		CredentialID:   c.CredentialID,
+		PublicKey:      c.PublicKey,
+		SignCount:      uint32(c.SignCount),
+		BackupEligible: c.BackupEligible,
+		CreatedAt:      domain.Timestamp(c.CreatedAt),
+		UpdatedAt:      domain.Timestamp(c.UpdatedAt),
+	}
+}
+
+type WebAuthnCredentialRepository struct {
+	db db.Store
+}
+
+func NewWebAuthnCredentialRepository(db db.Store) *WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{
+		db: db,
+	}
+}
+
+// Create stores a newly registered credential. A credential ID that is already
+// registered is reported as ErrInvalidPasskey.
+func (r *WebAuthnCredentialRepository) Create(ctx context.Context, credential *domain.WebAuthnCredential) error {
+	query := `
+		INSERT INTO webauthn_credentials (id, user_id, credential_id, public_key, sign_count, backup_eligible, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, credential.ID, credential.UserID, credential.CredentialID, credential.PublicKey,
+		int64(credential.SignCount), credential.BackupEligible, credential.CreatedAt.Millis(), credential.UpdatedAt.Millis())
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+			return errs.ErrInvalidPasskey
+		}
+		return fmt.Errorf("failed to create WebAuthn credential: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUserID returns the credentials of a user, oldest first
+func (r *WebAuthnCredentialRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.WebAuthnCredential, error) {
+	query := `
+		SELECT id, user_id, credential_id, public_key, sign_count, backup_eligible, created_at, updated_at
+		FROM webauthn_credentials
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	var rows []WebAuthnCredential
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list WebAuthn credentials: %w", err)
+	}
+
+	credentials := make([]*domain.WebAuthnCredential, 0, len(rows))
+	for i := range rows {
+		credentials = append(credentials, rows[i].ToDomain())
+	}
+
+	return credentials, nil
+}
+
+// UpdateSignCount stores the signature counter an authenticator reported at sign-in
+func (r *WebAuthnCredentialRepository) UpdateSignCount(ctx context.Context, credentialID []byte, signCount uint32) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE webauthn_credentials SET sign_count = $1, updated_at = $2 WHERE credential_id = $3`,
+		int64(signCount), time.Now().UnixMilli(), credentialID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update WebAuthn credential sign count: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"wallet-user-svc/db"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+
+	"github.com/google/uuid"
+)
+
+type WebAuthnSession struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     *uuid.UUID `db:"user_id"`
+	Ceremony   string     `db:"ceremony"`
+	Data       []byte     `db:"data"`
+	ExpiresAt  int64      `db:"expires_at"`
+	ConsumedAt *int64     `db:"consumed_at"`
+	CreatedAt  int64      `db:"created_at"`
+}
+
+func (s *WebAuthnSession) ToDomain() *domain.WebAuthnSession {
+	session := &domain.WebAuthnSession{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Ceremony:  domain.WebAuthnCeremony(s.Ceremony),
+		Data:      s.Data,
+		ExpiresAt: domain.Timestamp(s.ExpiresAt),
+		CreatedAt: domain.Timestamp(s.CreatedAt),
+	}
+	if s.ConsumedAt != nil {
+		consumedAt := domain.Timestamp(*s.ConsumedAt)
+		session.ConsumedAt = &consumedAt
+	}
+	return session
+}
+
+type WebAuthnSessionRepository struct {
+	db db.Store
+}
+
+func NewWebAuthnSessionRepository(db db.Store) *WebAuthnSessionRepository {
+	return &WebAuthnSessionRepository{
+		db: db,
+	}
+}
+
+// Create stores a new session
+func (r *WebAuthnSessionRepository) Create(ctx context.Context, session *domain.WebAuthnSession) error {
+	query := `
+		INSERT INTO webauthn_sessions (id, user_id, ceremony, data, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, session.Ceremony.String(), session.Data,
+		session.ExpiresAt.Millis(), session.CreatedAt.Millis())
+	if err != nil {
+		return fmt.Errorf("failed to create WebAuthn session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a session by ID
+func (r *WebAuthnSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WebAuthnSession, error) {
+	query := `
+		SELECT id, user_id, ceremony, data, expires_at, consumed_at, created_at
+		FROM webauthn_sessions
+		WHERE id = $1
+	`
+
+	var session WebAuthnSession
+	if err := r.db.GetContext(ctx, &session, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrInvalidCeremony
+		}
+		return nil, fmt.Errorf("failed to get WebAuthn session: %w", err)
+	}
+
+	return session.ToDomain(), nil
+}
+
+// Consume marks the session as used. Only the first caller succeeds, so a challenge
+// can never be answered twice.
+func (r *WebAuthnSessionRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		`UPDATE webauthn_sessions SET consumed_at = $1 WHERE id = $2 AND consumed_at IS NULL`,
+		time.Now().UnixMilli(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to consume WebAuthn session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrInvalidCeremony
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/internal/app/model/events"
+	"wallet-user-svc/internal/app/repository"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+	"wallet-user-svc/pkg/utils/tx"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// AddContactMethod starts adding an email or phone to the authenticated user. The
+// contact is validated like at registration and must not belong to any user; a code is
+// sent to it and the user is only updated once ConfirmContactMethod receives that code.
+// A contact of the same method replaces the current one when confirmed.
+func (s *UserService) AddContactMethod(ctx context.Context, req dto.AddContactMethodReq) (*dto.AddContactMethodResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	user, err := s.authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Normalize(s.config.Phone.DefaultDialingCode)
+
+	code, err := domain.GenerateVerificationCode()
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate verification code")
+		return nil, err
+	}
+
+	verification, err := domain.NewContactVerification(
+		user.ID,
+		req.Email,
+		req.CountryCode,
+		req.Phone,
+		domain.HashVerificationCode(code),
+		s.config.Contact.VerificationTTL,
+	)
+	if err != nil {
+		logger.WithError(err).Warn("Invalid contact to add")
+		return nil, err
+	}
+
+	if err := s.checkContactAvailable(ctx, verification); err != nil {
+		if errors.Is(err, errs.ErrContactInUse) {
+			s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionAddContactMethod, UserID: user.ID.String(), Reason: "contact in use"})
+		}
+		return nil, err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
+		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
+
+		if err := s.contactVerificationRepo.Create(txCtx, verification); err != nil {
+			logger.WithError(err).Error("Failed to store contact verification")
+			return err
+		}
+
+		// Without the event the code never reaches the user, so unlike login
+		// notifications a failure to record it always fails the request
+		return s.createContactVerificationNotification(txCtx, user, verification, code, logger)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionAddContactMethod,
+		UserID:  user.ID.String(),
+		Success: true,
+		Fields:  logrus.Fields{"method": verification.Method.String()},
+	})
+	logger.WithField("method", verification.Method.String()).Info("Contact verification sent")
+
+	return &dto.AddContactMethodResp{
+		VerificationID: verification.ID.String(),
+		Method:         verification.Method,
+		ExpiresAt:      verification.ExpiresAt,
+	}, nil
+}
+
+// ConfirmContactMethod completes AddContactMethod with the code sent to the new contact
+// and stores it on the user. Every code presented counts against the verification's
+// attempt limit.
+func (s *UserService) ConfirmContactMethod(ctx context.Context, req dto.ConfirmContactMethodReq) (*dto.ContactResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	user, err := s.authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationID, err := uuid.Parse(req.VerificationID)
+	if err != nil {
+		return nil, errs.ErrInvalidVerification
+	}
+
+	verification, err := s.contactVerificationRepo.GetByID(ctx, verificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another user's verification is reported like a missing one so IDs cannot be probed
+	if verification.UserID != user.ID {
+		logger.WithField("verification_id", verificationID.String()).Warn("Contact verification of another user")
+		return nil, errs.ErrInvalidVerification
+	}
+
+	if err := verification.IsValid(); err != nil {
+		return nil, err
+	}
+
+	// The attempt is reserved before the code is checked, in one statement with the limit
+	// check, so concurrent guesses cannot exceed the limit. A failure to record it fails
+	// the request rather than allowing an uncounted guess.
+	if err := s.contactVerificationRepo.ReserveAttempt(ctx, verification.ID, domain.MaxContactVerificationAttempts, domain.Now()); err != nil {
+		logger.WithError(err).Warn("Failed to reserve contact verification attempt")
+		return nil, err
+	}
+
+	if domain.HashVerificationCode(req.Code) != verification.CodeHash {
+		s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionConfirmContactMethod, UserID: user.ID.String(), Reason: "invalid code"})
+		return nil, errs.ErrInvalidContactCode
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
+		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
+
+		if err := s.contactVerificationRepo.Consume(txCtx, verification.ID); err != nil {
+			return err
+		}
+
+		// The contact may have been taken since the code was sent; the unique indexes
+		// still catch a concurrent confirmation
+		if err := s.checkContactAvailable(txCtx, verification); err != nil {
+			return err
+		}
+
+		user.ApplyContactVerification(verification)
+		if err := s.userRepo.UpdateContact(txCtx, user); err != nil {
+			logger.WithError(err).Error("Failed to update user contact")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errs.ErrContactInUse) {
+			s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionConfirmContactMethod, UserID: user.ID.String(), Reason: "contact in use"})
+		}
+		return nil, err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionConfirmContactMethod,
+		UserID:  user.ID.String(),
+		Success: true,
+		Fields:  logrus.Fields{"method": verification.Method.String()},
+	})
+	logger.WithField("method", verification.Method.String()).Info("Contact method confirmed")
+
+	return &dto.ContactResp{User: user}, nil
+}
+
+// SetPrimaryContact makes the authenticated user's email or phone the contact
+// notifications and account recovery go to. The user must already have a contact of
+// that method.
+func (s *UserService) SetPrimaryContact(ctx context.Context, req dto.SetPrimaryContactReq) (*dto.ContactResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	user, err := s.authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := domain.NewContactMethod(req.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.SetPrimaryContact(method); err != nil {
+		logger.WithField("method", method.String()).Warn("Primary contact method is not set on the user")
+		return nil, err
+	}
+
+	if err := s.userRepo.SetPrimaryContact(ctx, user.ID, method); err != nil {
+		logger.WithError(err).Error("Failed to set primary contact")
+		return nil, err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionSetPrimaryContact,
+		UserID:  user.ID.String(),
+		Success: true,
+		Fields:  logrus.Fields{"method": method.String()},
+	})
+	logger.WithField("method", method.String()).Info("Primary contact updated")
+
+	return &dto.ContactResp{User: user}, nil
+}
+
+// checkContactAvailable returns ErrContactInUse when any user, including the one
+// verifying it, already has the contact of verification
+func (s *UserService) checkContactAvailable(ctx context.Context, verification *domain.ContactVerification) error {
+	var err error
+	switch verification.Method {
+	case domain.ContactMethodEmail:
+		_, err = s.userRepo.GetByEmail(ctx, verification.Email.String())
+	case domain.ContactMethodPhone:
+		_, err = s.userRepo.GetByPhone(ctx, string(*verification.CountryCode), verification.Phone.String())
+	}
+
+	switch {
+	case err == nil:
+		return errs.ErrContactInUse
+	case errors.Is(err, errs.ErrUserNotFound):
+		return nil
+	default:
+		logutils.GetLoggerOrDefault(ctx).WithError(err).Error("Failed to check contact availability")
+		return err
+	}
+}
+
+// createContactVerificationNotification records the event that sends code to the
+// contact of verification
+func (s *UserService) createContactVerificationNotification(
+	ctx context.Context,
+	user *domain.User,
+	verification *domain.ContactVerification,
+	code string,
+	logger *logrus.Entry,
+) error {
+	params := dto.SendContactVerificationParams{
+		UserID:         user.ID.String(),
+		Username:       user.Username.String(),
+		VerificationID: verification.ID.String(),
+		Code:           code,
+		ExpiresAt:      verification.ExpiresAt.Time(),
+	}
+	eventType := events.EmailVerificationEventType
+	if verification.Method == domain.ContactMethodPhone {
+		eventType = events.PhoneVerificationEventType
+		params.CountryCode = verification.CountryCode.ToPtrString()
+		params.Phone = verification.Phone.ToPtrString()
+	} else {
+		params.Email = verification.Email.ToPtrString()
+	}
+
+	payload, err := json.Marshal(params)
+	if err != nil {
+		logger.WithError(err).Error("Failed to marshal contact verification payload")
+		return err
+	}
+
+	if err := s.notificationEventLogRepo.Create(ctx, &repository.NotificationEventLog{
+		ID:        uuid.New().String(),
+		EventName: string(eventType),
+		Payload:   payload,
+		Status:    repository.NotificationEventLogStatusPending,
+	}); err != nil {
+		logger.WithError(err).Error("Failed to create contact verification event log")
+		return err
+	}
+
+	return nil
+}
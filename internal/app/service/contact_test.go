@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/internal/app/model/events"
+	"wallet-user-svc/internal/app/repository"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryContactVerificationRepository keeps contact verifications in memory
+type memoryContactVerificationRepository struct {
+	verifications map[uuid.UUID]*domain.ContactVerification
+	// reserveErr, when set, fails every attempt reservation
+	reserveErr error
+}
+
+func (r *memoryContactVerificationRepository) Create(_ context.Context, verification *domain.ContactVerification) error {
+	if r.verifications == nil {
+		r.verifications = make(map[uuid.UUID]*domain.ContactVerification)
+	}
+	r.verifications[verification.ID] = verification
+	return nil
+}
+
+func (r *memoryContactVerificationRepository) GetByID(_ context.Context, id uuid.UUID) (*domain.ContactVerification, error) {
+	verification, ok := r.verifications[id]
+	if !ok {
+		return nil, errs.ErrInvalidVerification
+	}
+	return verification, nil
+}
+
+func (r *memoryContactVerificationRepository) ReserveAttempt(_ context.Context, id uuid.UUID, maxAttempts int, now domain.Timestamp) error {
+	if r.reserveErr != nil {
+		return r.reserveErr
+	}
+	verification, ok := r.verifications[id]
+	if !ok || verification.ConsumedAt != nil || verification.Attempts >= maxAttempts || verification.ExpiresAt <= now {
+		return errs.ErrInvalidVerification
+	}
+	verification.Attempts++
+	return nil
+}
+
+func (r *memoryContactVerificationRepository) Consume(_ context.Context, id uuid.UUID) error {
+	verification := r.verifications[id]
+	if verification.ConsumedAt != nil {
+		return errs.ErrInvalidVerification
+	}
+	now := domain.Now()
+	verification.ConsumedAt = &now
+	return nil
+}
+
+// recordingNotificationEventLogRepository keeps the notification events created
+type recordingNotificationEventLogRepository struct {
+	events []*repository.NotificationEventLog
+}
+
+func (r *recordingNotificationEventLogRepository) Create(_ context.Context, event *repository.NotificationEventLog) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func newContactTestService(t *testing.T) (*UserService, *stubUserRepository, *recordingNotificationEventLogRepository, context.Context) {
+	t.Helper()
+	service, userRepo := newRegisterTestService(false)
+	service.config.Contact.VerificationTTL = 10 * time.Minute
+	service.contactVerificationRepo = &memoryContactVerificationRepository{}
+	eventLogRepo := &recordingNotificationEventLogRepository{}
+	service.notificationEventLogRepo = eventLogRepo
+	service.auditLogger = &recordingAuditLogger{}
+
+	resp, err := service.Register(context.Background(), dto.RegisterReq{
+		Username:    "phoneuser",
+		Password:    "Password123!",
+		CountryCode: stringPtr("TW"),
+		Phone:       stringPtr("+886912345678"),
+	})
+	require.NoError(t, err)
+	userRepo.usersByPhone = map[string]*domain.User{"TW+886912345678": resp.User}
+
+	return service, userRepo, eventLogRepo, cx.WithAuthUserID(context.Background(), resp.User.ID)
+}
+
+// sentCode returns the verification code carried by the last notification event
+func sentCode(t *testing.T, eventLogRepo *recordingNotificationEventLogRepository) string {
+	t.Helper()
+	require.NotEmpty(t, eventLogRepo.events)
+	var params dto.SendContactVerificationParams
+	require.NoError(t, json.Unmarshal(eventLogRepo.events[len(eventLogRepo.events)-1].Payload, &params))
+	return params.Code
+}
+
+func TestContactMethod_AddEmailAndMakePrimary(t *testing.T) {
+	service, userRepo, eventLogRepo, ctx := newContactTestService(t)
+	userID, _ := cx.GetAuthUserID(ctx)
+
+	resp, err := service.SetPrimaryContact(ctx, dto.SetPrimaryContactReq{Method: "email"})
+	assert.ErrorIs(t, err, errs.ErrContactMethodMissing, "an unverified email cannot become the primary contact")
+	assert.Nil(t, resp)
+
+	added, err := service.AddContactMethod(ctx, dto.AddContactMethodReq{Email: "new@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ContactMethodEmail, added.Method)
+	require.Len(t, eventLogRepo.events, 1)
+	assert.Equal(t, string(events.EmailVerificationEventType), eventLogRepo.events[0].EventName)
+	assert.Nil(t, userRepo.usersByID[userID].Email, "the email is only stored once confirmed")
+
+	_, err = service.ConfirmContactMethod(ctx, dto.ConfirmContactMethodReq{VerificationID: added.VerificationID, Code: "not-the-code"})
+	assert.ErrorIs(t, err, errs.ErrInvalidContactCode)
+
+	confirmed, err := service.ConfirmContactMethod(ctx, dto.ConfirmContactMethodReq{VerificationID: added.VerificationID, Code: sentCode(t, eventLogRepo)})
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", confirmed.User.Email.String())
+	assert.True(t, confirmed.User.EmailVerified)
+	assert.Equal(t, "+886912345678", confirmed.User.Phone.String(), "the phone is kept")
+
+	_, err = service.ConfirmContactMethod(ctx, dto.ConfirmContactMethodReq{VerificationID: added.VerificationID, Code: sentCode(t, eventLogRepo)})
+	assert.ErrorIs(t, err, errs.ErrInvalidVerification, "a verification is used once")
+
+	resp, err = service.SetPrimaryContact(ctx, dto.SetPrimaryContactReq{Method: "email"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ContactMethodEmail, resp.User.PrimaryContact)
+
+	auditLogger := service.auditLogger.(*recordingAuditLogger)
+	last := auditLogger.events[len(auditLogger.events)-1]
+	assert.Equal(t, audit.ActionSetPrimaryContact, last.Action)
+	assert.True(t, last.Success)
+}
+
+func TestContactMethod_AddRejected(t *testing.T) {
+	service, userRepo, _, ctx := newContactTestService(t)
+
+	other, err := domain.NewUser("taken@example.com", "$2a$10$hash", "other", nil, nil)
+	require.NoError(t, err)
+	userRepo.usersByEmail = map[string]*domain.User{"taken@example.com": other}
+
+	tests := []struct {
+		name string
+		req  dto.AddContactMethodReq
+		err  error
+	}{
+		{name: "email of another user", req: dto.AddContactMethodReq{Email: "taken@example.com"}, err: errs.ErrContactInUse},
+		{name: "own phone", req: dto.AddContactMethodReq{CountryCode: stringPtr("TW"), Phone: stringPtr("+886912345678")}, err: errs.ErrContactInUse},
+		{name: "no contact", req: dto.AddContactMethodReq{}, err: errs.ErrInvalidContact},
+		{name: "email and phone", req: dto.AddContactMethodReq{Email: "new@example.com", CountryCode: stringPtr("TW"), Phone: stringPtr("+886912345679")}, err: errs.ErrInvalidContact},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AddContactMethod(ctx, tt.req)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	_, err = service.AddContactMethod(context.Background(), dto.AddContactMethodReq{Email: "new@example.com"})
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+}
+
+func TestContactMethod_ConfirmRejected(t *testing.T) {
+	service, userRepo, eventLogRepo, ctx := newContactTestService(t)
+
+	added, err := service.AddContactMethod(ctx, dto.AddContactMethodReq{Email: "new@example.com"})
+	require.NoError(t, err)
+	code := sentCode(t, eventLogRepo)
+
+	other, err := domain.NewUser("other@example.com", "$2a$10$hash", "other", nil, nil)
+	require.NoError(t, err)
+	userRepo.usersByID[other.ID] = other
+	_, err = service.ConfirmContactMethod(cx.WithAuthUserID(context.Background(), other.ID), dto.ConfirmContactMethodReq{VerificationID: added.VerificationID, Code: code})
+	assert.ErrorIs(t, err, errs.ErrInvalidVerification, "another user's verification cannot be confirmed")
+
+	userRepo.usersByEmail = map[string]*domain.User{"new@example.com": other}
+	_, err = service.ConfirmContactMethod(ctx, dto.ConfirmContactMethodReq{VerificationID: added.VerificationID, Code: code})
+	assert.ErrorIs(t, err, errs.ErrContactInUse, "a contact taken after the code was sent is rejected")
+
+	delete(userRepo.usersByEmail, "new@example.com")
+	added, err = service.AddContactMethod(ctx, dto.AddContactMethodReq{Email: "new@example.com"})
+	require.NoError(t, err)
+	for range domain.MaxContactVerificationAttempts {
+		_, err = service.ConfirmContactMethod(ctx, dto.ConfirmContactMethodReq{VerificationID: added.VerificationID, Code: "000000x"})
+		require.ErrorIs(t, err, errs.ErrInvalidContactCode)
+	}
+	_, err = service.ConfirmContactMethod(ctx, dto.ConfirmContactMethodReq{VerificationID: added.VerificationID, Code: sentCode(t, eventLogRepo)})
+	assert.ErrorIs(t, err, errs.ErrInvalidVerification, "the right code is refused once the attempts are used up")
+}
+
+func TestContactMethod_ConfirmFailsClosed(t *testing.T) {
+	service, userRepo, eventLogRepo, ctx := newContactTestService(t)
+
+	added, err := service.AddContactMethod(ctx, dto.AddContactMethodReq{Email: "new@example.com"})
+	require.NoError(t, err)
+	code := sentCode(t, eventLogRepo)
+
+	verifications := service.contactVerificationRepo.(*memoryContactVerificationRepository)
+	verifications.reserveErr = errors.New("database unavailable")
+	_, err = service.ConfirmContactMethod(ctx, dto.ConfirmContactMethodReq{VerificationID: added.VerificationID, Code: code})
+	assert.Error(t, err, "a code is not checked when its attempt cannot be recorded")
+	userID, _ := cx.GetAuthUserID(ctx)
+	assert.Nil(t, userRepo.usersByID[userID].Email)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"wallet-user-svc/internal/app/config"
+	"wallet-user-svc/internal/app/model/dto"
+	logutils "wallet-user-svc/pkg/utils/log"
+	"wallet-user-svc/pkg/version"
+)
+
+type DBStatsProvider interface {
+	Stats() sql.DBStats
+}
+
+type NotificationStatsProvider interface {
+	NotificationStats() dto.NotificationStats
+}
+
+// DiagnosticsService exposes non-sensitive runtime information for operators
+type DiagnosticsService struct {
+	config        *config.Config
+	db            DBStatsProvider
+	notifications NotificationStatsProvider
+	startedAt     time.Time
+}
+
+// NewDiagnosticsService creates a new DiagnosticsService instance
+func NewDiagnosticsService(config *config.Config, db DBStatsProvider, notifications NotificationStatsProvider) *DiagnosticsService {
+	return &DiagnosticsService{
+		config:        config,
+		db:            db,
+		notifications: notifications,
+		startedAt:     time.Now(),
+	}
+}
+
+// GetRuntimeInfo returns build info, the redacted effective configuration,
+// database pool and notification stats and uptime of the running instance
+func (s *DiagnosticsService) GetRuntimeInfo(ctx context.Context) (*dto.RuntimeInfoResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	logger.Debug("Collecting runtime info")
+
+	stats := s.db.Stats()
+
+	return &dto.RuntimeInfoResp{
+		Build:  readBuildInfo(),
+		Config: s.config.Redacted().Flatten(),
+		DatabasePool: dto.DatabasePoolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDuration:       stats.WaitDuration,
+		},
+		Notifications: s.notifications.NotificationStats(),
+		StartedAt:     s.startedAt,
+		Uptime:        time.Since(s.startedAt),
+	}, nil
+}
+
+// readBuildInfo returns the version, commit and build time injected at build time,
+// falling back to the module version and VCS details embedded by the Go toolchain
+func readBuildInfo() dto.BuildInfo {
+	info := version.Get()
+	return dto.BuildInfo{
+		Version:   info.Version,
+		GoVersion: info.GoVersion,
+		Revision:  info.Commit,
+		BuildTime: info.BuildTime,
+	}
+}
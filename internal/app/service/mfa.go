@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+	"wallet-user-svc/pkg/utils/tx"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/sirupsen/logrus"
+)
+
+// totpPeriod is the lifetime in seconds of a TOTP code, the default of authenticator apps
+const totpPeriod = 30
+
+// EnrollTOTP starts two-factor enrollment for the authenticated user. It stores a new
+// encrypted secret and returns it with the otpauth URI; TOTP stays disabled until the
+// user proves possession of the secret with VerifyTOTP.
+func (s *UserService) EnrollTOTP(ctx context.Context) (*dto.EnrollTOTPResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	user, err := s.authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		logger.Warn("TOTP enrollment requested but already enabled")
+		return nil, errs.ErrTOTPAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.config.MFA.Issuer,
+		AccountName: totpAccountName(user),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate TOTP secret")
+		return nil, err
+	}
+
+	encryptedSecret, err := s.secretCipher.Encrypt(key.Secret())
+	if err != nil {
+		logger.WithError(err).Error("Failed to encrypt TOTP secret")
+		return nil, err
+	}
+
+	backupCodes, err := domain.GenerateBackupCodes(s.config.MFA.BackupCodeCount)
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate backup codes")
+		return nil, err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
+		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
+
+		if err := s.userRepo.SetTOTPSecret(txCtx, user.ID, encryptedSecret); err != nil {
+			logger.WithError(err).Error("Failed to store TOTP secret")
+			return err
+		}
+
+		if err := s.mfaBackupCodeRepo.Replace(txCtx, user.ID, hashBackupCodes(backupCodes)); err != nil {
+			logger.WithError(err).Error("Failed to store backup codes")
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionEnrollTOTP, UserID: user.ID.String(), Success: true})
+	logger.Info("TOTP enrollment started")
+
+	return &dto.EnrollTOTPResp{
+		Secret:      key.Secret(),
+		URI:         key.URL(),
+		BackupCodes: backupCodes,
+	}, nil
+}
+
+// VerifyTOTP confirms enrollment with a code from the authenticator app and enables
+// two-factor authentication for the authenticated user
+func (s *UserService) VerifyTOTP(ctx context.Context, req dto.VerifyTOTPReq) error {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	user, err := s.authenticatedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if user.TOTPEnabled {
+		return errs.ErrTOTPAlreadyEnabled
+	}
+
+	if user.TOTPSecret == nil {
+		logger.Warn("TOTP verification requested before enrollment")
+		return errs.ErrTOTPNotEnrolled
+	}
+
+	valid, err := s.acceptTOTPCode(ctx, user, req.Code)
+	if err != nil {
+		logger.WithError(err).Error("Failed to validate TOTP code")
+		return err
+	}
+	if !valid {
+		s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionVerifyTOTP, UserID: user.ID.String(), Reason: "invalid code"})
+		return errs.ErrInvalidTOTPCode
+	}
+
+	if err := s.userRepo.EnableTOTP(ctx, user.ID); err != nil {
+		logger.WithError(err).Error("Failed to enable TOTP")
+		return err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionVerifyTOTP, UserID: user.ID.String(), Success: true})
+	logger.Info("TOTP enabled")
+
+	return nil
+}
+
+// CompleteLogin finishes a login that returned an MFA challenge. Tokens are issued only
+// for a valid TOTP code that was not used before; every code presented counts against
+// the challenge's attempt limit.
+func (s *UserService) CompleteLogin(ctx context.Context, req dto.CompleteLoginReq) (*dto.LoginResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	challengeID, err := uuid.Parse(req.ChallengeID)
+	if err != nil {
+		return nil, errs.ErrInvalidMFAChallenge
+	}
+
+	challenge, err := s.mfaChallengeRepo.GetByID(ctx, challengeID)
+	if err != nil {
+		logger.WithError(err).WithField("challenge_id", req.ChallengeID).Warn("Failed to retrieve MFA challenge")
+		return nil, err
+	}
+
+	logger = logger.WithFields(logrus.Fields{
+		"challenge_id": challenge.ID.String(),
+		"user_id":      challenge.UserID.String(),
+	})
+
+	if err := challenge.IsValid(); err != nil {
+		logger.Warn("MFA challenge is no longer valid")
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to retrieve user for MFA challenge")
+		return nil, err
+	}
+
+	// The account may have been disabled or locked since the password was checked
+	if ok, err := user.CanLogin(); !ok {
+		logger.WithError(err).WithField("user_id", user.ID.String()).Warn("Account is not allowed to sign in")
+		return nil, err
+	}
+
+	// The attempt is reserved before the code is checked, in one statement with the limit
+	// check, so concurrent guesses cannot exceed the limit
+	if err := s.mfaChallengeRepo.ReserveAttempt(ctx, challenge.ID, domain.MaxMFAChallengeAttempts, domain.Now()); err != nil {
+		logger.WithError(err).Warn("Failed to reserve MFA challenge attempt")
+		return nil, err
+	}
+
+	usingBackupCode := req.BackupCode != ""
+
+	var valid bool
+	if usingBackupCode {
+		valid, err = s.mfaBackupCodeRepo.Consume(ctx, user.ID, domain.HashBackupCode(req.BackupCode))
+	} else {
+		valid, err = s.acceptTOTPCode(ctx, user, req.Code)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to validate second factor")
+		return nil, err
+	}
+	if !valid {
+		s.auditLogger.Log(ctx, audit.Event{
+			Action: audit.ActionCompleteLogin,
+			UserID: user.ID.String(),
+			Reason: "invalid code",
+			Fields: logrus.Fields{"attempts": challenge.Attempts + 1, "backup_code": usingBackupCode},
+		})
+		if usingBackupCode {
+			return nil, errs.ErrInvalidBackupCode
+		}
+		return nil, errs.ErrInvalidTOTPCode
+	}
+
+	if err := s.mfaChallengeRepo.Consume(ctx, challenge.ID); err != nil {
+		logger.WithError(err).Warn("Failed to consume MFA challenge")
+		return nil, err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionCompleteLogin,
+		UserID:  user.ID.String(),
+		Success: true,
+		Fields:  logrus.Fields{"backup_code": usingBackupCode},
+	})
+
+	resp, err := s.issueLoginTokens(ctx, user, req.Device, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if usingBackupCode {
+		remaining, err := s.mfaBackupCodeRepo.CountRemaining(ctx, user.ID)
+		if err != nil {
+			logger.WithError(err).Error("Failed to count remaining backup codes")
+			return nil, err
+		}
+		resp.BackupCodesRemaining = &remaining
+		resp.BackupCodesLow = remaining <= s.config.MFA.BackupCodeLowThreshold
+		logger.WithField("remaining", remaining).Info("Login completed with a backup code")
+	}
+
+	return resp, nil
+}
+
+// RegenerateBackupCodes replaces all backup codes of the authenticated user. A current
+// TOTP code is required so a stolen access token alone cannot mint recovery codes.
+func (s *UserService) RegenerateBackupCodes(ctx context.Context, req dto.RegenerateBackupCodesReq) (*dto.RegenerateBackupCodesResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	user, err := s.authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TOTPEnabled {
+		return nil, errs.ErrTOTPNotEnrolled
+	}
+
+	valid, err := s.acceptTOTPCode(ctx, user, req.Code)
+	if err != nil {
+		logger.WithError(err).Error("Failed to validate TOTP code")
+		return nil, err
+	}
+	if !valid {
+		s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionRegenerateBackupCodes, UserID: user.ID.String(), Reason: "invalid code"})
+		return nil, errs.ErrInvalidTOTPCode
+	}
+
+	backupCodes, err := domain.GenerateBackupCodes(s.config.MFA.BackupCodeCount)
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate backup codes")
+		return nil, err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
+		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
+		return s.mfaBackupCodeRepo.Replace(txCtx, user.ID, hashBackupCodes(backupCodes))
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to replace backup codes")
+		return nil, err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionRegenerateBackupCodes, UserID: user.ID.String(), Success: true})
+	logger.Info("Backup codes regenerated")
+
+	return &dto.RegenerateBackupCodesResp{BackupCodes: backupCodes}, nil
+}
+
+// createMFAChallenge stores a challenge for a user whose password was verified
+func (s *UserService) createMFAChallenge(ctx context.Context, user *domain.User, logger *logrus.Entry) (*dto.LoginResp, error) {
+	challenge, err := domain.NewMFAChallenge(user.ID, s.config.MFA.ChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaChallengeRepo.Create(ctx, challenge); err != nil {
+		logger.WithError(err).Error("Failed to store MFA challenge")
+		return nil, err
+	}
+
+	logger.WithField("user_id", user.ID.String()).Info("Password verified, MFA challenge issued")
+
+	return &dto.LoginResp{
+		MFARequired: true,
+		ChallengeID: challenge.ID.String(),
+	}, nil
+}
+
+// authenticatedUser loads the caller identified by the auth interceptor
+func (s *UserService) authenticatedUser(ctx context.Context) (*domain.User, error) {
+	userID, ok := cx.GetAuthUserID(ctx)
+	if !ok {
+		return nil, errs.ErrUnauthenticated
+	}
+
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+// acceptTOTPCode checks code against the user's secret and records the time step it
+// matched, so a code accepted once, by any challenge or by VerifyTOTP, is rejected after
+func (s *UserService) acceptTOTPCode(ctx context.Context, user *domain.User, code string) (bool, error) {
+	step, valid, err := s.validateTOTPCode(user, code, time.Now())
+	if err != nil || !valid {
+		return false, err
+	}
+
+	return s.userRepo.ConsumeTOTPStep(ctx, user.ID, step)
+}
+
+// validateTOTPCode decrypts the user's secret and checks the code against it, allowing
+// one step of clock skew either way as totp.Validate does. It returns the time step the
+// code belongs to.
+func (s *UserService) validateTOTPCode(user *domain.User, code string, now time.Time) (int64, bool, error) {
+	if user.TOTPSecret == nil {
+		return 0, false, errs.ErrTOTPNotEnrolled
+	}
+
+	secret, err := s.secretCipher.Decrypt(*user.TOTPSecret)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, skew := range []int64{0, -1, 1} {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		// A code of the wrong length is an error here; it is just an invalid code
+		valid, _ := totp.ValidateCustom(code, secret, at, totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if valid {
+			return at.Unix() / totpPeriod, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// hashBackupCodes returns the hashes stored for the given backup codes
+func hashBackupCodes(codes []string) []string {
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, domain.HashBackupCode(code))
+	}
+	return hashes
+}
+
+// totpAccountName labels the account in authenticator apps
+func totpAccountName(user *domain.User) string {
+	if user.Email != nil {
+		return user.Email.String()
+	}
+	return user.Username.String()
+}
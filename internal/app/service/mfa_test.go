@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/config"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/internal/app/repository"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/crypt/secret"
+	"wallet-user-svc/pkg/utils/crypt/token"
+	"wallet-user-svc/pkg/utils/cx"
+	"wallet-user-svc/pkg/utils/tx"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubMFAChallengeRepository struct {
+	MFAChallengeRepository
+	challenges map[uuid.UUID]*domain.MFAChallenge
+}
+
+func (r *stubMFAChallengeRepository) GetByID(_ context.Context, id uuid.UUID) (*domain.MFAChallenge, error) {
+	challenge, ok := r.challenges[id]
+	if !ok {
+		return nil, errs.ErrInvalidMFAChallenge
+	}
+	return challenge, nil
+}
+
+func (r *stubMFAChallengeRepository) Create(_ context.Context, challenge *domain.MFAChallenge) error {
+	r.challenges[challenge.ID] = challenge
+	return nil
+}
+
+func (r *stubMFAChallengeRepository) Consume(_ context.Context, id uuid.UUID) error {
+	challenge := r.challenges[id]
+	if challenge.ConsumedAt != nil {
+		return errs.ErrInvalidMFAChallenge
+	}
+	now := domain.Now()
+	challenge.ConsumedAt = &now
+	return nil
+}
+
+func (r *stubMFAChallengeRepository) ReserveAttempt(_ context.Context, id uuid.UUID, maxAttempts int, now domain.Timestamp) error {
+	challenge, ok := r.challenges[id]
+	if !ok || challenge.ConsumedAt != nil || challenge.Attempts >= maxAttempts || challenge.ExpiresAt <= now {
+		return errs.ErrInvalidMFAChallenge
+	}
+	challenge.Attempts++
+	return nil
+}
+
+type stubMFABackupCodeRepository struct {
+	MFABackupCodeRepository
+	unused map[string]bool
+}
+
+func (r *stubMFABackupCodeRepository) Consume(_ context.Context, _ uuid.UUID, codeHash string) (bool, error) {
+	if !r.unused[codeHash] {
+		return false, nil
+	}
+	delete(r.unused, codeHash)
+	return true, nil
+}
+
+func (r *stubMFABackupCodeRepository) CountRemaining(context.Context, uuid.UUID) (int, error) {
+	return len(r.unused), nil
+}
+
+type stubTxManager struct {
+	TxManager
+}
+
+func (stubTxManager) WithTransaction(_ context.Context, fn func(*tx.TxWrapper) error) error {
+	return fn(tx.NewTxWrapper(nil))
+}
+
+type stubRefreshTokenRepository struct {
+	RefreshTokenRepository
+}
+
+func (stubRefreshTokenRepository) Create(context.Context, *domain.RefreshToken) error {
+	return nil
+}
+
+type stubNotificationEventLogRepository struct{}
+
+func (stubNotificationEventLogRepository) Create(context.Context, *repository.NotificationEventLog) error {
+	return nil
+}
+
+type nopAuditLogger struct{}
+
+func (nopAuditLogger) Log(context.Context, audit.Event) {}
+
+// newMFATestService returns a service with a user holding an encrypted TOTP secret
+func newMFATestService(t *testing.T, enabled bool) (*UserService, *domain.User, string, *stubMFAChallengeRepository) {
+	t.Helper()
+
+	cipher, err := secret.NewCipher("test-encryption-key")
+	require.NoError(t, err)
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "testuser"})
+	require.NoError(t, err)
+	encryptedSecret, err := cipher.Encrypt(key.Secret())
+	require.NoError(t, err)
+
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+	user.TOTPSecret = &encryptedSecret
+	user.TOTPEnabled = enabled
+
+	challenges := &stubMFAChallengeRepository{challenges: make(map[uuid.UUID]*domain.MFAChallenge)}
+	service := &UserService{
+		config:           &config.Config{MFA: config.MFAConfig{Issuer: "test", ChallengeTTL: time.Minute}},
+		userRepo:         &stubUserRepository{usersByID: map[uuid.UUID]*domain.User{user.ID: user}},
+		auditLogger:      nopAuditLogger{},
+		mfaChallengeRepo: challenges,
+		secretCipher:     cipher,
+	}
+
+	return service, user, key.Secret(), challenges
+}
+
+func TestVerifyTOTP_EnablesWithValidCode(t *testing.T) {
+	service, user, totpSecret, _ := newMFATestService(t, false)
+	ctx := cx.WithAuthUserID(context.Background(), user.ID)
+
+	code, err := totp.GenerateCode(totpSecret, time.Now())
+	require.NoError(t, err)
+	wrongCode := "000000"
+	if wrongCode == code {
+		wrongCode = "111111"
+	}
+
+	assert.ErrorIs(t, service.VerifyTOTP(ctx, dto.VerifyTOTPReq{Code: wrongCode}), errs.ErrInvalidTOTPCode)
+	assert.False(t, user.TOTPEnabled)
+
+	require.NoError(t, service.VerifyTOTP(ctx, dto.VerifyTOTPReq{Code: code}))
+	assert.True(t, user.TOTPEnabled)
+
+	assert.ErrorIs(t, service.VerifyTOTP(ctx, dto.VerifyTOTPReq{Code: code}), errs.ErrTOTPAlreadyEnabled)
+}
+
+func TestVerifyTOTP_RequiresAuthentication(t *testing.T) {
+	service, _, _, _ := newMFATestService(t, false)
+
+	err := service.VerifyTOTP(context.Background(), dto.VerifyTOTPReq{Code: "123456"})
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+}
+
+func TestCompleteLogin_InvalidCodeCountsAttempts(t *testing.T) {
+	service, user, totpSecret, challenges := newMFATestService(t, true)
+
+	challenge, err := domain.NewMFAChallenge(user.ID, time.Minute)
+	require.NoError(t, err)
+	challenges.challenges[challenge.ID] = challenge
+
+	validCode, err := totp.GenerateCode(totpSecret, time.Now())
+	require.NoError(t, err)
+	wrongCode := "000000"
+	if wrongCode == validCode {
+		wrongCode = "111111"
+	}
+
+	for i := 0; i < domain.MaxMFAChallengeAttempts; i++ {
+		_, err := service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: challenge.ID.String(), Code: wrongCode})
+		assert.ErrorIs(t, err, errs.ErrInvalidTOTPCode)
+	}
+
+	_, err = service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: challenge.ID.String(), Code: validCode})
+	assert.ErrorIs(t, err, errs.ErrInvalidMFAChallenge, "challenge must be rejected once attempts are exhausted")
+}
+
+func TestCompleteLogin_ReservesAttemptBeforeCheckingCode(t *testing.T) {
+	service, user, totpSecret, challenges := newMFATestService(t, true)
+
+	challenge, err := domain.NewMFAChallenge(user.ID, time.Minute)
+	require.NoError(t, err)
+	challenge.Attempts = domain.MaxMFAChallengeAttempts - 1
+	challenges.challenges[challenge.ID] = challenge
+
+	validCode, err := totp.GenerateCode(totpSecret, time.Now())
+	require.NoError(t, err)
+	wrongCode := "000000"
+	if wrongCode == validCode {
+		wrongCode = "111111"
+	}
+
+	// The last attempt is taken by the wrong code, so the valid one is never checked
+	_, err = service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: challenge.ID.String(), Code: wrongCode})
+	assert.ErrorIs(t, err, errs.ErrInvalidTOTPCode)
+	_, err = service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: challenge.ID.String(), Code: validCode})
+	assert.ErrorIs(t, err, errs.ErrInvalidMFAChallenge)
+	assert.Equal(t, domain.MaxMFAChallengeAttempts, challenge.Attempts)
+}
+
+func TestTOTPCode_UsableOnce(t *testing.T) {
+	service, user, totpSecret, challenges := newMFATestService(t, false)
+	service.config.JWT = config.JWTConfig{AccessTokenDuration: time.Minute, RefreshTokenDuration: time.Hour}
+	service.txManager = stubTxManager{}
+	service.tokenMaker = token.NewJWTTokenMaker("test-secret-key-with-at-least-32-chars", 0)
+	service.refreshTokenRepo = stubRefreshTokenRepository{}
+	service.notificationEventLogRepo = stubNotificationEventLogRepository{}
+	service.notificationPrefRepo = &memoryNotificationPreferenceRepository{}
+
+	newChallenge := func() string {
+		challenge, err := domain.NewMFAChallenge(user.ID, time.Minute)
+		require.NoError(t, err)
+		challenges.challenges[challenge.ID] = challenge
+		return challenge.ID.String()
+	}
+
+	now := time.Now()
+	code, err := totp.GenerateCode(totpSecret, now)
+	require.NoError(t, err)
+	require.NoError(t, service.VerifyTOTP(cx.WithAuthUserID(context.Background(), user.ID), dto.VerifyTOTPReq{Code: code}))
+
+	_, err = service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: newChallenge(), Code: code})
+	assert.ErrorIs(t, err, errs.ErrInvalidTOTPCode, "the code that enabled TOTP must not also sign in")
+
+	// A code of the next step is accepted once, and the previous step's code no longer is
+	nextCode, err := totp.GenerateCode(totpSecret, now.Add(totpPeriod*time.Second))
+	require.NoError(t, err)
+	if nextCode == code {
+		t.Skip("consecutive steps produced the same code")
+	}
+	resp, err := service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: newChallenge(), Code: nextCode})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+
+	_, err = service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: newChallenge(), Code: nextCode})
+	assert.ErrorIs(t, err, errs.ErrInvalidTOTPCode, "a code must not complete a second challenge")
+}
+
+func TestCompleteLogin_BackupCodeUsableOnce(t *testing.T) {
+	service, user, _, challenges := newMFATestService(t, true)
+
+	backupCodes, err := domain.GenerateBackupCodes(4)
+	require.NoError(t, err)
+	backupCodeRepo := &stubMFABackupCodeRepository{unused: make(map[string]bool)}
+	for _, hash := range hashBackupCodes(backupCodes) {
+		backupCodeRepo.unused[hash] = true
+	}
+
+	service.config.JWT = config.JWTConfig{AccessTokenDuration: time.Minute, RefreshTokenDuration: time.Hour}
+	service.config.MFA.BackupCodeLowThreshold = 3
+	service.mfaBackupCodeRepo = backupCodeRepo
+	service.txManager = stubTxManager{}
+	service.tokenMaker = token.NewJWTTokenMaker("test-secret-key-with-at-least-32-chars", 0)
+	service.refreshTokenRepo = stubRefreshTokenRepository{}
+	service.notificationEventLogRepo = stubNotificationEventLogRepository{}
+	service.notificationPrefRepo = &memoryNotificationPreferenceRepository{}
+
+	newChallenge := func() string {
+		challenge, err := domain.NewMFAChallenge(user.ID, time.Minute)
+		require.NoError(t, err)
+		challenges.challenges[challenge.ID] = challenge
+		return challenge.ID.String()
+	}
+
+	// Codes are accepted regardless of case and dashes
+	resp, err := service.CompleteLogin(context.Background(), dto.CompleteLoginReq{
+		ChallengeID: newChallenge(),
+		BackupCode:  strings.ToLower(strings.ReplaceAll(backupCodes[0], "-", "")),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	require.NotNil(t, resp.BackupCodesRemaining)
+	assert.Equal(t, 3, *resp.BackupCodesRemaining)
+	assert.True(t, resp.BackupCodesLow)
+
+	_, err = service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: newChallenge(), BackupCode: backupCodes[0]})
+	assert.ErrorIs(t, err, errs.ErrInvalidBackupCode, "a backup code must only work once")
+}
+
+func TestCompleteLogin_RejectsUnknownOrExpiredChallenge(t *testing.T) {
+	service, user, _, challenges := newMFATestService(t, true)
+
+	_, err := service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: "not-a-uuid", Code: "123456"})
+	assert.ErrorIs(t, err, errs.ErrInvalidMFAChallenge)
+
+	_, err = service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: uuid.NewString(), Code: "123456"})
+	assert.ErrorIs(t, err, errs.ErrInvalidMFAChallenge)
+
+	expired := &domain.MFAChallenge{ID: uuid.New(), UserID: user.ID, ExpiresAt: domain.FromTime(time.Now().Add(-time.Second))}
+	challenges.challenges[expired.ID] = expired
+
+	_, err = service.CompleteLogin(context.Background(), dto.CompleteLoginReq{ChallengeID: expired.ID.String(), Code: "123456"})
+	assert.ErrorIs(t, err, errs.ErrInvalidMFAChallenge)
+}
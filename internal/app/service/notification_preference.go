@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/internal/app/model/events"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+	"wallet-user-svc/pkg/utils/tx"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// notificationDefaults lists the event types users can configure and whether they are
+// notified when no preference is stored. Security relevant notifications default to enabled.
+var notificationDefaults = map[events.EventType]bool{
+	events.LoginEventType: true,
+}
+
+// notificationEnabled reports whether user userID should be notified of eventType. A
+// failed lookup falls back to the default, so a preferences outage neither silences
+// security notifications nor fails the operation that emits them.
+func (s *UserService) notificationEnabled(ctx context.Context, userID uuid.UUID, eventType events.EventType, logger *logrus.Entry) bool {
+	defaultEnabled := notificationDefaults[eventType]
+
+	enabled, err := s.notificationPrefRepo.IsEnabled(ctx, userID, string(eventType), defaultEnabled)
+	if err != nil {
+		logger.WithError(err).WithField("event_type", string(eventType)).Warn("Failed to get notification preference, using the default")
+		return defaultEnabled
+	}
+
+	return enabled
+}
+
+// GetNotificationPreferences returns the authenticated user's setting for every
+// configurable event type, filling in defaults for types the user has not changed
+func (s *UserService) GetNotificationPreferences(ctx context.Context) (*dto.GetNotificationPreferencesResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	userID, ok := cx.GetAuthUserID(ctx)
+	if !ok {
+		return nil, errs.ErrUnauthenticated
+	}
+
+	preferences, err := s.notificationPreferences(ctx, userID)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", userID.String()).Error("Failed to list notification preferences")
+		return nil, err
+	}
+
+	return &dto.GetNotificationPreferencesResp{Preferences: preferences}, nil
+}
+
+// UpdateNotificationPreferences stores the given settings of the authenticated user and
+// returns the resulting preferences. Unknown event types are rejected before anything is stored.
+func (s *UserService) UpdateNotificationPreferences(ctx context.Context, req dto.UpdateNotificationPreferencesReq) (*dto.UpdateNotificationPreferencesResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	userID, ok := cx.GetAuthUserID(ctx)
+	if !ok {
+		return nil, errs.ErrUnauthenticated
+	}
+	logger = logger.WithField("user_id", userID.String())
+
+	for _, preference := range req.Preferences {
+		if _, ok := notificationDefaults[events.EventType(preference.EventType)]; !ok {
+			logger.WithField("event_type", preference.EventType).Warn("Unknown notification event type")
+			return nil, errs.ErrUnknownEventType
+		}
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
+		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
+		for _, preference := range req.Preferences {
+			if err := s.notificationPrefRepo.Upsert(txCtx, &domain.NotificationPreference{
+				UserID:    userID,
+				EventType: preference.EventType,
+				Enabled:   preference.Enabled,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to update notification preferences")
+		return nil, err
+	}
+
+	changes := make(map[string]bool, len(req.Preferences))
+	for _, preference := range req.Preferences {
+		changes[preference.EventType] = preference.Enabled
+	}
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionUpdateNotificationPreferences,
+		UserID:  userID.String(),
+		Success: true,
+		Fields:  logrus.Fields{"preferences": changes},
+	})
+	logger.Info("Notification preferences updated")
+
+	preferences, err := s.notificationPreferences(ctx, userID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list notification preferences")
+		return nil, err
+	}
+
+	return &dto.UpdateNotificationPreferencesResp{Preferences: preferences}, nil
+}
+
+// notificationPreferences merges the stored preferences of a user with the defaults,
+// ordered by event type
+func (s *UserService) notificationPreferences(ctx context.Context, userID uuid.UUID) ([]dto.NotificationPreference, error) {
+	stored, err := s.notificationPrefRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[string]bool, len(notificationDefaults))
+	for eventType, defaultEnabled := range notificationDefaults {
+		enabled[string(eventType)] = defaultEnabled
+	}
+	for _, preference := range stored {
+		if _, ok := enabled[preference.EventType]; ok {
+			enabled[preference.EventType] = preference.Enabled
+		}
+	}
+
+	preferences := make([]dto.NotificationPreference, 0, len(enabled))
+	for eventType, isEnabled := range enabled {
+		preferences = append(preferences, dto.NotificationPreference{EventType: eventType, Enabled: isEnabled})
+	}
+	sort.Slice(preferences, func(i, j int) bool {
+		return preferences[i].EventType < preferences[j].EventType
+	})
+
+	return preferences, nil
+}
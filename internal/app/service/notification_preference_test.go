@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/internal/app/repository"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryNotificationPreferenceRepository keeps stored preferences keyed by user and event type
+type memoryNotificationPreferenceRepository struct {
+	preferences map[uuid.UUID]map[string]bool
+	err         error
+}
+
+func (r *memoryNotificationPreferenceRepository) IsEnabled(_ context.Context, userID uuid.UUID, eventType string, defaultEnabled bool) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+	enabled, ok := r.preferences[userID][eventType]
+	if !ok {
+		return defaultEnabled, nil
+	}
+	return enabled, nil
+}
+
+func (r *memoryNotificationPreferenceRepository) ListByUserID(_ context.Context, userID uuid.UUID) ([]*domain.NotificationPreference, error) {
+	var preferences []*domain.NotificationPreference
+	for eventType, enabled := range r.preferences[userID] {
+		preferences = append(preferences, &domain.NotificationPreference{UserID: userID, EventType: eventType, Enabled: enabled})
+	}
+	return preferences, nil
+}
+
+func (r *memoryNotificationPreferenceRepository) Upsert(_ context.Context, preference *domain.NotificationPreference) error {
+	if r.preferences == nil {
+		r.preferences = make(map[uuid.UUID]map[string]bool)
+	}
+	if r.preferences[preference.UserID] == nil {
+		r.preferences[preference.UserID] = make(map[string]bool)
+	}
+	r.preferences[preference.UserID][preference.EventType] = preference.Enabled
+	return nil
+}
+
+// countingNotificationEventLogRepository counts the notification events created
+type countingNotificationEventLogRepository struct {
+	created int
+}
+
+func (r *countingNotificationEventLogRepository) Create(context.Context, *repository.NotificationEventLog) error {
+	r.created++
+	return nil
+}
+
+func TestNotificationPreferences_DefaultsAndUpdate(t *testing.T) {
+	service, _ := newRegisterTestService(false)
+	auditLogger := &recordingAuditLogger{}
+	service.auditLogger = auditLogger
+	ctx := cx.WithAuthUserID(context.Background(), uuid.New())
+
+	resp, err := service.GetNotificationPreferences(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []dto.NotificationPreference{{EventType: "login", Enabled: true}}, resp.Preferences,
+		"login notifications are security relevant and enabled by default")
+
+	updated, err := service.UpdateNotificationPreferences(ctx, dto.UpdateNotificationPreferencesReq{
+		Preferences: []dto.NotificationPreference{{EventType: "login", Enabled: false}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []dto.NotificationPreference{{EventType: "login", Enabled: false}}, updated.Preferences)
+
+	require.Len(t, auditLogger.events, 1)
+	assert.Equal(t, audit.ActionUpdateNotificationPreferences, auditLogger.events[0].Action)
+	assert.True(t, auditLogger.events[0].Success)
+}
+
+func TestUpdateNotificationPreferences_UnknownEventType(t *testing.T) {
+	service, _ := newRegisterTestService(false)
+	prefRepo := &memoryNotificationPreferenceRepository{}
+	service.notificationPrefRepo = prefRepo
+	ctx := cx.WithAuthUserID(context.Background(), uuid.New())
+
+	_, err := service.UpdateNotificationPreferences(ctx, dto.UpdateNotificationPreferencesReq{
+		Preferences: []dto.NotificationPreference{{EventType: "login", Enabled: false}, {EventType: "marketing", Enabled: false}},
+	})
+	assert.ErrorIs(t, err, errs.ErrUnknownEventType)
+	assert.Empty(t, prefRepo.preferences, "nothing is stored when any event type is unknown")
+}
+
+func TestNotificationPreferences_RequireAuthentication(t *testing.T) {
+	service, _ := newRegisterTestService(false)
+
+	_, err := service.GetNotificationPreferences(context.Background())
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+
+	_, err = service.UpdateNotificationPreferences(context.Background(), dto.UpdateNotificationPreferencesReq{})
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+}
+
+func TestCreateLoginNotification_RespectsPreference(t *testing.T) {
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+	logger := logutils.GetLoggerOrDefault(context.Background())
+
+	tests := []struct {
+		name        string
+		preferences map[uuid.UUID]map[string]bool
+		lookupErr   error
+		wantCreated int
+	}{
+		{name: "default", wantCreated: 1},
+		{name: "disabled", preferences: map[uuid.UUID]map[string]bool{user.ID: {"login": false}}, wantCreated: 0},
+		{name: "enabled", preferences: map[uuid.UUID]map[string]bool{user.ID: {"login": true}}, wantCreated: 1},
+		{name: "lookup failure uses the default", lookupErr: errors.New("connection refused"), wantCreated: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventLogRepo := &countingNotificationEventLogRepository{}
+			service := &UserService{
+				notificationEventLogRepo: eventLogRepo,
+				notificationPrefRepo:     &memoryNotificationPreferenceRepository{preferences: tt.preferences, err: tt.lookupErr},
+			}
+
+			require.NoError(t, service.createLoginNotification(context.Background(), user, logger))
+			assert.Equal(t, tt.wantCreated, eventLogRepo.created)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+	"wallet-user-svc/pkg/utils/tx"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultReEncryptBatchSize is the number of secrets re-encrypted per transaction
+	defaultReEncryptBatchSize = 100
+	// maxReEncryptBatchSize bounds how long a batch keeps its rows locked
+	maxReEncryptBatchSize = 1000
+)
+
+// ReEncryptSecrets moves the TOTP secrets encrypted with a previous key to the current
+// one after mfa.encryption_key was rotated. Users are processed in ID order, one batch
+// per transaction, so a run that stops part way can resume after the last user it
+// reported. Secrets already under the current key are left alone, which makes repeated
+// runs harmless; secrets no configured key decrypts are counted and skipped. The key is
+// shared by every tenant, so the run covers the users of all tenants, not only the
+// caller's.
+func (s *UserService) ReEncryptSecrets(ctx context.Context, req dto.ReEncryptSecretsReq) (*dto.ReEncryptSecretsResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	adminID, ok := cx.GetAuthUserID(ctx)
+	if !ok {
+		return nil, errs.ErrUnauthenticated
+	}
+
+	cursor := uuid.Nil
+	if req.AfterUserID != "" {
+		var err error
+		if cursor, err = uuid.Parse(req.AfterUserID); err != nil {
+			return nil, errs.ErrInvalidID
+		}
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultReEncryptBatchSize
+	}
+	batchSize = min(batchSize, maxReEncryptBatchSize)
+
+	resp := &dto.ReEncryptSecretsResp{}
+	for batches := 0; req.MaxBatches <= 0 || batches < req.MaxBatches; batches++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var processed int
+		err := s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
+			txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
+
+			secrets, err := s.userRepo.ListTOTPSecrets(txCtx, cursor, batchSize)
+			if err != nil {
+				return err
+			}
+			processed = len(secrets)
+
+			for _, secret := range secrets {
+				reEncrypted, changed, err := s.secretCipher.ReEncrypt(secret.Ciphertext)
+				if err != nil {
+					logger.WithError(err).WithField("user_id", secret.UserID.String()).Warn("Stored TOTP secret cannot be decrypted with any configured key")
+					resp.Failed++
+					continue
+				}
+				if !changed {
+					continue
+				}
+
+				if err := s.userRepo.ReplaceTOTPSecret(txCtx, secret.UserID, secret.Ciphertext, reEncrypted); err != nil {
+					// The user was deleted after the batch was read
+					if errors.Is(err, errs.ErrUserNotFound) {
+						continue
+					}
+					return err
+				}
+				resp.ReEncrypted++
+			}
+
+			if processed > 0 {
+				cursor = secrets[processed-1].UserID
+			}
+			return nil
+		})
+		if err != nil {
+			logger.WithError(err).WithField("after_user_id", resp.LastUserID).Error("Failed to re-encrypt secrets")
+			return nil, err
+		}
+
+		resp.Processed += processed
+		if processed > 0 {
+			resp.LastUserID = cursor.String()
+		}
+		logger.WithFields(logrus.Fields{
+			"processed":    resp.Processed,
+			"re_encrypted": resp.ReEncrypted,
+			"last_user_id": resp.LastUserID,
+			"failed":       resp.Failed,
+		}).Info("Re-encrypted secrets batch")
+
+		if processed < batchSize {
+			resp.Done = true
+			break
+		}
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionReEncryptSecrets,
+		UserID:  adminID.String(),
+		Success: true,
+		Fields: logrus.Fields{
+			"processed":    resp.Processed,
+			"re_encrypted": resp.ReEncrypted,
+			"failed":       resp.Failed,
+			"done":         resp.Done,
+		},
+	})
+
+	return resp, nil
+}
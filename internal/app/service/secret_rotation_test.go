@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/crypt/secret"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTOTPSecretRepository stores encrypted TOTP secrets by user ID
+type memoryTOTPSecretRepository struct {
+	UserRepository
+	secrets map[uuid.UUID]string
+}
+
+func (r *memoryTOTPSecretRepository) ListTOTPSecrets(_ context.Context, afterID uuid.UUID, limit int) ([]*domain.TOTPSecret, error) {
+	ids := make([]uuid.UUID, 0, len(r.secrets))
+	for id := range r.secrets {
+		if strings.Compare(id.String(), afterID.String()) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+
+	secrets := make([]*domain.TOTPSecret, 0, limit)
+	for _, id := range ids[:min(limit, len(ids))] {
+		secrets = append(secrets, &domain.TOTPSecret{UserID: id, Ciphertext: r.secrets[id]})
+	}
+	return secrets, nil
+}
+
+func (r *memoryTOTPSecretRepository) ReplaceTOTPSecret(_ context.Context, id uuid.UUID, currentSecret, newSecret string) error {
+	if r.secrets[id] != currentSecret {
+		return errs.ErrUserNotFound
+	}
+	r.secrets[id] = newSecret
+	return nil
+}
+
+func TestReEncryptSecrets_ResumesAndIsIdempotent(t *testing.T) {
+	oldCipher, err := secret.NewCipher("old-encryption-key")
+	require.NoError(t, err)
+	rotatedCipher, err := secret.NewCipher("new-encryption-key", "old-encryption-key")
+	require.NoError(t, err)
+	newCipher, err := secret.NewCipher("new-encryption-key")
+	require.NoError(t, err)
+
+	repo := &memoryTOTPSecretRepository{secrets: make(map[uuid.UUID]string)}
+	for i := 0; i < 3; i++ {
+		encrypted, err := oldCipher.Encrypt("JBSWY3DPEHPK3PXP")
+		require.NoError(t, err)
+		repo.secrets[uuid.New()] = encrypted
+	}
+	current, err := newCipher.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	currentUserID := uuid.New()
+	repo.secrets[currentUserID] = current
+	undecryptableUserID := uuid.New()
+	repo.secrets[undecryptableUserID] = "not-a-ciphertext"
+
+	auditLogger := &recordingAuditLogger{}
+	service := &UserService{
+		userRepo:     repo,
+		txManager:    stubTxManager{},
+		auditLogger:  auditLogger,
+		secretCipher: rotatedCipher,
+	}
+	ctx := cx.WithAuthUserID(context.Background(), uuid.New())
+
+	// Process one batch at a time, resuming after the last user of the previous run
+	total := &dto.ReEncryptSecretsResp{}
+	req := dto.ReEncryptSecretsReq{BatchSize: 2, MaxBatches: 1}
+	for runs := 0; !total.Done; runs++ {
+		require.Less(t, runs, 5, "the run should finish")
+
+		resp, err := service.ReEncryptSecrets(ctx, req)
+		require.NoError(t, err)
+		total.Processed += resp.Processed
+		total.ReEncrypted += resp.ReEncrypted
+		total.Failed += resp.Failed
+		total.Done = resp.Done
+		req.AfterUserID = resp.LastUserID
+	}
+
+	assert.Equal(t, 5, total.Processed)
+	assert.Equal(t, 3, total.ReEncrypted)
+	assert.Equal(t, 1, total.Failed)
+	assert.Equal(t, current, repo.secrets[currentUserID], "secrets under the current key are left alone")
+	for id, encrypted := range repo.secrets {
+		if id == undecryptableUserID {
+			continue
+		}
+		decrypted, err := newCipher.Decrypt(encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "JBSWY3DPEHPK3PXP", decrypted)
+	}
+
+	// A second full run has nothing left to do
+	resp, err := service.ReEncryptSecrets(ctx, dto.ReEncryptSecretsReq{})
+	require.NoError(t, err)
+	assert.True(t, resp.Done)
+	assert.Equal(t, 5, resp.Processed)
+	assert.Zero(t, resp.ReEncrypted)
+	assert.Equal(t, audit.ActionReEncryptSecrets, auditLogger.events[len(auditLogger.events)-1].Action)
+}
+
+func TestReEncryptSecrets_InvalidCursor(t *testing.T) {
+	service := &UserService{}
+	ctx := cx.WithAuthUserID(context.Background(), uuid.New())
+
+	_, err := service.ReEncryptSecrets(ctx, dto.ReEncryptSecretsReq{AfterUserID: "not-a-uuid"})
+	assert.ErrorIs(t, err, errs.ErrInvalidID)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ListSessions returns the active sessions (unrevoked, unexpired refresh tokens) of the
+// authenticated user, newest first
+func (s *UserService) ListSessions(ctx context.Context) (*dto.ListSessionsResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	userID, ok := cx.GetAuthUserID(ctx)
+	if !ok {
+		return nil, errs.ErrUnauthenticated
+	}
+
+	refreshTokens, err := s.refreshTokenRepo.ListActiveByUserID(ctx, userID, domain.Now())
+	if err != nil {
+		logger.WithError(err).WithField("user_id", userID.String()).Error("Failed to list sessions")
+		return nil, err
+	}
+
+	sessions := make([]dto.Session, 0, len(refreshTokens))
+	for _, refreshToken := range refreshTokens {
+		sessions = append(sessions, dto.Session{
+			ID:        refreshToken.ID.String(),
+			CreatedAt: refreshToken.CreatedAt.Millis(),
+			ExpiresAt: refreshToken.ExpiresAt.Millis(),
+		})
+	}
+
+	return &dto.ListSessionsResp{Sessions: sessions}, nil
+}
+
+// RevokeSession revokes one session of the authenticated user. A session of another
+// user is reported as not found so its existence is not revealed.
+func (s *UserService) RevokeSession(ctx context.Context, req dto.RevokeSessionReq) error {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	userID, ok := cx.GetAuthUserID(ctx)
+	if !ok {
+		return errs.ErrUnauthenticated
+	}
+	logger = logger.WithField("user_id", userID.String())
+
+	sessionID, err := uuid.Parse(req.SessionID)
+	if err != nil {
+		return errs.ErrInvalidID
+	}
+	logger = logger.WithField("session_id", sessionID.String())
+
+	refreshToken, err := s.refreshTokenRepo.GetByID(ctx, sessionID)
+	if err != nil && !errors.Is(err, errs.ErrTokenNotFound) {
+		logger.WithError(err).Error("Failed to retrieve session")
+		return err
+	}
+	if err != nil || refreshToken.UserID != userID || refreshToken.IsRevoked {
+		logger.Warn("Session to revoke not found")
+		s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionRevokeSession, UserID: userID.String(), Reason: "session not found"})
+		return errs.ErrSessionNotFound
+	}
+
+	if err := s.refreshTokenRepo.RevokeByID(ctx, sessionID); err != nil {
+		// A concurrent revocation of the same session
+		if errors.Is(err, errs.ErrTokenNotFound) {
+			return errs.ErrSessionNotFound
+		}
+		logger.WithError(err).Error("Failed to revoke session")
+		return err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionRevokeSession,
+		UserID:  userID.String(),
+		Success: true,
+		Fields:  logrus.Fields{"session_id": sessionID.String()},
+	})
+	logger.Info("Session revoked")
+
+	return nil
+}
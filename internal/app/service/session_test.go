@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/crypt/token"
+	"wallet-user-svc/pkg/utils/cx"
+	grpcutils "wallet-user-svc/pkg/utils/grpc"
+	"wallet-user-svc/pkg/utils/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// registerSession registers a user and returns them with the ID of the session created
+// by the registration
+func registerSession(t *testing.T, service *UserService, username, email string) (*domain.User, string) {
+	t.Helper()
+
+	resp, err := service.Register(context.Background(), dto.RegisterReq{
+		Username: username,
+		Password: "Password123!",
+		Email:    stringPtr(email),
+	})
+	require.NoError(t, err)
+
+	ctx := cx.WithAuthUserID(context.Background(), resp.User.ID)
+	sessions, err := service.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions.Sessions, 1)
+
+	return resp.User, sessions.Sessions[0].ID
+}
+
+func TestRevokeSession(t *testing.T) {
+	service, _ := newRegisterTestService(false)
+	service.refreshTokenRepo = &memoryRefreshTokenRepository{}
+	auditLogger := &recordingAuditLogger{}
+	service.auditLogger = auditLogger
+
+	owner, sessionID := registerSession(t, service, "owner", "owner@example.com")
+	other, _ := registerSession(t, service, "other", "other@example.com")
+	ownerCtx := cx.WithAuthUserID(context.Background(), owner.ID)
+	otherCtx := cx.WithAuthUserID(context.Background(), other.ID)
+
+	err := service.RevokeSession(otherCtx, dto.RevokeSessionReq{SessionID: sessionID})
+	assert.ErrorIs(t, err, errs.ErrSessionNotFound, "another user's session must look nonexistent")
+
+	require.NoError(t, service.RevokeSession(ownerCtx, dto.RevokeSessionReq{SessionID: sessionID}))
+	sessions, err := service.ListSessions(ownerCtx)
+	require.NoError(t, err)
+	assert.Empty(t, sessions.Sessions)
+
+	err = service.RevokeSession(ownerCtx, dto.RevokeSessionReq{SessionID: sessionID})
+	assert.ErrorIs(t, err, errs.ErrSessionNotFound)
+
+	err = service.RevokeSession(ownerCtx, dto.RevokeSessionReq{SessionID: "not-a-uuid"})
+	assert.ErrorIs(t, err, errs.ErrInvalidID)
+
+	require.Len(t, auditLogger.events, 3)
+	assert.Equal(t, audit.ActionRevokeSession, auditLogger.events[1].Action)
+	assert.True(t, auditLogger.events[1].Success)
+	assert.Equal(t, owner.ID.String(), auditLogger.events[1].UserID)
+	assert.False(t, auditLogger.events[0].Success)
+	assert.False(t, auditLogger.events[2].Success)
+}
+
+func TestRevokeSession_RequiresAuthentication(t *testing.T) {
+	service, _ := newRegisterTestService(false)
+
+	err := service.RevokeSession(context.Background(), dto.RevokeSessionReq{SessionID: "00000000-0000-0000-0000-000000000001"})
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+}
+
+// TestLogin_AccessTokenAuthorizesRPC follows an access token from Login through the auth
+// interceptor to an authenticated RPC, all with the real JWT token maker and the default
+// access token duration
+func TestLogin_AccessTokenAuthorizesRPC(t *testing.T) {
+	service, userRepo := newRegisterTestService(false)
+	service.config.JWT.AccessTokenDuration = 15 * time.Minute
+	service.refreshTokenRepo = &memoryRefreshTokenRepository{}
+	service.notificationEventLogRepo = stubNotificationEventLogRepository{}
+	service.loginLimiter = ratelimit.NewLimiter(10, time.Minute)
+	service.accountLoginLimiter = ratelimit.NewLimiter(10, time.Minute)
+
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+	userRepo.usersByEmail = map[string]*domain.User{"known@example.com": user}
+
+	login, err := service.Login(context.Background(), dto.LoginReq{Email: "known@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), time.UnixMilli(login.AccessTokenExpiresAt), 2*time.Second)
+
+	const listSessionsMethod = "/user.UserService/ListSessions"
+	interceptor := grpcutils.AuthInterceptor(service.tokenMaker.(*token.JWTTokenMaker), nil,
+		map[string]grpcutils.AccessLevel{listSessionsMethod: grpcutils.AccessAuthenticated}, grpcutils.ExpiryGrace{})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+login.AccessToken))
+
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: listSessionsMethod}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return service.ListSessions(ctx)
+	})
+	require.NoError(t, err, "a freshly issued access token must authorize the call")
+	assert.Len(t, resp.(*dto.ListSessionsResp).Sessions, 1)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/internal/app/repository"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultTokenStatsPageSize is the number of users returned by GetTokenStats
+	defaultTokenStatsPageSize = 10
+	// maxTokenStatsPageSize bounds the per-user aggregation of one page
+	maxTokenStatsPageSize = 100
+)
+
+// GetTokenStats counts the refresh tokens of the tenant by state, overall and for one
+// page of users ordered by active tokens. Users holding far more active tokens than
+// others may be abusing the service, and a growing expired count means cleanup is not
+// keeping up.
+func (s *UserService) GetTokenStats(ctx context.Context, req dto.GetTokenStatsReq) (*dto.GetTokenStatsResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultTokenStatsPageSize
+	}
+	pageSize = min(pageSize, maxTokenStatsPageSize)
+	offset := max(req.Offset, 0)
+
+	now := domain.Now()
+
+	total, err := s.refreshTokenRepo.CountByState(ctx, now)
+	if err != nil {
+		logger.WithError(err).Error("Failed to count refresh tokens")
+		return nil, err
+	}
+
+	// One extra user tells whether another page follows
+	users, err := s.refreshTokenRepo.CountByUser(ctx, now, pageSize+1, offset)
+	if err != nil {
+		logger.WithError(err).Error("Failed to count refresh tokens by user")
+		return nil, err
+	}
+
+	resp := &dto.GetTokenStatsResp{
+		Total: tokenCounts(*total),
+		Users: make([]dto.UserTokenCounts, 0, min(len(users), pageSize)),
+	}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		resp.NextOffset = offset + pageSize
+	}
+	for _, user := range users {
+		resp.Users = append(resp.Users, dto.UserTokenCounts{
+			UserID: user.UserID.String(),
+			Counts: tokenCounts(user.RefreshTokenCounts),
+		})
+	}
+
+	logger.WithFields(logrus.Fields{
+		"active":  total.Active,
+		"revoked": total.Revoked,
+		"expired": total.Expired,
+		"users":   len(resp.Users),
+		"offset":  offset,
+	}).Info("Collected refresh token stats")
+
+	return resp, nil
+}
+
+func tokenCounts(counts repository.RefreshTokenCounts) dto.TokenCounts {
+	return dto.TokenCounts{
+		Active:  counts.Active,
+		Revoked: counts.Revoked,
+		Expired: counts.Expired,
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTokenStats(t *testing.T) {
+	service, _ := newRegisterTestService(false)
+	repo := &memoryRefreshTokenRepository{}
+	service.refreshTokenRepo = repo
+
+	heavy, light, idle := uuid.New(), uuid.New(), uuid.New()
+	addToken := func(userID uuid.UUID, expiresIn time.Duration, revoked bool) {
+		refreshToken, err := domain.NewRefreshToken(userID, uuid.NewString(), "", domain.FromTime(time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		refreshToken.ExpiresAt = domain.FromTime(time.Now().Add(expiresIn))
+		refreshToken.IsRevoked = revoked
+		require.NoError(t, repo.Create(context.Background(), refreshToken))
+	}
+	for i := 0; i < 3; i++ {
+		addToken(heavy, time.Hour, false)
+	}
+	addToken(heavy, time.Hour, true)
+	addToken(light, time.Hour, false)
+	addToken(light, -time.Hour, false)
+	addToken(idle, -time.Hour, true)
+
+	resp, err := service.GetTokenStats(context.Background(), dto.GetTokenStatsReq{PageSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, dto.TokenCounts{Active: 4, Revoked: 2, Expired: 1}, resp.Total)
+	require.Len(t, resp.Users, 2)
+	assert.Equal(t, heavy.String(), resp.Users[0].UserID, "users are ordered by active tokens")
+	assert.Equal(t, dto.TokenCounts{Active: 3, Revoked: 1}, resp.Users[0].Counts)
+	assert.Equal(t, dto.TokenCounts{Active: 1, Expired: 1}, resp.Users[1].Counts)
+	assert.Equal(t, 2, resp.NextOffset)
+
+	resp, err = service.GetTokenStats(context.Background(), dto.GetTokenStatsReq{PageSize: 2, Offset: resp.NextOffset})
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 1)
+	assert.Equal(t, idle.String(), resp.Users[0].UserID)
+	assert.Zero(t, resp.NextOffset, "the last page has no next offset")
+}
@@ -2,8 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"wallet-user-svc/internal/app/config"
@@ -12,11 +17,15 @@ import (
 	"wallet-user-svc/internal/app/model/dto"
 	"wallet-user-svc/internal/app/model/events"
 	"wallet-user-svc/internal/app/repository"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/crypt/password"
 	"wallet-user-svc/pkg/utils/crypt/token"
 	"wallet-user-svc/pkg/utils/cx"
 	logutils "wallet-user-svc/pkg/utils/log"
+	"wallet-user-svc/pkg/utils/ratelimit"
 	"wallet-user-svc/pkg/utils/tx"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
@@ -25,17 +34,75 @@ type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetByPhone(ctx context.Context, countryCode, phone string) (*domain.User, error)
+	GetByIdentifier(ctx context.Context, identifier dto.Identifier) (*domain.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	GetPasswordHash(ctx context.Context, id uuid.UUID) (domain.PasswordHash, error)
+	SetTOTPSecret(ctx context.Context, id uuid.UUID, encryptedSecret string) error
+	EnableTOTP(ctx context.Context, id uuid.UUID) error
+	ConsumeTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error)
+	ListTOTPSecrets(ctx context.Context, afterID uuid.UUID, limit int) ([]*domain.TOTPSecret, error)
+	ReplaceTOTPSecret(ctx context.Context, id uuid.UUID, currentSecret, newSecret string) error
+	UpdateContact(ctx context.Context, user *domain.User) error
+	SetPrimaryContact(ctx context.Context, id uuid.UUID, method domain.ContactMethod) error
+}
+
+type MFAChallengeRepository interface {
+	Create(ctx context.Context, challenge *domain.MFAChallenge) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.MFAChallenge, error)
+	ReserveAttempt(ctx context.Context, id uuid.UUID, maxAttempts int, now domain.Timestamp) error
+	Consume(ctx context.Context, id uuid.UUID) error
+}
+
+type ContactVerificationRepository interface {
+	Create(ctx context.Context, verification *domain.ContactVerification) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ContactVerification, error)
+	ReserveAttempt(ctx context.Context, id uuid.UUID, maxAttempts int, now domain.Timestamp) error
+	Consume(ctx context.Context, id uuid.UUID) error
+}
+
+type WebAuthnCredentialRepository interface {
+	Create(ctx context.Context, credential *domain.WebAuthnCredential) error
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.WebAuthnCredential, error)
+	UpdateSignCount(ctx context.Context, credentialID []byte, signCount uint32) error
+}
+
+type WebAuthnSessionRepository interface {
+	Create(ctx context.Context, session *domain.WebAuthnSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.WebAuthnSession, error)
+	Consume(ctx context.Context, id uuid.UUID) error
+}
+
+type MFABackupCodeRepository interface {
+	Replace(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	Consume(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	CountRemaining(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// SecretCipher encrypts secrets stored at rest
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+	// ReEncrypt moves a value encrypted with a previous key to the current key and
+	// reports whether it had to
+	ReEncrypt(ciphertext string) (string, bool, error)
 }
 
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, refreshToken *domain.RefreshToken) error
 	GetByToken(ctx context.Context, token string) (*domain.RefreshToken, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.RefreshToken, error)
+	ListActiveByUserID(ctx context.Context, userID uuid.UUID, now domain.Timestamp) ([]*domain.RefreshToken, error)
+	RevokeByID(ctx context.Context, id uuid.UUID) error
+	MarkReplaced(ctx context.Context, id, replacedBy uuid.UUID) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+	CountByState(ctx context.Context, now domain.Timestamp) (*repository.RefreshTokenCounts, error)
+	CountByUser(ctx context.Context, now domain.Timestamp, limit, offset int) ([]repository.UserRefreshTokenCounts, error)
 }
 
 type TxManager interface {
 	WithTransaction(ctx context.Context, fn func(*tx.TxWrapper) error) error
-	WithTransactionOptions(ctx context.Context, fn func(*tx.TxWrapper) error, opts *sql.TxOptions) error
+	WithTransactionOptions(ctx context.Context, fn func(*tx.TxWrapper) error, opts *sql.TxOptions, beforeCommit ...tx.BeforeCommitHook) error
 	WithTransactionIsolation(ctx context.Context, fn func(*tx.TxWrapper) error, isolation sql.IsolationLevel) error
 	WithReadOnlyTransaction(ctx context.Context, fn func(*tx.TxWrapper) error) error
 	WithSerializableTransaction(ctx context.Context, fn func(*tx.TxWrapper) error) error
@@ -47,6 +114,30 @@ type NotificationEventLogRepository interface {
 	Create(ctx context.Context, event *repository.NotificationEventLog) error
 }
 
+type NotificationPreferenceRepository interface {
+	IsEnabled(ctx context.Context, userID uuid.UUID, eventType string, defaultEnabled bool) (bool, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.NotificationPreference, error)
+	Upsert(ctx context.Context, preference *domain.NotificationPreference) error
+}
+
+// RateLimiter limits how often an operation may be performed per key
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) bool
+}
+
+// FailureLimiter throttles a key once too many failures were recorded for it
+type FailureLimiter interface {
+	// Allow records a failure for key and reports whether it was within the limit
+	Allow(ctx context.Context, key string) bool
+	// Exceeded reports whether key has used up its limit, without recording a failure
+	Exceeded(ctx context.Context, key string) bool
+}
+
+// AuditLogger records security relevant events
+type AuditLogger interface {
+	Log(ctx context.Context, event audit.Event)
+}
+
 // UserService handles business logic for user operations
 type UserService struct {
 	config                   *config.Config
@@ -55,6 +146,24 @@ type UserService struct {
 	txManager                TxManager
 	tokenMaker               token.TokenMaker
 	notificationEventLogRepo NotificationEventLogRepository
+	verifyPasswordLimiter    RateLimiter
+	loginLimiter             RateLimiter
+	accountLoginLimiter      RateLimiter
+	auditLogger              AuditLogger
+	mfaChallengeRepo         MFAChallengeRepository
+	mfaBackupCodeRepo        MFABackupCodeRepository
+	secretCipher             SecretCipher
+	notificationPrefRepo     NotificationPreferenceRepository
+	refreshFailureLimiter    FailureLimiter
+	hashSemaphore            *ratelimit.Semaphore
+	passwordHasher           *password.Hasher
+	contactVerificationRepo  ContactVerificationRepository
+	// webAuthn verifies passkey ceremonies; nil unless webauthn.enabled is set
+	webAuthn               *webauthn.WebAuthn
+	webAuthnCredentialRepo WebAuthnCredentialRepository
+	webAuthnSessionRepo    WebAuthnSessionRepository
+	// droppedNotifications counts notification events skipped because recording them failed
+	droppedNotifications atomic.Int64
 }
 
 // NewUserService creates a new UserService instance
@@ -65,6 +174,19 @@ func NewUserService(
 	txManager TxManager,
 	tokenMaker token.TokenMaker,
 	notificationEventLogRepo NotificationEventLogRepository,
+	verifyPasswordLimiter RateLimiter,
+	loginLimiter RateLimiter,
+	accountLoginLimiter RateLimiter,
+	auditLogger AuditLogger,
+	mfaChallengeRepo MFAChallengeRepository,
+	mfaBackupCodeRepo MFABackupCodeRepository,
+	secretCipher SecretCipher,
+	notificationPrefRepo NotificationPreferenceRepository,
+	refreshFailureLimiter FailureLimiter,
+	contactVerificationRepo ContactVerificationRepository,
+	webAuthn *webauthn.WebAuthn,
+	webAuthnCredentialRepo WebAuthnCredentialRepository,
+	webAuthnSessionRepo WebAuthnSessionRepository,
 ) *UserService {
 	logutils.Info("Initializing UserService")
 
@@ -75,6 +197,21 @@ func NewUserService(
 		txManager:                txManager,
 		tokenMaker:               tokenMaker,
 		notificationEventLogRepo: notificationEventLogRepo,
+		verifyPasswordLimiter:    verifyPasswordLimiter,
+		loginLimiter:             loginLimiter,
+		accountLoginLimiter:      accountLoginLimiter,
+		auditLogger:              auditLogger,
+		mfaChallengeRepo:         mfaChallengeRepo,
+		mfaBackupCodeRepo:        mfaBackupCodeRepo,
+		secretCipher:             secretCipher,
+		notificationPrefRepo:     notificationPrefRepo,
+		refreshFailureLimiter:    refreshFailureLimiter,
+		hashSemaphore:            ratelimit.NewSemaphore(config.Password.MaxConcurrentHashes, config.Password.HashQueueTimeout),
+		passwordHasher:           password.DefaultHasher().WithPepper(config.Password.Pepper),
+		contactVerificationRepo:  contactVerificationRepo,
+		webAuthn:                 webAuthn,
+		webAuthnCredentialRepo:   webAuthnCredentialRepo,
+		webAuthnSessionRepo:      webAuthnSessionRepo,
 	}
 
 	logutils.WithFields(logrus.Fields{
@@ -89,32 +226,29 @@ func NewUserService(
 func (s *UserService) Register(ctx context.Context, req dto.RegisterReq) (*dto.RegisterResp, error) {
 	logger := logutils.GetLoggerOrDefault(ctx)
 
-	if err := req.Validate(); err != nil {
-		logger.WithError(err).Error("Request validation failed")
-		return nil, err
+	if !s.config.Registration.Enabled {
+		logger.Warn("Registration attempted while registration is disabled")
+		return nil, errs.ErrRegistrationDisabled
 	}
 
-	user, err := domain.NewUserWithPassword(
-		req.Email,
-		req.Password,
-		req.Username,
-		req.CountryCode,
-		req.Phone,
-	)
+	user, err := s.newUser(ctx, req, logger)
 	if err != nil {
-		logger.WithError(err).Error("Failed to create user with password")
 		return nil, err
 	}
 
-	accessToken, refreshToken, err := s.tokenMaker.CreateTokenPair(
+	issuedAt := time.Now()
+	accessToken, refreshToken, accessPayload, err := s.tokenMaker.CreateTokenPair(
 		user.ID.String(),
 		user.Username.String(),
-		int64(s.config.JWT.AccessTokenDuration),
+		user.Role.String(),
+		requestTenantID(ctx),
+		s.config.JWT.AccessTokenDuration,
 	)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create token pair")
 		return nil, err
 	}
+	refreshTokenExpiresAt := domain.FromTime(issuedAt.Add(s.config.JWT.RefreshTokenDuration))
 
 	err = s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
 		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
@@ -127,7 +261,8 @@ func (s *UserService) Register(ctx context.Context, req dto.RegisterReq) (*dto.R
 		refreshToken, err := domain.NewRefreshToken(
 			user.ID,
 			refreshToken,
-			time.Now().Add(s.config.JWT.RefreshTokenDuration).UnixMilli(),
+			req.Device.Fingerprint(),
+			refreshTokenExpiresAt,
 		)
 		if err != nil {
 			logger.WithError(err).Error("Failed to create refresh token model")
@@ -147,12 +282,139 @@ func (s *UserService) Register(ctx context.Context, req dto.RegisterReq) (*dto.R
 	}
 
 	return &dto.RegisterResp{
-		User:         user,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		User:                  user,
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		Warnings:              req.Warnings(),
+		AccessTokenExpiresAt:  accessPayload.ExpiresAt().UnixMilli(),
+		RefreshTokenExpiresAt: refreshTokenExpiresAt.Millis(),
 	}, nil
 }
 
+// AdminCreateUser provisions an account on behalf of an admin. The account fields follow
+// the rules of Register, but the role and account flags can be set and it works while
+// registration is disabled. The user is not signed in.
+func (s *UserService) AdminCreateUser(ctx context.Context, req dto.AdminCreateUserReq) (*dto.AdminCreateUserResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	role := domain.RoleUser
+	if req.Role != "" {
+		var err error
+		if role, err = domain.NewRole(req.Role); err != nil {
+			logger.WithField("role", req.Role).Warn("Invalid role for new user")
+			return nil, errs.NewValidationError(errs.FieldViolation{Field: "role", Err: err})
+		}
+	}
+
+	user, err := s.newUser(ctx, req.RegisterReq, logger)
+	if err != nil {
+		return nil, err
+	}
+	user.Role = role
+	user.EmailVerified = req.EmailVerified
+	user.MustChangePassword = req.MustChangePassword
+
+	err = s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
+		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
+
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			logger.WithError(err).Error("Failed to create user in database")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Database transaction failed")
+		return nil, err
+	}
+
+	var createdBy string
+	if payload, ok := cx.GetAuthPayload(ctx); ok {
+		createdBy = payload.UserID
+	}
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionCreateUser,
+		UserID:  user.ID.String(),
+		Success: true,
+		Fields: logrus.Fields{
+			"created_by":           createdBy,
+			"role":                 user.Role.String(),
+			"email_verified":       user.EmailVerified,
+			"must_change_password": user.MustChangePassword,
+		},
+	})
+
+	return &dto.AdminCreateUserResp{
+		User:     user,
+		Warnings: req.Warnings(),
+	}, nil
+}
+
+// newUser validates a registration request and builds the user it describes
+func (s *UserService) newUser(ctx context.Context, req dto.RegisterReq, logger *logrus.Entry) (*domain.User, error) {
+	req.Normalize(s.config.Phone.DefaultDialingCode)
+
+	if err := req.Validate(); err != nil {
+		logger.WithError(err).Error("Request validation failed")
+		return nil, err
+	}
+
+	// The unique index still rejects a concurrent registration of the same username;
+	// checking first avoids hashing the password for a request that cannot succeed
+	if s.config.Registration.UniqueUsernames {
+		taken, err := s.userRepo.ExistsByUsername(ctx, req.Username)
+		if err != nil {
+			logger.WithError(err).Error("Failed to check username availability")
+			return nil, err
+		}
+		if taken {
+			logger.WithField("username", req.Username).Warn("Username is already taken")
+			return nil, errs.ErrUsernameTaken
+		}
+	}
+
+	// Hashing is the CPU-bound part of registration; excess callers wait for a slot or are
+	// turned away instead of starving other RPCs
+	if !s.hashSemaphore.Acquire(ctx) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		logger.Warn("Too many concurrent password hashes, rejecting registration")
+		return nil, errs.ErrServerBusy
+	}
+	user, err := domain.NewUserWithPassword(
+		s.passwordHasher,
+		req.Email,
+		req.Password,
+		req.Username,
+		req.CountryCode,
+		req.Phone,
+	)
+	s.hashSemaphore.Release()
+	if err != nil {
+		logger.WithError(err).Error("Failed to create user with password")
+		return nil, err
+	}
+
+	// A user registering with both an email and a phone gets the requested primary
+	// contact or else the configured one; with a single contact it is that one
+	primary := domain.ContactMethod(s.config.Registration.PrimaryContact)
+	if req.PrimaryContact != nil && *req.PrimaryContact != "" {
+		primary = domain.ContactMethod(*req.PrimaryContact)
+	}
+	if user.HasContact(primary) {
+		user.PrimaryContact = primary
+	}
+
+	return user, nil
+}
+
+// NotificationStats reports the notification events dropped since startup because
+// recording them failed while worker.notification.strict was off
+func (s *UserService) NotificationStats() dto.NotificationStats {
+	return dto.NotificationStats{Dropped: s.droppedNotifications.Load()}
+}
+
 // Login handles user login
 func (s *UserService) Login(ctx context.Context, req dto.LoginReq) (*dto.LoginResp, error) {
 	// Get logger from context
@@ -160,10 +422,27 @@ func (s *UserService) Login(ctx context.Context, req dto.LoginReq) (*dto.LoginRe
 
 	logger.Info("Starting user login")
 
-	// Validate email is provided
-	if req.Email == "" {
-		logger.Error("Email is required for login")
-		return nil, errs.ErrEmailIsRequired
+	// Users sign in with either their email or their phone, normalized like at
+	// registration so differently typed forms of it find the same user
+	req.Normalize(s.config.Phone.DefaultDialingCode)
+	if err := req.Identifier().Validate(); err != nil {
+		logger.WithError(err).Error("Login requires exactly one of email or a valid country code and phone")
+		return nil, err
+	}
+
+	// Attempts are limited per client IP to slow down credential stuffing
+	clientIP, _ := cx.GetClientIP(ctx)
+	if !s.loginLimiter.Allow(ctx, clientIP) {
+		logger.WithField("client_ip", clientIP).Warn("Login rate limit exceeded")
+		return nil, errs.NewRateLimitError(s.config.RateLimit.Login.Limit, s.config.RateLimit.Login.Window)
+	}
+
+	// Attempts are also limited per account, so one password guessed from many IPs is
+	// slowed down too. The account is keyed by a hash so keys never hold an email or phone.
+	accountKey := loginAccountKey(requestTenantID(ctx), req.Identifier())
+	if !s.accountLoginLimiter.Allow(ctx, accountKey) {
+		logger.WithField("account_key", accountKey).Warn("Login rate limit exceeded for account")
+		return nil, errs.NewRateLimitError(s.config.RateLimit.LoginAccount.Limit, s.config.RateLimit.LoginAccount.Window)
 	}
 
 	user, err := s.authenticateUser(ctx, req, logger)
@@ -171,63 +450,146 @@ func (s *UserService) Login(ctx context.Context, req dto.LoginReq) (*dto.LoginRe
 		return nil, err
 	}
 
-	accessToken, refreshToken, err := s.createTokenPair(user, logger)
+	// Users with two-factor authentication get a challenge instead of tokens
+	if user.TOTPEnabled {
+		return s.createMFAChallenge(ctx, user, logger)
+	}
+
+	return s.issueLoginTokens(ctx, user, req.Device, logger)
+}
+
+// loginAccountKey returns the per-account login limiter key of identifier in tenantID:
+// the SHA-256 of the tenant and the normalized email or the country code and phone.
+// Normalizing first keeps case or whitespace variants of an email from getting their own
+// counter; the tenant keeps the same identifier in two tenants, which are two accounts,
+// from sharing one.
+func loginAccountKey(tenantID string, identifier dto.Identifier) string {
+	var account string
+	if identifier.HasEmail() {
+		account = "email:" + domain.NormalizeEmail(*identifier.Email)
+	} else {
+		account = "phone:" + domain.NormalizeCountryCode(*identifier.CountryCode) + domain.NormalizePhone(*identifier.Phone, "")
+	}
+	sum := sha256.Sum256([]byte(tenantID + ":" + account))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueLoginTokens finishes a successful login: it creates and stores the token pair,
+// bound to the fingerprint of device, and records the login notification
+func (s *UserService) issueLoginTokens(ctx context.Context, user *domain.User, device dto.ClientDevice, logger *logrus.Entry) (*dto.LoginResp, error) {
+	issuedAt := time.Now()
+	accessToken, refreshToken, accessPayload, err := s.createTokenPair(ctx, user, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.storeRefreshToken(ctx, user, refreshToken, logger); err != nil {
+	refreshTokenExpiresAt := domain.FromTime(issuedAt.Add(s.config.JWT.RefreshTokenDuration))
+	if err := s.storeRefreshToken(ctx, user, refreshToken, device.Fingerprint(), refreshTokenExpiresAt, logger); err != nil {
 		return nil, err
 	}
 
 	s.logLoginSuccess(user, logger)
 
+	// The notification is a side effect of an already authenticated login, so by
+	// default a failure to record it does not withhold the issued tokens
 	if err := s.createLoginNotification(ctx, user, logger); err != nil {
-		return nil, err
+		if s.config.Worker.Notification.Strict {
+			return nil, err
+		}
+		dropped := s.droppedNotifications.Add(1)
+		logger.WithError(err).WithField("dropped_notifications", dropped).Warn("Continuing login without a login notification")
+	}
+
+	passwordExpired := user.PasswordExpired(s.config.Password.MaxAge, issuedAt)
+	if passwordExpired {
+		logger.WithField("user_id", user.ID.String()).Warn("User signed in with an expired password")
 	}
 
 	return &dto.LoginResp{
-		User:         user,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		User:                  user,
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiresAt().UnixMilli(),
+		RefreshTokenExpiresAt: refreshTokenExpiresAt.Millis(),
+		PasswordExpired:       passwordExpired,
 	}, nil
 }
 
 func (s *UserService) authenticateUser(ctx context.Context, req dto.LoginReq, logger *logrus.Entry) (*domain.User, error) {
-	logger.Debug("Retrieving user by email")
-	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	logger.Debug("Retrieving user by identifier")
+	user, err := s.userRepo.GetByIdentifier(ctx, req.Identifier())
 	if err != nil {
-		logger.WithError(err).Error("Failed to retrieve user by email")
+		// Report an unknown account exactly like a wrong password so the response
+		// does not reveal which emails or phones are registered
+		if errors.Is(err, errs.ErrUserNotFound) {
+			logger.Warn("Login attempted for unknown account")
+			verifyDummyPassword(s.passwordHasher, req.Password)
+			return nil, errs.ErrInvalidCredentials
+		}
+		logger.WithError(err).Error("Failed to retrieve user by identifier")
 		return nil, err
 	}
 
 	logger.WithField("user_id", user.ID.String()).Debug("Verifying password")
-	if !user.PasswordHash.VerifyPassword(req.Password) {
-		logger.WithFields(logrus.Fields{
+	if !user.PasswordHash.VerifyPassword(s.passwordHasher, req.Password) {
+		logger.WithFields(withUserEmail(logrus.Fields{
 			"user_id": user.ID.String(),
-			"email":   user.Email.String(),
-		}).Warn("Invalid password provided")
+		}, user)).Warn("Invalid password provided")
 		return nil, errs.ErrInvalidCredentials
 	}
 
+	// Checked after the password so that only the account owner learns its status
+	if ok, err := user.CanLogin(); !ok {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"user_id": user.ID.String(),
+			"status":  user.Status.String(),
+		}).Warn("Account is not allowed to sign in")
+		return nil, err
+	}
+
 	return user, nil
 }
 
-func (s *UserService) createTokenPair(user *domain.User, logger *logrus.Entry) (string, string, error) {
+// dummyPasswordHash is a hash of a throwaway password generated with the same bcrypt
+// cost as real accounts, so comparing against it costs the same as a real comparison
+var dummyPasswordHash = sync.OnceValue(func() domain.PasswordHash {
+	hash, err := domain.NewPasswordHashFromPlain(nil, uuid.NewString())
+	if err != nil {
+		logutils.WithError(err).Error("Failed to generate dummy password hash")
+	}
+	return hash
+})
+
+// verifyDummyPassword runs a password comparison whose result is discarded. Login for
+// an unknown account calls it so its timing matches a wrong password for a real one.
+var verifyDummyPassword = func(hasher *password.Hasher, plainPassword string) {
+	dummyPasswordHash().VerifyPassword(hasher, plainPassword)
+}
+
+func (s *UserService) createTokenPair(ctx context.Context, user *domain.User, logger *logrus.Entry) (string, string, *token.Payload, error) {
 	logger.WithField("user_id", user.ID.String()).Debug("Creating token pair")
-	accessToken, refreshToken, err := s.tokenMaker.CreateTokenPair(
+	accessToken, refreshToken, accessPayload, err := s.tokenMaker.CreateTokenPair(
 		user.ID.String(),
 		user.Username.String(),
-		int64(s.config.JWT.AccessTokenDuration),
+		user.Role.String(),
+		requestTenantID(ctx),
+		s.config.JWT.AccessTokenDuration,
 	)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create token pair")
-		return "", "", err
+		return "", "", nil, err
 	}
-	return accessToken, refreshToken, nil
+	return accessToken, refreshToken, accessPayload, nil
+}
+
+// requestTenantID returns the tenant of the request, empty when tenancy is disabled.
+// Issued tokens are bound to it so AuthInterceptor rejects them in any other tenant.
+func requestTenantID(ctx context.Context) string {
+	tenantID, _ := cx.GetTenantID(ctx)
+	return tenantID
 }
 
-func (s *UserService) storeRefreshToken(ctx context.Context, user *domain.User, refreshToken string, logger *logrus.Entry) error {
+func (s *UserService) storeRefreshToken(ctx context.Context, user *domain.User, refreshToken, fingerprint string, expiresAt domain.Timestamp, logger *logrus.Entry) error {
 	logger.Debug("Starting database transaction")
 	return s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
 		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
@@ -236,7 +598,8 @@ func (s *UserService) storeRefreshToken(ctx context.Context, user *domain.User,
 		refreshTokenModel, err := domain.NewRefreshToken(
 			user.ID,
 			refreshToken,
-			time.Now().Add(s.config.JWT.RefreshTokenDuration).UnixMilli(),
+			fingerprint,
+			expiresAt,
 		)
 		if err != nil {
 			logger.WithError(err).Error("Failed to create refresh token model")
@@ -255,13 +618,10 @@ func (s *UserService) storeRefreshToken(ctx context.Context, user *domain.User,
 }
 
 func (s *UserService) logLoginSuccess(user *domain.User, logger *logrus.Entry) {
-	logFields := logrus.Fields{
+	logFields := withUserEmail(logrus.Fields{
 		"user_id":  user.ID.String(),
 		"username": user.Username.String(),
-	}
-	if user.Email != nil {
-		logFields["email"] = user.Email.String()
-	}
+	}, user)
 	if user.CountryCode != nil && user.Phone != nil {
 		logFields["country_code"] = *user.CountryCode
 		logFields["phone"] = *user.Phone
@@ -269,13 +629,32 @@ func (s *UserService) logLoginSuccess(user *domain.User, logger *logrus.Entry) {
 	logger.WithFields(logFields).Info("User login completed successfully")
 }
 
+// withUserEmail adds the user's email to fields. Phone-only accounts have no email, so
+// user.Email must never be dereferenced unguarded.
+func withUserEmail(fields logrus.Fields, user *domain.User) logrus.Fields {
+	if user.Email != nil {
+		fields["email"] = user.Email.String()
+	}
+	return fields
+}
+
 func (s *UserService) createLoginNotification(ctx context.Context, user *domain.User, logger *logrus.Entry) error {
+	if !s.notificationEnabled(ctx, user.ID, events.LoginEventType, logger) {
+		logger.Debug("Login notifications are disabled for the user")
+		return nil
+	}
+
 	notificationParams := dto.SendLoginNotificationParams{
 		UserID:   user.ID.String(),
 		Username: user.Username.String(),
 		LoginAt:  time.Now(),
 	}
-	if user.Email != nil {
+	// The notification goes to the primary contact only, so a user with both an email
+	// and a phone is not notified twice
+	if user.PrimaryContact == domain.ContactMethodPhone && user.HasContact(domain.ContactMethodPhone) {
+		notificationParams.CountryCode = user.CountryCode.ToPtrString()
+		notificationParams.Phone = user.Phone.ToPtrString()
+	} else if user.Email != nil {
 		email := user.Email.String()
 		notificationParams.Email = &email
 	}
@@ -298,23 +677,151 @@ func (s *UserService) createLoginNotification(ctx context.Context, user *domain.
 	return nil
 }
 
+// RefreshToken exchanges a refresh token for a new access token. The refresh token is
+// kept and keeps its expiry; RotateRefreshToken replaces it as well.
 func (s *UserService) RefreshToken(ctx context.Context, req dto.RefreshTokenReq) (*dto.RefreshTokenResp, error) {
 	// Get logger from context
 	logger := logutils.GetLoggerOrDefault(ctx)
 
 	logger.Info("Starting token refresh")
 
+	refreshToken, err := s.checkRefreshToken(ctx, req.RefreshToken, req.Device, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("user_id", refreshToken.UserID.String()).Debug("Retrieving user by ID")
+	user, err := s.userRepo.GetByID(ctx, refreshToken.UserID)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", refreshToken.UserID.String()).Error("Failed to retrieve user by ID")
+		return nil, err
+	}
+
+	logger.WithField("user_id", user.ID.String()).Debug("Creating new access token")
+	accessToken, accessPayload, err := s.tokenMaker.CreateAccessToken(
+		user.ID.String(),
+		user.Username.String(),
+		user.Role.String(),
+		requestTenantID(ctx),
+		s.config.JWT.AccessTokenDuration,
+	)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create access token")
+		return nil, err
+	}
+
+	logger.WithFields(withUserEmail(logrus.Fields{
+		"user_id":  user.ID.String(),
+		"username": user.Username.String(),
+		"token_id": refreshToken.ID.String(),
+	}, user)).Info("Token refresh completed successfully")
+
+	return &dto.RefreshTokenResp{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiresAt().UnixMilli(),
+	}, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new access token and refresh token
+// pair, e.g. when an app resumes, without signing in again. The presented token is
+// revoked and its successor joins its family, so replaying it later revokes the
+// successor as well.
+func (s *UserService) RotateRefreshToken(ctx context.Context, req dto.RotateRefreshReq) (*dto.RotateRefreshResp, error) {
+	// Get logger from context
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	logger.Info("Starting refresh token rotation")
+
+	refreshToken, err := s.checkRefreshToken(ctx, req.RefreshToken, req.Device, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithField("user_id", refreshToken.UserID.String()).Debug("Retrieving user by ID")
+	user, err := s.userRepo.GetByID(ctx, refreshToken.UserID)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", refreshToken.UserID.String()).Error("Failed to retrieve user by ID")
+		return nil, err
+	}
+
+	issuedAt := time.Now()
+	accessToken, newRefreshToken, accessPayload, err := s.createTokenPair(ctx, user, logger)
+	if err != nil {
+		return nil, err
+	}
+	refreshTokenExpiresAt := domain.FromTime(issuedAt.Add(s.config.JWT.RefreshTokenDuration))
+
+	successor, err := refreshToken.Rotate(newRefreshToken, refreshTokenExpiresAt)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create refresh token model")
+		return nil, err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(txWrapper *tx.TxWrapper) error {
+		txCtx := context.WithValue(ctx, cx.TransactionContextKey, txWrapper.GetTx())
+
+		if err := s.refreshTokenRepo.MarkReplaced(txCtx, refreshToken.ID, successor.ID); err != nil {
+			return err
+		}
+
+		if err := s.refreshTokenRepo.Create(txCtx, successor); err != nil {
+			logger.WithError(err).Error("Failed to store refresh token in database")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		// A concurrent request rotated or revoked the token after it was checked, so
+		// it was presented twice
+		if errors.Is(err, errs.ErrTokenNotFound) {
+			s.revokeReplayedFamily(ctx, logger, refreshToken)
+			return nil, errs.ErrTokenRevoked
+		}
+
+		logger.WithError(err).Error("Failed to rotate refresh token")
+		return nil, err
+	}
+
+	logger.WithFields(withUserEmail(logrus.Fields{
+		"user_id":      user.ID.String(),
+		"username":     user.Username.String(),
+		"token_id":     refreshToken.ID.String(),
+		"new_token_id": successor.ID.String(),
+		"family_id":    successor.FamilyID.String(),
+	}, user)).Info("Refresh token rotation completed successfully")
+
+	return &dto.RotateRefreshResp{
+		AccessToken:           accessToken,
+		RefreshToken:          newRefreshToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiresAt().UnixMilli(),
+		RefreshTokenExpiresAt: refreshTokenExpiresAt.Millis(),
+	}, nil
+}
+
+// checkRefreshToken looks up a presented refresh token and checks that it can be
+// exchanged: it must not be rotated, revoked or expired, and must match the device it was
+// issued to when binding is enabled. Failures count towards the refresh failure limits.
+func (s *UserService) checkRefreshToken(ctx context.Context, token string, device dto.ClientDevice, logger *logrus.Entry) (*domain.RefreshToken, error) {
 	// Validate refresh token is provided
-	if req.RefreshToken == "" {
+	if token == "" {
 		logger.Error("Refresh token is required")
 		return nil, errs.ErrTokenIsRequired
 	}
 
+	// Refresh tokens cannot be guessed, so repeated failures from one client signal
+	// enumeration or replay of stolen tokens; such clients are throttled
+	clientIP, _ := cx.GetClientIP(ctx)
+	if s.refreshFailureLimiter.Exceeded(ctx, refreshFailureIPKey(clientIP)) {
+		s.logRefreshThrottled(ctx, logger, clientIP, "")
+		return nil, errs.NewRateLimitError(s.config.RateLimit.RefreshToken.Limit, s.config.RateLimit.RefreshToken.Window)
+	}
+
 	logger.Debug("Retrieving refresh token from database")
-	refreshToken, err := s.refreshTokenRepo.GetByToken(ctx, req.RefreshToken)
+	refreshToken, err := s.refreshTokenRepo.GetByToken(ctx, token)
 	if err != nil {
 		if err == errs.ErrTokenNotFound {
 			logger.Warn("Refresh token not found in database")
+			s.recordRefreshFailure(ctx, clientIP, "", logger)
 			return nil, errs.ErrTokenNotFound
 		}
 
@@ -329,50 +836,158 @@ func (s *UserService) RefreshToken(ctx context.Context, req dto.RefreshTokenReq)
 		"is_revoked": refreshToken.IsRevoked,
 	}).Debug("Retrieved refresh token")
 
+	userKey := refreshToken.UserID.String()
+	if s.refreshFailureLimiter.Exceeded(ctx, refreshFailureUserKey(userKey)) {
+		s.logRefreshThrottled(ctx, logger, clientIP, userKey)
+		return nil, errs.NewRateLimitError(s.config.RateLimit.RefreshToken.Limit, s.config.RateLimit.RefreshToken.Window)
+	}
+
+	if refreshToken.IsRotated() {
+		s.revokeReplayedFamily(ctx, logger, refreshToken)
+		s.recordRefreshFailure(ctx, clientIP, userKey, logger)
+		return nil, errs.ErrTokenRevoked
+	}
+
 	if refreshToken.IsRevoked {
 		logger.WithFields(logrus.Fields{
 			"token_id": refreshToken.ID.String(),
 			"user_id":  refreshToken.UserID.String(),
 		}).Warn("Refresh token is revoked")
+		s.recordRefreshFailure(ctx, clientIP, userKey, logger)
 		return nil, errs.ErrTokenRevoked
 	}
 
-	if refreshToken.ExpiresAt < time.Now().UnixMilli() {
+	if now := domain.Now(); refreshToken.ExpiresAt.Before(now) {
 		logger.WithFields(logrus.Fields{
 			"token_id":     refreshToken.ID.String(),
 			"user_id":      refreshToken.UserID.String(),
 			"expires_at":   refreshToken.ExpiresAt,
-			"current_time": time.Now().UnixMilli(),
+			"current_time": now,
 		}).Warn("Refresh token has expired")
+		s.recordRefreshFailure(ctx, clientIP, userKey, logger)
 		return nil, errs.ErrTokenExpired
 	}
 
-	logger.WithField("user_id", refreshToken.UserID.String()).Debug("Retrieving user by ID")
-	user, err := s.userRepo.GetByID(ctx, refreshToken.UserID)
-	if err != nil {
-		logger.WithError(err).WithField("user_id", refreshToken.UserID.String()).Error("Failed to retrieve user by ID")
-		return nil, err
+	if s.config.JWT.BindRefreshTokens && !refreshToken.MatchesFingerprint(device.Fingerprint()) {
+		logger.WithFields(logrus.Fields{
+			"token_id": refreshToken.ID.String(),
+			"user_id":  refreshToken.UserID.String(),
+		}).Warn("Refresh token presented from a different device")
+		s.recordRefreshFailure(ctx, clientIP, userKey, logger)
+		return nil, errs.ErrInvalidToken
 	}
 
-	logger.WithField("user_id", user.ID.String()).Debug("Creating new access token")
-	accessToken, err := s.tokenMaker.CreateAccessToken(
-		user.ID.String(),
-		user.Username.String(),
-		int64(s.config.JWT.AccessTokenDuration),
-	)
+	return refreshToken, nil
+}
+
+// revokeReplayedFamily revokes the whole family of a refresh token that was presented
+// after being rotated. Only one party should hold the latest token of a family, so a
+// replayed one means it leaked and no token of the family can be trusted anymore.
+func (s *UserService) revokeReplayedFamily(ctx context.Context, logger *logrus.Entry, refreshToken *domain.RefreshToken) {
+	fields := logrus.Fields{
+		"token_id":  refreshToken.ID.String(),
+		"user_id":   refreshToken.UserID.String(),
+		"family_id": refreshToken.FamilyID.String(),
+	}
+
+	revoked, err := s.refreshTokenRepo.RevokeFamily(ctx, refreshToken.FamilyID)
 	if err != nil {
-		logger.WithError(err).Error("Failed to create access token")
-		return nil, err
+		logger.WithError(err).WithFields(fields).Error("Failed to revoke refresh token family")
+	} else {
+		fields["revoked_tokens"] = revoked
+	}
+
+	logger.WithFields(fields).Warn("Rotated refresh token replayed, revoked its family")
+	s.auditLogger.Log(ctx, audit.Event{
+		Action: audit.ActionRefreshToken,
+		UserID: refreshToken.UserID.String(),
+		Reason: "rotated refresh token replayed",
+		Fields: fields,
+	})
+}
+
+func refreshFailureIPKey(clientIP string) string {
+	return "ip:" + clientIP
+}
+
+func refreshFailureUserKey(userID string) string {
+	return "user:" + userID
+}
+
+// recordRefreshFailure counts a failed refresh against the client IP and, when the token
+// was found, against its user. Crossing the limit is logged for the security team.
+func (s *UserService) recordRefreshFailure(ctx context.Context, clientIP, userID string, logger *logrus.Entry) {
+	keys := []string{refreshFailureIPKey(clientIP)}
+	if userID != "" {
+		keys = append(keys, refreshFailureUserKey(userID))
+	}
+
+	for _, key := range keys {
+		if !s.refreshFailureLimiter.Allow(ctx, key) {
+			logger.WithFields(logrus.Fields{
+				"client_ip":   clientIP,
+				"user_id":     userID,
+				"limiter_key": key,
+			}).Warn("Suspicious refresh token activity: failed refresh limit reached")
+		}
 	}
+}
 
+// logRefreshThrottled records a refresh rejected for too many earlier failures
+func (s *UserService) logRefreshThrottled(ctx context.Context, logger *logrus.Entry, clientIP, userID string) {
 	logger.WithFields(logrus.Fields{
-		"user_id":  user.ID.String(),
-		"email":    user.Email.String(),
-		"username": user.Username.String(),
-		"token_id": refreshToken.ID.String(),
-	}).Info("Token refresh completed successfully")
+		"client_ip": clientIP,
+		"user_id":   userID,
+	}).Warn("Refresh token attempts throttled after repeated failures")
+	s.auditLogger.Log(ctx, audit.Event{
+		Action: audit.ActionRefreshToken,
+		UserID: userID,
+		Reason: "too many failed refresh attempts",
+	})
+}
 
-	return &dto.RefreshTokenResp{
-		AccessToken: accessToken,
-	}, nil
+// VerifyPassword checks a plaintext password against the stored hash of a user without
+// loading the full user. It backs "confirm your password" flows before sensitive
+// operations, so attempts are rate limited per user and failures are audited.
+func (s *UserService) VerifyPassword(ctx context.Context, userID uuid.UUID, plaintext string) (bool, error) {
+	logger := logutils.GetLoggerOrDefault(ctx).WithField("user_id", userID.String())
+
+	if !s.verifyPasswordLimiter.Allow(ctx, userID.String()) {
+		logger.Warn("Password verification rate limit exceeded")
+		s.auditLogger.Log(ctx, audit.Event{
+			Action: audit.ActionVerifyPassword,
+			UserID: userID.String(),
+			Reason: "rate limited",
+		})
+		return false, errs.NewRateLimitError(s.config.RateLimit.VerifyPassword.Limit, s.config.RateLimit.VerifyPassword.Window)
+	}
+
+	passwordHash, err := s.userRepo.GetPasswordHash(ctx, userID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to retrieve password hash")
+		return false, err
+	}
+
+	if !passwordHash.VerifyPassword(s.passwordHasher, plaintext) {
+		logger.Warn("Password verification failed")
+		s.auditLogger.Log(ctx, audit.Event{
+			Action: audit.ActionVerifyPassword,
+			UserID: userID.String(),
+			Reason: "password mismatch",
+		})
+		return false, nil
+	}
+
+	logger.Debug("Password verified")
+	return true, nil
+}
+
+// parseUserID parses a user ID taken from a request. A malformed or nil ID is reported
+// as errs.ErrInvalidID instead of surfacing later as a confusing NotFound or Internal.
+func parseUserID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, errs.ErrInvalidID
+	}
+	return id, nil
 }
@@ -0,0 +1,1158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/config"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/internal/app/repository"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/crypt/password"
+	"wallet-user-svc/pkg/utils/crypt/token"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+	"wallet-user-svc/pkg/utils/ratelimit"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stubUserRepository struct {
+	UserRepository
+	usersByEmail map[string]*domain.User
+	usersByID    map[uuid.UUID]*domain.User
+	usersByPhone map[string]*domain.User
+	// totpSteps holds the last accepted TOTP step per user
+	totpSteps map[uuid.UUID]int64
+}
+
+func (r *stubUserRepository) GetByID(_ context.Context, id uuid.UUID) (*domain.User, error) {
+	user, ok := r.usersByID[id]
+	if !ok {
+		return nil, errs.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *stubUserRepository) EnableTOTP(_ context.Context, id uuid.UUID) error {
+	user, ok := r.usersByID[id]
+	if !ok {
+		return errs.ErrUserNotFound
+	}
+	user.TOTPEnabled = true
+	return nil
+}
+
+func (r *stubUserRepository) ConsumeTOTPStep(_ context.Context, id uuid.UUID, step int64) (bool, error) {
+	if last, ok := r.totpSteps[id]; ok && last >= step {
+		return false, nil
+	}
+	if r.totpSteps == nil {
+		r.totpSteps = make(map[uuid.UUID]int64)
+	}
+	r.totpSteps[id] = step
+	return true, nil
+}
+
+func (r *stubUserRepository) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	user, ok := r.usersByEmail[email]
+	if !ok {
+		return nil, errs.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *stubUserRepository) GetByPhone(_ context.Context, countryCode, phone string) (*domain.User, error) {
+	user, ok := r.usersByPhone[countryCode+phone]
+	if !ok {
+		return nil, errs.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *stubUserRepository) UpdateContact(_ context.Context, user *domain.User) error {
+	if _, ok := r.usersByID[user.ID]; !ok {
+		return errs.ErrUserNotFound
+	}
+	if user.Email != nil {
+		if r.usersByEmail == nil {
+			r.usersByEmail = make(map[string]*domain.User)
+		}
+		r.usersByEmail[user.Email.String()] = user
+	}
+	if user.CountryCode != nil && user.Phone != nil {
+		if r.usersByPhone == nil {
+			r.usersByPhone = make(map[string]*domain.User)
+		}
+		r.usersByPhone[string(*user.CountryCode)+user.Phone.String()] = user
+	}
+	return nil
+}
+
+func (r *stubUserRepository) SetPrimaryContact(_ context.Context, id uuid.UUID, method domain.ContactMethod) error {
+	user, ok := r.usersByID[id]
+	if !ok {
+		return errs.ErrUserNotFound
+	}
+	user.PrimaryContact = method
+	return nil
+}
+
+func (r *stubUserRepository) GetByIdentifier(ctx context.Context, identifier dto.Identifier) (*domain.User, error) {
+	if err := identifier.Validate(); err != nil {
+		return nil, err
+	}
+	if identifier.Phone != nil && *identifier.Phone != "" {
+		user, ok := r.usersByPhone[*identifier.CountryCode+*identifier.Phone]
+		if !ok {
+			return nil, errs.ErrUserNotFound
+		}
+		return user, nil
+	}
+	if identifier.Email == nil || *identifier.Email == "" {
+		return nil, errs.ErrUserNotFound
+	}
+	return r.GetByEmail(ctx, *identifier.Email)
+}
+
+func (r *stubUserRepository) Create(_ context.Context, user *domain.User) error {
+	if r.usersByID == nil {
+		r.usersByID = make(map[uuid.UUID]*domain.User)
+	}
+	r.usersByID[user.ID] = user
+	return nil
+}
+
+func (r *stubUserRepository) ExistsByUsername(_ context.Context, username string) (bool, error) {
+	for _, user := range r.usersByID {
+		if strings.EqualFold(user.Username.String(), username) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newRegisterTestService(uniqueUsernames bool) (*UserService, *stubUserRepository) {
+	userRepo := &stubUserRepository{}
+	service := &UserService{
+		config: &config.Config{
+			JWT:          config.JWTConfig{AccessTokenDuration: time.Minute, RefreshTokenDuration: time.Hour},
+			Registration: config.RegistrationConfig{Enabled: true, UniqueUsernames: uniqueUsernames},
+		},
+		userRepo:              userRepo,
+		refreshTokenRepo:      stubRefreshTokenRepository{},
+		txManager:             stubTxManager{},
+		tokenMaker:            token.NewJWTTokenMaker("test-secret-key-with-enough-length", 0),
+		notificationPrefRepo:  &memoryNotificationPreferenceRepository{},
+		refreshFailureLimiter: ratelimit.NewLimiter(0, 0),
+	}
+	return service, userRepo
+}
+
+func TestRegister_UsernameTaken(t *testing.T) {
+	service, _ := newRegisterTestService(true)
+
+	_, err := service.Register(context.Background(), dto.RegisterReq{Username: "testuser", Password: "Password123!", Email: stringPtr("first@example.com")})
+	require.NoError(t, err)
+
+	_, err = service.Register(context.Background(), dto.RegisterReq{Username: "TestUser", Password: "Password123!", Email: stringPtr("second@example.com")})
+	assert.ErrorIs(t, err, errs.ErrUsernameTaken)
+	assert.Equal(t, codes.AlreadyExists, status.Code(errs.ToGRPCError(err)))
+}
+
+func TestRegister_HashingBusy(t *testing.T) {
+	service, _ := newRegisterTestService(false)
+	service.hashSemaphore = ratelimit.NewSemaphore(1, 0)
+	require.True(t, service.hashSemaphore.Acquire(context.Background()))
+
+	req := dto.RegisterReq{Username: "testuser", Password: "Password123!", Email: stringPtr("user@example.com")}
+	_, err := service.Register(context.Background(), req)
+	assert.ErrorIs(t, err, errs.ErrServerBusy)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(errs.ToGRPCError(err)))
+
+	service.hashSemaphore.Release()
+	_, err = service.Register(context.Background(), req)
+	assert.NoError(t, err, "a freed hashing slot admits the next registration")
+}
+
+func TestRegister_PrimaryContact(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		email      *string
+		requested  *string
+		want       domain.ContactMethod
+	}{
+		{name: "configured default", configured: "email", email: stringPtr("both@example.com"), want: domain.ContactMethodEmail},
+		{name: "configured phone", configured: "phone", email: stringPtr("both@example.com"), want: domain.ContactMethodPhone},
+		{name: "requested phone", configured: "email", email: stringPtr("both@example.com"), requested: stringPtr("phone"), want: domain.ContactMethodPhone},
+		{name: "requested email", configured: "phone", email: stringPtr("both@example.com"), requested: stringPtr("email"), want: domain.ContactMethodEmail},
+		{name: "phone only", configured: "email", want: domain.ContactMethodPhone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newRegisterTestService(false)
+			service.config.Registration.PrimaryContact = tt.configured
+
+			resp, err := service.Register(context.Background(), dto.RegisterReq{
+				Username:       "testuser",
+				Password:       "Password123!",
+				Email:          tt.email,
+				CountryCode:    stringPtr("TW"),
+				Phone:          stringPtr("+886912345678"),
+				PrimaryContact: tt.requested,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.User.PrimaryContact)
+		})
+	}
+}
+
+func TestCreateLoginNotification_PrimaryContact(t *testing.T) {
+	user, err := domain.NewUserWithPassword(nil, stringPtr("both@example.com"), "Password123!", "testuser", stringPtr("TW"), stringPtr("+886912345678"))
+	require.NoError(t, err)
+	logger := logutils.GetLoggerOrDefault(context.Background())
+
+	for _, method := range []domain.ContactMethod{domain.ContactMethodEmail, domain.ContactMethodPhone} {
+		require.NoError(t, user.SetPrimaryContact(method))
+		eventLogRepo := &recordingNotificationEventLogRepository{}
+		service := &UserService{notificationEventLogRepo: eventLogRepo, notificationPrefRepo: &memoryNotificationPreferenceRepository{}}
+
+		require.NoError(t, service.createLoginNotification(context.Background(), user, logger))
+		require.Len(t, eventLogRepo.events, 1)
+		var params dto.SendLoginNotificationParams
+		require.NoError(t, json.Unmarshal(eventLogRepo.events[0].Payload, &params))
+
+		if method == domain.ContactMethodEmail {
+			assert.Equal(t, stringPtr("both@example.com"), params.Email)
+			assert.Nil(t, params.Phone, "only the primary contact is notified")
+		} else {
+			assert.Nil(t, params.Email, "only the primary contact is notified")
+			assert.Equal(t, stringPtr("TW"), params.CountryCode)
+			assert.Equal(t, stringPtr("+886912345678"), params.Phone)
+		}
+	}
+}
+
+// discardUserRepository accepts every new user without keeping it, so it is safe for
+// concurrent use
+type discardUserRepository struct {
+	UserRepository
+}
+
+func (discardUserRepository) Create(context.Context, *domain.User) error {
+	return nil
+}
+
+// BenchmarkRegistrationFlood measures the latency of a cheap RPC while registrations
+// hash passwords in a loop; compare the mean and tail latencies of the two
+// sub-benchmarks. Bounding the concurrent hashes parks the excess registrations instead
+// of leaving them runnable, so the scheduler gets to other requests sooner.
+func BenchmarkRegistrationFlood(b *testing.B) {
+	logutils.GetLoggerOrDefault(context.Background()).Logger.SetLevel(logrus.ErrorLevel)
+	procs := runtime.GOMAXPROCS(0)
+
+	benchmarks := []struct {
+		name      string
+		semaphore *ratelimit.Semaphore
+	}{
+		{name: "Unbounded"},
+		{name: "Bounded", semaphore: ratelimit.NewSemaphore(max(procs/2, 1), time.Minute)},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			service, _ := newRegisterTestService(false)
+			service.userRepo = discardUserRepository{}
+			service.hashSemaphore = bm.semaphore
+
+			floodCtx, stopFlood := context.WithCancel(context.Background())
+			var wg sync.WaitGroup
+			for i := 0; i < 4*procs; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for floodCtx.Err() == nil {
+						_, _ = service.Register(floodCtx, dto.RegisterReq{Username: "flooduser", Password: "Password123!", Email: stringPtr("flood@example.com")})
+					}
+				}()
+			}
+			defer wg.Wait()
+			defer stopFlood()
+
+			ctx := cx.WithAuthUserID(context.Background(), uuid.New())
+			latencies := make([]time.Duration, b.N)
+			b.ResetTimer()
+			for i := range latencies {
+				start := time.Now()
+				_, _ = service.GetNotificationPreferences(ctx)
+				latencies[i] = time.Since(start)
+			}
+			b.StopTimer()
+
+			slices.Sort(latencies)
+			b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-ns")
+			b.ReportMetric(float64(latencies[len(latencies)*999/1000].Nanoseconds()), "p99.9-ns")
+		})
+	}
+}
+
+// uniqueEmailUserRepository rejects a second user with the same email the way the
+// repository maps the unique index violation, and is safe for concurrent use
+type uniqueEmailUserRepository struct {
+	UserRepository
+	mu     sync.Mutex
+	emails map[string]bool
+}
+
+func (r *uniqueEmailUserRepository) Create(_ context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.emails[user.Email.String()] {
+		return errs.ErrUserExists
+	}
+	r.emails[user.Email.String()] = true
+	return nil
+}
+
+func TestRegister_ConcurrentSameEmail(t *testing.T) {
+	service, _ := newRegisterTestService(false)
+	service.userRepo = &uniqueEmailUserRepository{emails: make(map[string]bool)}
+	// A context logger keeps the goroutines off the lazily created global logger
+	ctx := logutils.WithLogger(context.Background(), logrus.NewEntry(logrus.New()))
+
+	const attempts = 2
+	results := make(chan error, attempts)
+	var start sync.WaitGroup
+	start.Add(1)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			start.Wait()
+			_, err := service.Register(ctx, dto.RegisterReq{Username: "racer", Password: "Password123!", Email: stringPtr("race@example.com")})
+			results <- err
+		}()
+	}
+	start.Done()
+
+	var succeeded, alreadyExists int
+	for i := 0; i < attempts; i++ {
+		err := <-results
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, errs.ErrUserExists)
+		assert.Equal(t, codes.AlreadyExists, status.Code(errs.ToGRPCError(err)))
+		alreadyExists++
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, alreadyExists)
+}
+
+func TestRegister_DefaultDialingCode(t *testing.T) {
+	tests := []struct {
+		name          string
+		dialingCode   string
+		phone         string
+		expectedPhone string
+		expectedErr   error
+	}{
+		{name: "bare number gets the default", dialingCode: "+886", phone: "912345678", expectedPhone: "+886912345678"},
+		{name: "E.164 number is kept", dialingCode: "+886", phone: "+14155550123", expectedPhone: "+14155550123"},
+		{name: "bare number without a default is rejected", phone: "912345678", expectedErr: errs.ErrInvalidPhoneNumber},
+		{name: "number still invalid after the default is rejected", dialingCode: "+886", phone: "12ab", expectedErr: errs.ErrInvalidPhoneNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newRegisterTestService(true)
+			service.config.Phone.DefaultDialingCode = tt.dialingCode
+
+			resp, err := service.Register(context.Background(), dto.RegisterReq{
+				Username:    "testuser",
+				Password:    "Password123!",
+				CountryCode: stringPtr("TW"),
+				Phone:       stringPtr(tt.phone),
+			})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, resp.User.Phone)
+			assert.Equal(t, tt.expectedPhone, resp.User.Phone.String())
+		})
+	}
+}
+
+func TestRegister_Disabled(t *testing.T) {
+	service, userRepo := newRegisterTestService(true)
+	service.config.Registration.Enabled = false
+
+	_, err := service.Register(context.Background(), dto.RegisterReq{Username: "testuser", Password: "Password123!", Email: stringPtr("first@example.com")})
+	assert.ErrorIs(t, err, errs.ErrRegistrationDisabled)
+	assert.Empty(t, userRepo.usersByID)
+
+	st := status.Convert(errs.ToGRPCError(err))
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, "registration_disabled", st.Details()[0].(*errdetails.ErrorInfo).Reason)
+
+	service.auditLogger = nopAuditLogger{}
+	resp, err := service.AdminCreateUser(context.Background(), dto.AdminCreateUserReq{RegisterReq: dto.RegisterReq{
+		Username: "testuser",
+		Password: "Password123!",
+		Email:    stringPtr("first@example.com"),
+	}})
+	require.NoError(t, err, "admins can create users while registration is disabled")
+	assert.Contains(t, userRepo.usersByID, resp.User.ID)
+}
+
+type recordingAuditLogger struct {
+	events []audit.Event
+}
+
+func (l *recordingAuditLogger) Log(_ context.Context, event audit.Event) {
+	l.events = append(l.events, event)
+}
+
+func TestAdminCreateUser(t *testing.T) {
+	service, userRepo := newRegisterTestService(true)
+	service.config.Registration.Enabled = false
+	auditLogger := &recordingAuditLogger{}
+	service.auditLogger = auditLogger
+
+	adminID := uuid.NewString()
+	ctx := cx.WithAuthPayload(context.Background(), &token.Payload{UserID: adminID, Role: domain.RoleAdmin.String()})
+
+	resp, err := service.AdminCreateUser(ctx, dto.AdminCreateUserReq{
+		RegisterReq: dto.RegisterReq{
+			Username: "operator",
+			Password: "Password123!",
+			Email:    stringPtr("operator@example.com"),
+		},
+		Role:               "admin",
+		EmailVerified:      true,
+		MustChangePassword: true,
+	})
+	require.NoError(t, err)
+
+	created := userRepo.usersByID[resp.User.ID]
+	require.NotNil(t, created)
+	assert.Equal(t, domain.RoleAdmin, created.Role)
+	assert.True(t, created.EmailVerified)
+	assert.True(t, created.MustChangePassword)
+
+	require.Len(t, auditLogger.events, 1)
+	assert.Equal(t, audit.ActionCreateUser, auditLogger.events[0].Action)
+	assert.Equal(t, resp.User.ID.String(), auditLogger.events[0].UserID)
+	assert.Equal(t, adminID, auditLogger.events[0].Fields["created_by"])
+
+	_, err = service.AdminCreateUser(ctx, dto.AdminCreateUserReq{
+		RegisterReq: dto.RegisterReq{Username: "other", Password: "Password123!", Email: stringPtr("other@example.com")},
+		Role:        "superuser",
+	})
+	assert.ErrorIs(t, err, errs.ErrInvalidRole)
+}
+
+func TestRegister_DuplicateUsernameAllowedWhenNotUnique(t *testing.T) {
+	service, userRepo := newRegisterTestService(false)
+
+	_, err := service.Register(context.Background(), dto.RegisterReq{Username: "testuser", Password: "Password123!", Email: stringPtr("first@example.com")})
+	require.NoError(t, err)
+	_, err = service.Register(context.Background(), dto.RegisterReq{Username: "testuser", Password: "Password123!", Email: stringPtr("second@example.com")})
+	require.NoError(t, err)
+
+	assert.Len(t, userRepo.usersByID, 2)
+}
+
+type failingNotificationEventLogRepository struct{}
+
+func (failingNotificationEventLogRepository) Create(context.Context, *repository.NotificationEventLog) error {
+	return errors.New("connection refused")
+}
+
+func TestIssueLoginTokens_NotificationFailure(t *testing.T) {
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+	logger := logutils.GetLoggerOrDefault(context.Background())
+
+	for _, strict := range []bool{false, true} {
+		service, _ := newRegisterTestService(true)
+		service.config.Worker.Notification.Strict = strict
+		service.notificationEventLogRepo = failingNotificationEventLogRepository{}
+
+		resp, err := service.issueLoginTokens(context.Background(), user, dto.ClientDevice{}, logger)
+		if strict {
+			assert.Error(t, err)
+			assert.Zero(t, service.NotificationStats().Dropped, "a failed login drops no notification")
+			continue
+		}
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.AccessToken)
+		assert.Equal(t, int64(1), service.NotificationStats().Dropped)
+	}
+}
+
+// memoryRefreshTokenRepository keeps created refresh tokens so they can be refreshed
+type memoryRefreshTokenRepository struct {
+	tokens map[string]*domain.RefreshToken
+}
+
+func (r *memoryRefreshTokenRepository) Create(_ context.Context, refreshToken *domain.RefreshToken) error {
+	if r.tokens == nil {
+		r.tokens = make(map[string]*domain.RefreshToken)
+	}
+	r.tokens[refreshToken.Token] = refreshToken
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) GetByToken(_ context.Context, token string) (*domain.RefreshToken, error) {
+	refreshToken, ok := r.tokens[token]
+	if !ok {
+		return nil, errs.ErrTokenNotFound
+	}
+	return refreshToken, nil
+}
+
+func (r *memoryRefreshTokenRepository) GetByID(_ context.Context, id uuid.UUID) (*domain.RefreshToken, error) {
+	for _, refreshToken := range r.tokens {
+		if refreshToken.ID == id {
+			return refreshToken, nil
+		}
+	}
+	return nil, errs.ErrTokenNotFound
+}
+
+func (r *memoryRefreshTokenRepository) ListActiveByUserID(_ context.Context, userID uuid.UUID, now domain.Timestamp) ([]*domain.RefreshToken, error) {
+	var refreshTokens []*domain.RefreshToken
+	for _, refreshToken := range r.tokens {
+		if refreshToken.UserID == userID && !refreshToken.IsRevoked && refreshToken.ExpiresAt > now {
+			refreshTokens = append(refreshTokens, refreshToken)
+		}
+	}
+	return refreshTokens, nil
+}
+
+func (r *memoryRefreshTokenRepository) RevokeByID(ctx context.Context, id uuid.UUID) error {
+	refreshToken, err := r.GetByID(ctx, id)
+	if err != nil || refreshToken.IsRevoked {
+		return errs.ErrTokenNotFound
+	}
+	refreshToken.IsRevoked = true
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) MarkReplaced(ctx context.Context, id, replacedBy uuid.UUID) error {
+	refreshToken, err := r.GetByID(ctx, id)
+	if err != nil || refreshToken.IsRevoked || refreshToken.IsRotated() {
+		return errs.ErrTokenNotFound
+	}
+	refreshToken.IsRevoked = true
+	refreshToken.ReplacedBy = &replacedBy
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) RevokeFamily(_ context.Context, familyID uuid.UUID) (int64, error) {
+	var revoked int64
+	for _, refreshToken := range r.tokens {
+		if refreshToken.FamilyID == familyID && !refreshToken.IsRevoked {
+			refreshToken.IsRevoked = true
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *memoryRefreshTokenRepository) CountByState(_ context.Context, now domain.Timestamp) (*repository.RefreshTokenCounts, error) {
+	counts := &repository.RefreshTokenCounts{}
+	for _, refreshToken := range r.tokens {
+		addRefreshTokenCount(counts, refreshToken, now)
+	}
+	return counts, nil
+}
+
+func (r *memoryRefreshTokenRepository) CountByUser(_ context.Context, now domain.Timestamp, limit, offset int) ([]repository.UserRefreshTokenCounts, error) {
+	byUser := make(map[uuid.UUID]*repository.UserRefreshTokenCounts)
+	for _, refreshToken := range r.tokens {
+		userCounts, ok := byUser[refreshToken.UserID]
+		if !ok {
+			userCounts = &repository.UserRefreshTokenCounts{UserID: refreshToken.UserID}
+			byUser[refreshToken.UserID] = userCounts
+		}
+		addRefreshTokenCount(&userCounts.RefreshTokenCounts, refreshToken, now)
+	}
+
+	users := make([]repository.UserRefreshTokenCounts, 0, len(byUser))
+	for _, userCounts := range byUser {
+		users = append(users, *userCounts)
+	}
+	slices.SortFunc(users, func(a, b repository.UserRefreshTokenCounts) int {
+		if a.Active != b.Active {
+			return int(b.Active - a.Active)
+		}
+		return strings.Compare(a.UserID.String(), b.UserID.String())
+	})
+
+	users = users[min(offset, len(users)):]
+	return users[:min(limit, len(users))], nil
+}
+
+func addRefreshTokenCount(counts *repository.RefreshTokenCounts, refreshToken *domain.RefreshToken, now domain.Timestamp) {
+	switch {
+	case refreshToken.IsRevoked:
+		counts.Revoked++
+	case refreshToken.ExpiresAt.Before(now):
+		counts.Expired++
+	default:
+		counts.Active++
+	}
+}
+
+func TestRefreshToken_DeviceBinding(t *testing.T) {
+	service, _ := newRegisterTestService(true)
+	service.refreshTokenRepo = &memoryRefreshTokenRepository{}
+	issuedTo := dto.ClientDevice{UserAgent: "wallet-ios/2.3", DeviceID: "device-a"}
+
+	resp, err := service.Register(context.Background(), dto.RegisterReq{
+		Username: "testuser",
+		Password: "Password123!",
+		Email:    stringPtr("known@example.com"),
+		Device:   issuedTo,
+	})
+	require.NoError(t, err)
+
+	refresh := func(device dto.ClientDevice) error {
+		_, err := service.RefreshToken(context.Background(), dto.RefreshTokenReq{RefreshToken: resp.RefreshToken, Device: device})
+		return err
+	}
+	otherDevice := dto.ClientDevice{UserAgent: "wallet-ios/2.3", DeviceID: "device-b"}
+	otherUserAgent := dto.ClientDevice{UserAgent: "curl/8.0", DeviceID: "device-a"}
+
+	assert.NoError(t, refresh(otherDevice), "binding is opt-in")
+
+	service.config.JWT.BindRefreshTokens = true
+	assert.NoError(t, refresh(issuedTo))
+	assert.ErrorIs(t, refresh(otherDevice), errs.ErrInvalidToken)
+	assert.ErrorIs(t, refresh(otherUserAgent), errs.ErrInvalidToken)
+}
+
+func TestRefreshToken_ThrottledAfterFailures(t *testing.T) {
+	service, _ := newRegisterTestService(true)
+	refreshTokens := &memoryRefreshTokenRepository{}
+	service.refreshTokenRepo = refreshTokens
+	service.refreshFailureLimiter = ratelimit.NewLimiter(2, time.Minute)
+	auditLogger := &recordingAuditLogger{}
+	service.auditLogger = auditLogger
+
+	register := func(username string) string {
+		resp, err := service.Register(context.Background(), dto.RegisterReq{
+			Username: username,
+			Password: "Password123!",
+			Email:    stringPtr(username + "@example.com"),
+		})
+		require.NoError(t, err)
+		return resp.RefreshToken
+	}
+	refresh := func(clientIP, refreshToken string) error {
+		_, err := service.RefreshToken(cx.WithClientIP(context.Background(), clientIP), dto.RefreshTokenReq{RefreshToken: refreshToken})
+		return err
+	}
+
+	// Guessing tokens throttles the client IP
+	valid := register("first")
+	assert.ErrorIs(t, refresh("203.0.113.7", "guessed-1"), errs.ErrTokenNotFound)
+	assert.ErrorIs(t, refresh("203.0.113.7", "guessed-2"), errs.ErrTokenNotFound)
+	assert.ErrorIs(t, refresh("203.0.113.7", valid), errs.ErrTooManyRequests)
+	assert.NoError(t, refresh("198.51.100.1", valid), "other clients are not affected")
+
+	// Replaying a revoked token throttles its user from any client IP
+	revoked := register("second")
+	refreshTokens.tokens[revoked].IsRevoked = true
+	assert.ErrorIs(t, refresh("192.0.2.1", revoked), errs.ErrTokenRevoked)
+	assert.ErrorIs(t, refresh("192.0.2.2", revoked), errs.ErrTokenRevoked)
+	refreshTokens.tokens[revoked].IsRevoked = false
+	assert.ErrorIs(t, refresh("192.0.2.3", revoked), errs.ErrTooManyRequests)
+
+	require.Len(t, auditLogger.events, 2)
+	assert.Equal(t, audit.ActionRefreshToken, auditLogger.events[0].Action)
+	assert.False(t, auditLogger.events[0].Success)
+	assert.Equal(t, refreshTokens.tokens[revoked].UserID.String(), auditLogger.events[1].UserID)
+}
+
+func TestRefreshToken_ReplayedRotatedTokenRevokesFamily(t *testing.T) {
+	service, _ := newRegisterTestService(true)
+	refreshTokens := &memoryRefreshTokenRepository{}
+	service.refreshTokenRepo = refreshTokens
+	auditLogger := &recordingAuditLogger{}
+	service.auditLogger = auditLogger
+
+	resp, err := service.Register(context.Background(), dto.RegisterReq{
+		Username: "testuser",
+		Password: "Password123!",
+		Email:    stringPtr("testuser@example.com"),
+	})
+	require.NoError(t, err)
+
+	// Rotate the issued token twice, leaving the last one active
+	first := refreshTokens.tokens[resp.RefreshToken]
+	second, err := first.Rotate("second-token", first.ExpiresAt)
+	require.NoError(t, err)
+	require.NoError(t, refreshTokens.Create(context.Background(), second))
+	require.NoError(t, refreshTokens.MarkReplaced(context.Background(), first.ID, second.ID))
+	third, err := second.Rotate("third-token", first.ExpiresAt)
+	require.NoError(t, err)
+	require.NoError(t, refreshTokens.Create(context.Background(), third))
+	require.NoError(t, refreshTokens.MarkReplaced(context.Background(), second.ID, third.ID))
+
+	_, err = service.RefreshToken(context.Background(), dto.RefreshTokenReq{RefreshToken: resp.RefreshToken})
+	assert.ErrorIs(t, err, errs.ErrTokenRevoked)
+	assert.True(t, third.IsRevoked, "the latest token of a replayed family is revoked")
+
+	_, err = service.RefreshToken(context.Background(), dto.RefreshTokenReq{RefreshToken: "third-token"})
+	assert.ErrorIs(t, err, errs.ErrTokenRevoked)
+
+	require.NotEmpty(t, auditLogger.events)
+	assert.Equal(t, audit.ActionRefreshToken, auditLogger.events[0].Action)
+	assert.Equal(t, "rotated refresh token replayed", auditLogger.events[0].Reason)
+	assert.Equal(t, first.UserID.String(), auditLogger.events[0].UserID)
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	service, _ := newRegisterTestService(true)
+	refreshTokens := &memoryRefreshTokenRepository{}
+	service.refreshTokenRepo = refreshTokens
+	service.auditLogger = &recordingAuditLogger{}
+
+	registered, err := service.Register(context.Background(), dto.RegisterReq{
+		Username: "testuser",
+		Password: "Password123!",
+		Email:    stringPtr("testuser@example.com"),
+	})
+	require.NoError(t, err)
+	first := refreshTokens.tokens[registered.RefreshToken]
+
+	rotated, err := service.RotateRefreshToken(context.Background(), dto.RotateRefreshReq{RefreshToken: registered.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEmpty(t, rotated.AccessToken)
+	assert.NotEqual(t, registered.RefreshToken, rotated.RefreshToken)
+	assert.Greater(t, rotated.RefreshTokenExpiresAt, time.Now().UnixMilli())
+
+	second := refreshTokens.tokens[rotated.RefreshToken]
+	require.NotNil(t, second)
+	assert.Equal(t, first.FamilyID, second.FamilyID)
+	require.NotNil(t, first.ReplacedBy)
+	assert.Equal(t, second.ID, *first.ReplacedBy)
+	assert.True(t, first.IsRevoked)
+
+	_, err = service.RefreshToken(context.Background(), dto.RefreshTokenReq{RefreshToken: rotated.RefreshToken})
+	assert.NoError(t, err, "the new refresh token is usable")
+
+	_, err = service.RotateRefreshToken(context.Background(), dto.RotateRefreshReq{RefreshToken: registered.RefreshToken})
+	assert.ErrorIs(t, err, errs.ErrTokenRevoked)
+	assert.True(t, second.IsRevoked, "replaying the rotated token revokes its successor")
+}
+
+func TestRegister_ReturnsTokenExpiry(t *testing.T) {
+	service, _ := newRegisterTestService(true)
+	refreshTokens := &memoryRefreshTokenRepository{}
+	service.refreshTokenRepo = refreshTokens
+
+	before := time.Now()
+	resp, err := service.Register(context.Background(), dto.RegisterReq{
+		Username: "testuser",
+		Password: "Password123!",
+		Email:    stringPtr("known@example.com"),
+	})
+	require.NoError(t, err)
+
+	after := time.Now()
+
+	// The reported expiry is the exp claim signed into the token, not a recomputation
+	payload, err := service.tokenMaker.VerifyAccessToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, payload.ExpiresAt().UnixMilli(), resp.AccessTokenExpiresAt)
+	accessExpiresAt := time.UnixMilli(resp.AccessTokenExpiresAt)
+	assert.False(t, accessExpiresAt.Before(before.Add(time.Minute).Truncate(time.Second)))
+	assert.False(t, accessExpiresAt.After(after.Add(time.Minute)))
+	assert.Equal(t, refreshTokens.tokens[resp.RefreshToken].ExpiresAt.Millis(), resp.RefreshTokenExpiresAt)
+
+	refreshed, err := service.RefreshToken(context.Background(), dto.RefreshTokenReq{RefreshToken: resp.RefreshToken})
+	require.NoError(t, err)
+	refreshedPayload, err := service.tokenMaker.VerifyAccessToken(refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, refreshedPayload.ExpiresAt().UnixMilli(), refreshed.AccessTokenExpiresAt)
+	assert.GreaterOrEqual(t, refreshed.AccessTokenExpiresAt, resp.AccessTokenExpiresAt)
+}
+
+func TestRegister_BindsTokensToTenant(t *testing.T) {
+	service, _ := newRegisterTestService(true)
+	service.refreshTokenRepo = &memoryRefreshTokenRepository{}
+
+	ctx := cx.WithTenantID(context.Background(), "tenant-a")
+	resp, err := service.Register(ctx, dto.RegisterReq{
+		Username: "testuser",
+		Password: "Password123!",
+		Email:    stringPtr("known@example.com"),
+	})
+	require.NoError(t, err)
+
+	payload, err := service.tokenMaker.VerifyAccessToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-a", payload.TenantID)
+
+	refreshed, err := service.RefreshToken(ctx, dto.RefreshTokenReq{RefreshToken: resp.RefreshToken})
+	require.NoError(t, err)
+	payload, err = service.tokenMaker.VerifyAccessToken(refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-a", payload.TenantID)
+}
+
+func TestAuthenticateUser_IndistinguishableFailures(t *testing.T) {
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+
+	service := &UserService{
+		userRepo: &stubUserRepository{usersByEmail: map[string]*domain.User{"known@example.com": user}},
+	}
+	logger := logutils.GetLoggerOrDefault(context.Background())
+
+	_, unknownErr := service.authenticateUser(context.Background(), dto.LoginReq{Email: "unknown@example.com", Password: "Password123!"}, logger)
+	_, wrongPasswordErr := service.authenticateUser(context.Background(), dto.LoginReq{Email: "known@example.com", Password: "WrongPassword1!"}, logger)
+
+	assert.ErrorIs(t, unknownErr, errs.ErrInvalidCredentials)
+	assert.ErrorIs(t, wrongPasswordErr, errs.ErrInvalidCredentials)
+
+	unknownStatus := status.Convert(errs.ToGRPCError(unknownErr))
+	wrongPasswordStatus := status.Convert(errs.ToGRPCError(wrongPasswordErr))
+	assert.Equal(t, wrongPasswordStatus.Code(), unknownStatus.Code())
+	assert.Equal(t, wrongPasswordStatus.Message(), unknownStatus.Message())
+}
+
+func TestAuthenticateUser_UnknownUserRunsPasswordComparison(t *testing.T) {
+	var comparedPassword string
+	original := verifyDummyPassword
+	verifyDummyPassword = func(_ *password.Hasher, plainPassword string) {
+		comparedPassword = plainPassword
+	}
+	defer func() { verifyDummyPassword = original }()
+
+	service := &UserService{userRepo: &stubUserRepository{}}
+
+	_, err := service.authenticateUser(context.Background(), dto.LoginReq{Email: "unknown@example.com", Password: "Password123!"}, logutils.GetLoggerOrDefault(context.Background()))
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials)
+	assert.Equal(t, "Password123!", comparedPassword, "unknown accounts must still pay for a password comparison")
+}
+
+// BenchmarkAuthenticateUser documents that a login for an unknown account costs about
+// the same as a wrong password for an existing one; compare the two sub-benchmarks.
+func BenchmarkAuthenticateUser(b *testing.B) {
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(b, err)
+
+	service := &UserService{
+		userRepo: &stubUserRepository{usersByEmail: map[string]*domain.User{"known@example.com": user}},
+	}
+	logger := logutils.GetLoggerOrDefault(context.Background())
+	logger.Logger.SetLevel(logrus.ErrorLevel)
+	dummyPasswordHash()
+
+	b.Run("UnknownUser", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = service.authenticateUser(context.Background(), dto.LoginReq{Email: "unknown@example.com", Password: "WrongPassword1!"}, logger)
+		}
+	})
+	b.Run("WrongPassword", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = service.authenticateUser(context.Background(), dto.LoginReq{Email: "known@example.com", Password: "WrongPassword1!"}, logger)
+		}
+	})
+}
+
+func TestAuthenticateUser_Success(t *testing.T) {
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+
+	service := &UserService{
+		userRepo: &stubUserRepository{usersByEmail: map[string]*domain.User{"known@example.com": user}},
+	}
+
+	authenticated, err := service.authenticateUser(context.Background(), dto.LoginReq{Email: "known@example.com", Password: "Password123!"}, logutils.GetLoggerOrDefault(context.Background()))
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, authenticated.ID)
+}
+
+func TestAuthenticateUser_AccountStatus(t *testing.T) {
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+	user.Status = domain.AccountStatusDisabled
+
+	service := &UserService{
+		userRepo: &stubUserRepository{usersByEmail: map[string]*domain.User{"known@example.com": user}},
+	}
+	logger := logutils.GetLoggerOrDefault(context.Background())
+
+	_, err = service.authenticateUser(context.Background(), dto.LoginReq{Email: "known@example.com", Password: "WrongPassword1!"}, logger)
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials, "the status is not revealed without the password")
+
+	_, err = service.authenticateUser(context.Background(), dto.LoginReq{Email: "known@example.com", Password: "Password123!"}, logger)
+	assert.ErrorIs(t, err, errs.ErrAccountDisabled)
+}
+
+func TestAuthenticateUser_Pepper(t *testing.T) {
+	hasher := password.NewHasher(4).WithPepper("current-pepper")
+	user, err := domain.NewUserWithPassword(hasher, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+
+	service := &UserService{
+		userRepo:       &stubUserRepository{usersByEmail: map[string]*domain.User{"known@example.com": user}},
+		passwordHasher: hasher,
+	}
+	req := dto.LoginReq{Email: "known@example.com", Password: "Password123!"}
+	logger := logutils.GetLoggerOrDefault(context.Background())
+
+	_, err = service.authenticateUser(context.Background(), req, logger)
+	require.NoError(t, err)
+
+	service.passwordHasher = password.NewHasher(4).WithPepper("changed-pepper")
+	_, err = service.authenticateUser(context.Background(), req, logger)
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials, "changing the pepper invalidates existing hashes")
+}
+
+func TestLogin_ExpiredPassword(t *testing.T) {
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+	user.PasswordChangedAt = domain.FromTime(time.Now().Add(-91 * 24 * time.Hour))
+
+	service, userRepo := newRegisterTestService(true)
+	userRepo.usersByEmail = map[string]*domain.User{"known@example.com": user}
+	service.loginLimiter = ratelimit.NewLimiter(10, time.Minute)
+	service.accountLoginLimiter = ratelimit.NewLimiter(10, time.Minute)
+	service.notificationEventLogRepo = stubNotificationEventLogRepository{}
+	req := dto.LoginReq{Email: "known@example.com", Password: "Password123!"}
+
+	resp, err := service.Login(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, resp.PasswordExpired, "expiry is disabled by default")
+
+	service.config.Password.MaxAge = 90 * 24 * time.Hour
+	resp, err = service.Login(context.Background(), req)
+	require.NoError(t, err, "an expired but correct password still signs in")
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.True(t, resp.PasswordExpired)
+
+	_, err = service.Login(context.Background(), dto.LoginReq{Email: "known@example.com", Password: "WrongPassword1!"})
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials)
+}
+
+func TestLogin_PhoneOnlyUser(t *testing.T) {
+	user, err := domain.NewUserWithPassword(nil, nil, "Password123!", "phoneuser", stringPtr("TW"), stringPtr("+886912345678"))
+	require.NoError(t, err)
+	require.Nil(t, user.Email)
+
+	service, userRepo := newRegisterTestService(true)
+	userRepo.usersByPhone = map[string]*domain.User{"TW+886912345678": user}
+	userRepo.usersByID = map[uuid.UUID]*domain.User{user.ID: user}
+	service.loginLimiter = ratelimit.NewLimiter(10, time.Minute)
+	service.accountLoginLimiter = ratelimit.NewLimiter(10, time.Minute)
+	service.notificationEventLogRepo = stubNotificationEventLogRepository{}
+	service.refreshTokenRepo = &memoryRefreshTokenRepository{}
+
+	_, err = service.Login(context.Background(), dto.LoginReq{CountryCode: "TW", Phone: "+886912345678", Password: "WrongPassword1!"})
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials)
+
+	resp, err := service.Login(context.Background(), dto.LoginReq{CountryCode: "TW", Phone: "+886912345678", Password: "Password123!"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+
+	_, err = service.RefreshToken(context.Background(), dto.RefreshTokenReq{RefreshToken: resp.RefreshToken})
+	require.NoError(t, err)
+}
+
+func TestLogin_NormalizesIdentifiers(t *testing.T) {
+	service, userRepo := newRegisterTestService(true)
+	service.config.Phone.DefaultDialingCode = "+886"
+	service.loginLimiter = ratelimit.NewLimiter(10, time.Minute)
+	service.accountLoginLimiter = ratelimit.NewLimiter(10, time.Minute)
+	service.notificationEventLogRepo = stubNotificationEventLogRepository{}
+	service.refreshTokenRepo = &memoryRefreshTokenRepository{}
+
+	resp, err := service.Register(context.Background(), dto.RegisterReq{
+		Username:    " Alice ",
+		Password:    "Password123!",
+		Email:       stringPtr(" Alice@Example.com "),
+		CountryCode: stringPtr("TW"),
+		Phone:       stringPtr("912 345 678"),
+	})
+	require.NoError(t, err)
+	user := resp.User
+	assert.Equal(t, "alice@example.com", user.Email.String())
+	assert.Equal(t, "+886912345678", user.Phone.String())
+	assert.Equal(t, "Alice", user.Username.String())
+
+	// The stub matches identifiers exactly, like the database does
+	userRepo.usersByEmail = map[string]*domain.User{user.Email.String(): user}
+	userRepo.usersByPhone = map[string]*domain.User{string(*user.CountryCode) + user.Phone.String(): user}
+
+	for _, req := range []dto.LoginReq{
+		{Email: "ALICE@example.COM"},
+		{Email: "  alice@example.com\t"},
+		{CountryCode: " TW ", Phone: "912-345-678"},
+		{CountryCode: "TW", Phone: "+886 912 345 678"},
+	} {
+		req.Password = "Password123!"
+		_, err := service.Login(context.Background(), req)
+		assert.NoError(t, err, "%+v", req)
+	}
+}
+
+func TestLogin_InvalidCountryCode(t *testing.T) {
+	service, userRepo := newRegisterTestService(true)
+	userRepo.usersByPhone = map[string]*domain.User{}
+	service.loginLimiter = ratelimit.NewLimiter(10, time.Minute)
+	service.accountLoginLimiter = ratelimit.NewLimiter(10, time.Minute)
+
+	for _, countryCode := range []string{"+886", "tw", "TWN"} {
+		_, err := service.Login(context.Background(), dto.LoginReq{CountryCode: countryCode, Phone: "+886912345678", Password: "Password123!"})
+		assert.ErrorIs(t, err, errs.ErrInvalidCountryCode, countryCode)
+	}
+}
+
+func TestLogin_RequiresExactlyOneIdentifier(t *testing.T) {
+	service, _ := newRegisterTestService(true)
+
+	for _, req := range []dto.LoginReq{
+		{Password: "Password123!"},
+		{Phone: "+886912345678", Password: "Password123!"},
+		{Email: "known@example.com", CountryCode: "TW", Phone: "+886912345678", Password: "Password123!"},
+	} {
+		_, err := service.Login(context.Background(), req)
+		assert.ErrorIs(t, err, errs.ErrInvalidIdentifier)
+	}
+}
+
+func TestLogin_RateLimitedPerClientIP(t *testing.T) {
+	service := &UserService{
+		config:              &config.Config{RateLimit: config.RateLimitConfig{Login: config.RateLimitRule{Limit: 2, Window: time.Minute}}},
+		userRepo:            &stubUserRepository{},
+		loginLimiter:        ratelimit.NewLimiter(2, time.Minute),
+		accountLoginLimiter: ratelimit.NewLimiter(10, time.Minute),
+	}
+	ctx := cx.WithClientIP(context.Background(), "203.0.113.7")
+	req := dto.LoginReq{Email: "unknown@example.com", Password: "Password123!"}
+
+	for i := 0; i < 2; i++ {
+		_, err := service.Login(ctx, req)
+		assert.ErrorIs(t, err, errs.ErrInvalidCredentials)
+	}
+
+	_, err := service.Login(ctx, req)
+	assert.ErrorIs(t, err, errs.ErrTooManyRequests)
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, time.Minute, st.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+
+	_, err = service.Login(cx.WithClientIP(context.Background(), "198.51.100.1"), req)
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials)
+}
+
+func TestLogin_RateLimitedPerAccount(t *testing.T) {
+	service := &UserService{
+		config: &config.Config{RateLimit: config.RateLimitConfig{
+			Login:        config.RateLimitRule{Limit: 10, Window: time.Minute},
+			LoginAccount: config.RateLimitRule{Limit: 2, Window: 15 * time.Minute},
+		}},
+		userRepo:            &stubUserRepository{},
+		loginLimiter:        ratelimit.NewLimiter(10, time.Minute),
+		accountLoginLimiter: ratelimit.NewLimiter(2, 15*time.Minute),
+	}
+	req := dto.LoginReq{Email: "target@example.com", Password: "Password123!"}
+
+	for _, ip := range []string{"203.0.113.7", "198.51.100.1"} {
+		_, err := service.Login(cx.WithClientIP(context.Background(), ip), req)
+		assert.ErrorIs(t, err, errs.ErrInvalidCredentials)
+	}
+
+	// A new IP and a differently cased email still count against the same account
+	_, err := service.Login(cx.WithClientIP(context.Background(), "192.0.2.9"), dto.LoginReq{Email: " Target@Example.com", Password: "Password123!"})
+	assert.ErrorIs(t, err, errs.ErrTooManyRequests)
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, 15*time.Minute, st.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+
+	_, err = service.Login(cx.WithClientIP(context.Background(), "192.0.2.9"), dto.LoginReq{Email: "other@example.com", Password: "Password123!"})
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials, "other accounts are not affected")
+}
+
+func TestLoginAccountKey(t *testing.T) {
+	email := loginAccountKey("", dto.LoginReq{Email: "alice@example.com"}.Identifier())
+	assert.Len(t, email, 64)
+	assert.NotContains(t, email, "alice")
+	assert.Equal(t, email, loginAccountKey("", dto.LoginReq{Email: "  ALICE@example.com "}.Identifier()))
+
+	phone := loginAccountKey("", dto.LoginReq{CountryCode: "TW", Phone: "+886912345678"}.Identifier())
+	assert.NotEqual(t, email, phone)
+	assert.NotEqual(t, phone, loginAccountKey("", dto.LoginReq{CountryCode: "TW", Phone: "+886912345679"}.Identifier()))
+
+	assert.NotEqual(t, loginAccountKey("tenant-a", dto.LoginReq{Email: "alice@example.com"}.Identifier()),
+		loginAccountKey("tenant-b", dto.LoginReq{Email: "alice@example.com"}.Identifier()))
+}
+
+func TestLogin_RateLimitedPerAccountPerTenant(t *testing.T) {
+	service := &UserService{
+		config: &config.Config{RateLimit: config.RateLimitConfig{
+			Login:        config.RateLimitRule{Limit: 10, Window: time.Minute},
+			LoginAccount: config.RateLimitRule{Limit: 2, Window: 15 * time.Minute},
+		}},
+		userRepo:            &stubUserRepository{},
+		loginLimiter:        ratelimit.NewLimiter(10, time.Minute),
+		accountLoginLimiter: ratelimit.NewLimiter(2, 15*time.Minute),
+	}
+	req := dto.LoginReq{Email: "alice@example.com", Password: "Password123!"}
+	tenantA := cx.WithTenantID(cx.WithClientIP(context.Background(), "203.0.113.7"), "tenant-a")
+	tenantB := cx.WithTenantID(cx.WithClientIP(context.Background(), "198.51.100.1"), "tenant-b")
+
+	for range 2 {
+		_, err := service.Login(tenantA, req)
+		assert.ErrorIs(t, err, errs.ErrInvalidCredentials)
+	}
+	_, err := service.Login(tenantA, req)
+	assert.ErrorIs(t, err, errs.ErrTooManyRequests)
+
+	_, err = service.Login(tenantB, req)
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials, "the same email in another tenant has its own quota")
+}
+
+func TestParseUserID(t *testing.T) {
+	want := uuid.New()
+	id, err := parseUserID(want.String())
+	require.NoError(t, err)
+	assert.Equal(t, want, id)
+
+	for _, input := range []string{"", "not-a-uuid", "123", uuid.Nil.String()} {
+		_, err := parseUserID(input)
+		assert.ErrorIs(t, err, errs.ErrInvalidID, "input %q", input)
+		assert.Equal(t, codes.InvalidArgument, status.Code(errs.ToGRPCError(err)))
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"wallet-user-svc/internal/app/config"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/pkg/utils/audit"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// NewWebAuthn returns the WebAuthn relying party described by cfg, or nil when WebAuthn
+// is disabled. Passkeys must be discoverable, so FinishWebAuthnLogin can find the user
+// without an identifier, and must verify the user with a PIN or biometrics, so a passkey
+// stands in for both the password and the second factor.
+func NewWebAuthn(cfg *config.WebAuthnConfig) (*webauthn.WebAuthn, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	requireResidentKey := true
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.SessionTTL, TimeoutUVD: cfg.SessionTTL}
+	return webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			RequireResidentKey: &requireResidentKey,
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+}
+
+// BeginWebAuthnRegistration starts registering a passkey for the authenticated user. It
+// returns the options to pass to navigator.credentials.create; the passkey is only
+// stored once FinishWebAuthnRegistration verifies the authenticator's response.
+func (s *UserService) BeginWebAuthnRegistration(ctx context.Context) (*dto.BeginWebAuthnResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	if s.webAuthn == nil {
+		return nil, errs.ErrWebAuthnDisabled
+	}
+
+	user, err := s.authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webAuthnUser, err := s.loadWebAuthnUser(ctx, user)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list WebAuthn credentials")
+		return nil, err
+	}
+
+	// Authenticators refuse to create a second passkey for the same account
+	exclusions := webauthn.Credentials(webAuthnUser.WebAuthnCredentials()).CredentialDescriptors()
+	creation, sessionData, err := s.webAuthn.BeginRegistration(webAuthnUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin WebAuthn registration")
+		return nil, err
+	}
+
+	return s.createWebAuthnSession(ctx, &user.ID, domain.WebAuthnCeremonyRegistration, creation, sessionData, logger)
+}
+
+// FinishWebAuthnRegistration verifies the authenticator's response to
+// BeginWebAuthnRegistration and stores the new passkey of the authenticated user
+func (s *UserService) FinishWebAuthnRegistration(ctx context.Context, req dto.FinishWebAuthnRegistrationReq) (*dto.FinishWebAuthnRegistrationResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	if s.webAuthn == nil {
+		return nil, errs.ErrWebAuthnDisabled
+	}
+
+	user, err := s.authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	session, sessionData, err := s.webAuthnSession(ctx, req.SessionID, domain.WebAuthnCeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another user's session is reported like a missing one so IDs cannot be probed
+	if session.UserID == nil || *session.UserID != user.ID {
+		logger.WithField("session_id", session.ID.String()).Warn("WebAuthn registration session of another user")
+		return nil, errs.ErrInvalidCeremony
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		logger.WithError(err).Warn("Malformed WebAuthn registration response")
+		return nil, errs.ErrInvalidPasskey
+	}
+
+	webAuthnUser, err := s.loadWebAuthnUser(ctx, user)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list WebAuthn credentials")
+		return nil, err
+	}
+
+	credential, err := s.webAuthn.CreateCredential(webAuthnUser, *sessionData, parsed)
+	if err != nil {
+		logger.WithError(err).Warn("WebAuthn registration response failed verification")
+		s.auditLogger.Log(ctx, audit.Event{Action: audit.ActionRegisterWebAuthnCredential, UserID: user.ID.String(), Reason: "invalid credential"})
+		return nil, errs.ErrInvalidPasskey
+	}
+
+	if err := s.webAuthnSessionRepo.Consume(ctx, session.ID); err != nil {
+		logger.WithError(err).Warn("Failed to consume WebAuthn session")
+		return nil, err
+	}
+
+	stored, err := domain.NewWebAuthnCredential(user.ID, credential.ID, credential.PublicKey, credential.Authenticator.SignCount, credential.Flags.BackupEligible)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webAuthnCredentialRepo.Create(ctx, stored); err != nil {
+		logger.WithError(err).Error("Failed to store WebAuthn credential")
+		return nil, err
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionRegisterWebAuthnCredential,
+		UserID:  user.ID.String(),
+		Success: true,
+		Fields:  logrus.Fields{"credential_id": credentialID},
+	})
+	logger.WithField("credential_id", credentialID).Info("WebAuthn credential registered")
+
+	return &dto.FinishWebAuthnRegistrationResp{CredentialID: credentialID}, nil
+}
+
+// BeginWebAuthnLogin starts a password-less login. The options name no user: the
+// authenticator offers the passkeys it holds for the relying party and its response to
+// FinishWebAuthnLogin identifies the user. Attempts share the per client IP login limit.
+func (s *UserService) BeginWebAuthnLogin(ctx context.Context) (*dto.BeginWebAuthnResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	if s.webAuthn == nil {
+		return nil, errs.ErrWebAuthnDisabled
+	}
+
+	clientIP, _ := cx.GetClientIP(ctx)
+	if !s.loginLimiter.Allow(ctx, clientIP) {
+		logger.WithField("client_ip", clientIP).Warn("Login rate limit exceeded")
+		return nil, errs.NewRateLimitError(s.config.RateLimit.Login.Limit, s.config.RateLimit.Login.Window)
+	}
+
+	assertion, sessionData, err := s.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		logger.WithError(err).Error("Failed to begin WebAuthn login")
+		return nil, err
+	}
+
+	return s.createWebAuthnSession(ctx, nil, domain.WebAuthnCeremonyLogin, assertion, sessionData, logger)
+}
+
+// FinishWebAuthnLogin verifies the authenticator's response to BeginWebAuthnLogin and
+// issues the same token pair as a password login. Every verification failure, including
+// an unknown user or passkey, is reported as ErrInvalidCredentials.
+func (s *UserService) FinishWebAuthnLogin(ctx context.Context, req dto.FinishWebAuthnLoginReq) (*dto.LoginResp, error) {
+	logger := logutils.GetLoggerOrDefault(ctx)
+
+	if s.webAuthn == nil {
+		return nil, errs.ErrWebAuthnDisabled
+	}
+
+	session, sessionData, err := s.webAuthnSession(ctx, req.SessionID, domain.WebAuthnCeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	if err != nil {
+		logger.WithError(err).Warn("Malformed WebAuthn login response")
+		return nil, errs.ErrInvalidCredentials
+	}
+
+	// The user handle the authenticator returns is the user ID it was registered with
+	var user *domain.User
+	findUser := func(_, userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, errs.ErrInvalidCredentials
+		}
+		if user, err = s.userRepo.GetByID(ctx, userID); err != nil {
+			return nil, err
+		}
+		return s.loadWebAuthnUser(ctx, user)
+	}
+
+	credential, err := s.webAuthn.ValidateDiscoverableLogin(findUser, *sessionData, parsed)
+	if err == nil && credential.Authenticator.CloneWarning {
+		logger.WithField("user_id", user.ID.String()).Warn("WebAuthn signature counter went backwards, the authenticator may be cloned")
+		err = errs.ErrInvalidCredentials
+	}
+	if err != nil {
+		event := audit.Event{Action: audit.ActionWebAuthnLogin, Reason: "invalid credential"}
+		if user != nil {
+			event.UserID = user.ID.String()
+		}
+		s.auditLogger.Log(ctx, event)
+		logger.WithError(err).Warn("WebAuthn login response failed verification")
+		return nil, errs.ErrInvalidCredentials
+	}
+
+	logger = logger.WithField("user_id", user.ID.String())
+
+	if ok, err := user.CanLogin(); !ok {
+		logger.WithError(err).Warn("Account is not allowed to sign in")
+		return nil, err
+	}
+
+	if err := s.webAuthnSessionRepo.Consume(ctx, session.ID); err != nil {
+		logger.WithError(err).Warn("Failed to consume WebAuthn session")
+		return nil, err
+	}
+
+	if err := s.webAuthnCredentialRepo.UpdateSignCount(ctx, credential.ID, credential.Authenticator.SignCount); err != nil {
+		logger.WithError(err).Error("Failed to update WebAuthn sign count")
+		return nil, err
+	}
+
+	s.auditLogger.Log(ctx, audit.Event{
+		Action:  audit.ActionWebAuthnLogin,
+		UserID:  user.ID.String(),
+		Success: true,
+		Fields:  logrus.Fields{"credential_id": base64.RawURLEncoding.EncodeToString(credential.ID)},
+	})
+
+	return s.issueLoginTokens(ctx, user, req.Device, logger)
+}
+
+// createWebAuthnSession stores the state of a begun ceremony and returns its options
+func (s *UserService) createWebAuthnSession(
+	ctx context.Context,
+	userID *uuid.UUID,
+	ceremony domain.WebAuthnCeremony,
+	options interface{},
+	sessionData *webauthn.SessionData,
+	logger *logrus.Entry,
+) (*dto.BeginWebAuthnResp, error) {
+	data, err := json.Marshal(sessionData)
+	if err != nil {
+		logger.WithError(err).Error("Failed to marshal WebAuthn session")
+		return nil, err
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		logger.WithError(err).Error("Failed to marshal WebAuthn options")
+		return nil, err
+	}
+
+	session, err := domain.NewWebAuthnSession(userID, ceremony, data, s.config.WebAuthn.SessionTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.webAuthnSessionRepo.Create(ctx, session); err != nil {
+		logger.WithError(err).Error("Failed to store WebAuthn session")
+		return nil, err
+	}
+
+	logger.WithField("ceremony", ceremony.String()).Info("WebAuthn ceremony started")
+
+	return &dto.BeginWebAuthnResp{
+		SessionID: session.ID.String(),
+		Options:   optionsJSON,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// webAuthnSession loads a session of ceremony that can still be finished, with its
+// library state
+func (s *UserService) webAuthnSession(ctx context.Context, rawID string, ceremony domain.WebAuthnCeremony) (*domain.WebAuthnSession, *webauthn.SessionData, error) {
+	sessionID, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, nil, errs.ErrInvalidCeremony
+	}
+
+	session, err := s.webAuthnSessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := session.IsValid(ceremony); err != nil {
+		return nil, nil, err
+	}
+
+	var sessionData webauthn.SessionData
+	if err := json.Unmarshal(session.Data, &sessionData); err != nil {
+		return nil, nil, err
+	}
+
+	return session, &sessionData, nil
+}
+
+// loadWebAuthnUser loads the credentials of user for the WebAuthn library
+func (s *UserService) loadWebAuthnUser(ctx context.Context, user *domain.User) (*webAuthnUser, error) {
+	credentials, err := s.webAuthnCredentialRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &webAuthnUser{user: user, credentials: credentials}, nil
+}
+
+// webAuthnUser adapts a user and their credentials to webauthn.User. The user handle
+// stored on the authenticator is the 16 byte user ID.
+type webAuthnUser struct {
+	user        *domain.User
+	credentials []*domain.WebAuthnCredential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return totpAccountName(u.user)
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Username.String()
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, credential := range u.credentials {
+		credentials = append(credentials, webauthn.Credential{
+			ID:            credential.CredentialID,
+			PublicKey:     credential.PublicKey,
+			Flags:         webauthn.CredentialFlags{BackupEligible: credential.BackupEligible},
+			Authenticator: webauthn.Authenticator{SignCount: credential.SignCount},
+		})
+	}
+	return credentials
+}
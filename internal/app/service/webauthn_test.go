@@ -0,0 +1,341 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/config"
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/pkg/utils/crypt/token"
+	"wallet-user-svc/pkg/utils/cx"
+	"wallet-user-svc/pkg/utils/ratelimit"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "wallet.example.com"
+	testOrigin = "https://wallet.example.com"
+)
+
+type memoryWebAuthnCredentialRepository struct {
+	credentials []*domain.WebAuthnCredential
+}
+
+func (r *memoryWebAuthnCredentialRepository) Create(_ context.Context, credential *domain.WebAuthnCredential) error {
+	r.credentials = append(r.credentials, credential)
+	return nil
+}
+
+func (r *memoryWebAuthnCredentialRepository) ListByUserID(_ context.Context, userID uuid.UUID) ([]*domain.WebAuthnCredential, error) {
+	var credentials []*domain.WebAuthnCredential
+	for _, credential := range r.credentials {
+		if credential.UserID == userID {
+			credentials = append(credentials, credential)
+		}
+	}
+	return credentials, nil
+}
+
+func (r *memoryWebAuthnCredentialRepository) UpdateSignCount(_ context.Context, credentialID []byte, signCount uint32) error {
+	for _, credential := range r.credentials {
+		if bytes.Equal(credential.CredentialID, credentialID) {
+			credential.SignCount = signCount
+		}
+	}
+	return nil
+}
+
+type memoryWebAuthnSessionRepository struct {
+	sessions map[uuid.UUID]*domain.WebAuthnSession
+}
+
+func (r *memoryWebAuthnSessionRepository) Create(_ context.Context, session *domain.WebAuthnSession) error {
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *memoryWebAuthnSessionRepository) GetByID(_ context.Context, id uuid.UUID) (*domain.WebAuthnSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errs.ErrInvalidCeremony
+	}
+	return session, nil
+}
+
+func (r *memoryWebAuthnSessionRepository) Consume(_ context.Context, id uuid.UUID) error {
+	session, ok := r.sessions[id]
+	if !ok || session.ConsumedAt != nil {
+		return errs.ErrInvalidCeremony
+	}
+	now := domain.Now()
+	session.ConsumedAt = &now
+	return nil
+}
+
+// softwareAuthenticator is a passkey authenticator holding a single P-256 credential.
+// It answers ceremonies with "none" attestation, the way platform authenticators do.
+type softwareAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+
+	return &softwareAuthenticator{key: key, credentialID: credentialID}
+}
+
+// authenticatorData returns the authenticator data with user presence and verification
+// set, and the attested credential data when attested is set
+func (a *softwareAuthenticator) authenticatorData(t *testing.T, attested bool) []byte {
+	t.Helper()
+
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	flags := byte(0x01 | 0x04) // user present, user verified
+	if attested {
+		flags |= 0x40
+	}
+
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if !attested {
+		return data
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{KeyType: int64(webauthncose.EllipticKey), Algorithm: int64(webauthncose.AlgES256)},
+		Curve:         1, // P-256
+		XCoord:        a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord:        a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	data = append(data, make([]byte, 16)...) // zero AAGUID
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+	data = append(data, a.credentialID...)
+	return append(data, publicKey...)
+}
+
+func (a *softwareAuthenticator) clientData(t *testing.T, ceremony, challenge string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]string{"type": ceremony, "challenge": challenge, "origin": testOrigin})
+	require.NoError(t, err)
+	return data
+}
+
+// create answers the options of BeginWebAuthnRegistration
+func (a *softwareAuthenticator) create(t *testing.T, options []byte) []byte {
+	t.Helper()
+
+	var creation struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}
+	require.NoError(t, json.Unmarshal(options, &creation))
+
+	attestationObject, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authenticatorData(t, true),
+	})
+	require.NoError(t, err)
+
+	return a.credentialJSON(t, map[string]string{
+		"clientDataJSON":    encodeBase64URL(a.clientData(t, "webauthn.create", creation.PublicKey.Challenge)),
+		"attestationObject": encodeBase64URL(attestationObject),
+	})
+}
+
+// get answers the options of BeginWebAuthnLogin for the user with userID
+func (a *softwareAuthenticator) get(t *testing.T, options []byte, userID uuid.UUID) []byte {
+	t.Helper()
+
+	var assertion struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}
+	require.NoError(t, json.Unmarshal(options, &assertion))
+
+	a.signCount++
+	authenticatorData := a.authenticatorData(t, false)
+	clientData := a.clientData(t, "webauthn.get", assertion.PublicKey.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authenticatorData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	return a.credentialJSON(t, map[string]string{
+		"clientDataJSON":    encodeBase64URL(clientData),
+		"authenticatorData": encodeBase64URL(authenticatorData),
+		"signature":         encodeBase64URL(signature),
+		"userHandle":        encodeBase64URL(userID[:]),
+	})
+}
+
+func (a *softwareAuthenticator) credentialJSON(t *testing.T, response map[string]string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"id":       encodeBase64URL(a.credentialID),
+		"rawId":    encodeBase64URL(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func encodeBase64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// newWebAuthnTestService returns a service with WebAuthn enabled and a user without passkeys
+func newWebAuthnTestService(t *testing.T) (*UserService, *domain.User, *memoryWebAuthnCredentialRepository) {
+	t.Helper()
+
+	cfg := &config.Config{
+		JWT: config.JWTConfig{AccessTokenDuration: time.Minute, RefreshTokenDuration: time.Hour},
+		WebAuthn: config.WebAuthnConfig{
+			Enabled:       true,
+			RPID:          testRPID,
+			RPDisplayName: "Wallet",
+			RPOrigins:     []string{testOrigin},
+			SessionTTL:    time.Minute,
+		},
+	}
+	webAuthn, err := NewWebAuthn(&cfg.WebAuthn)
+	require.NoError(t, err)
+
+	user, err := domain.NewUserWithPassword(nil, stringPtr("known@example.com"), "Password123!", "testuser", nil, nil)
+	require.NoError(t, err)
+
+	credentials := &memoryWebAuthnCredentialRepository{}
+	service := &UserService{
+		config:                   cfg,
+		userRepo:                 &stubUserRepository{usersByID: map[uuid.UUID]*domain.User{user.ID: user}},
+		refreshTokenRepo:         stubRefreshTokenRepository{},
+		txManager:                stubTxManager{},
+		tokenMaker:               token.NewJWTTokenMaker("test-secret-key-with-enough-length", 0),
+		notificationEventLogRepo: stubNotificationEventLogRepository{},
+		notificationPrefRepo:     &memoryNotificationPreferenceRepository{},
+		loginLimiter:             ratelimit.NewLimiter(10, time.Minute),
+		auditLogger:              nopAuditLogger{},
+		webAuthn:                 webAuthn,
+		webAuthnCredentialRepo:   credentials,
+		webAuthnSessionRepo:      &memoryWebAuthnSessionRepository{sessions: make(map[uuid.UUID]*domain.WebAuthnSession)},
+	}
+
+	return service, user, credentials
+}
+
+func TestWebAuthn_RegisterAndLogin(t *testing.T) {
+	service, user, credentials := newWebAuthnTestService(t)
+	authenticator := newSoftwareAuthenticator(t)
+	authCtx := cx.WithAuthUserID(context.Background(), user.ID)
+
+	registration, err := service.BeginWebAuthnRegistration(authCtx)
+	require.NoError(t, err)
+
+	registered, err := service.FinishWebAuthnRegistration(authCtx, dto.FinishWebAuthnRegistrationReq{
+		SessionID:  registration.SessionID,
+		Credential: authenticator.create(t, registration.Options),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, encodeBase64URL(authenticator.credentialID), registered.CredentialID)
+	require.Len(t, credentials.credentials, 1)
+	assert.Equal(t, user.ID, credentials.credentials[0].UserID)
+
+	login, err := service.BeginWebAuthnLogin(context.Background())
+	require.NoError(t, err)
+
+	resp, err := service.FinishWebAuthnLogin(context.Background(), dto.FinishWebAuthnLoginReq{
+		SessionID:  login.SessionID,
+		Credential: authenticator.get(t, login.Options, user.ID),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.NotEmpty(t, resp.RefreshToken)
+	assert.Equal(t, user.ID, resp.User.ID)
+	assert.Equal(t, uint32(1), credentials.credentials[0].SignCount)
+
+	_, err = service.FinishWebAuthnLogin(context.Background(), dto.FinishWebAuthnLoginReq{
+		SessionID:  login.SessionID,
+		Credential: authenticator.get(t, login.Options, user.ID),
+	})
+	assert.ErrorIs(t, err, errs.ErrInvalidCeremony, "a login session must only be finished once")
+}
+
+func TestWebAuthn_LoginRejectsUnknownPasskey(t *testing.T) {
+	service, user, _ := newWebAuthnTestService(t)
+
+	login, err := service.BeginWebAuthnLogin(context.Background())
+	require.NoError(t, err)
+
+	_, err = service.FinishWebAuthnLogin(context.Background(), dto.FinishWebAuthnLoginReq{
+		SessionID:  login.SessionID,
+		Credential: newSoftwareAuthenticator(t).get(t, login.Options, user.ID),
+	})
+	assert.ErrorIs(t, err, errs.ErrInvalidCredentials)
+}
+
+func TestWebAuthn_RegistrationSessionOfAnotherUser(t *testing.T) {
+	service, user, _ := newWebAuthnTestService(t)
+
+	registration, err := service.BeginWebAuthnRegistration(cx.WithAuthUserID(context.Background(), user.ID))
+	require.NoError(t, err)
+
+	other, err := domain.NewUserWithPassword(nil, stringPtr("other@example.com"), "Password123!", "otheruser", nil, nil)
+	require.NoError(t, err)
+	service.userRepo.(*stubUserRepository).usersByID[other.ID] = other
+
+	_, err = service.FinishWebAuthnRegistration(cx.WithAuthUserID(context.Background(), other.ID), dto.FinishWebAuthnRegistrationReq{
+		SessionID:  registration.SessionID,
+		Credential: newSoftwareAuthenticator(t).create(t, registration.Options),
+	})
+	assert.ErrorIs(t, err, errs.ErrInvalidCeremony)
+}
+
+func TestWebAuthn_Disabled(t *testing.T) {
+	service, user, _ := newWebAuthnTestService(t)
+	service.webAuthn = nil
+	authCtx := cx.WithAuthUserID(context.Background(), user.ID)
+
+	_, err := service.BeginWebAuthnRegistration(authCtx)
+	assert.ErrorIs(t, err, errs.ErrWebAuthnDisabled)
+	_, err = service.FinishWebAuthnRegistration(authCtx, dto.FinishWebAuthnRegistrationReq{})
+	assert.ErrorIs(t, err, errs.ErrWebAuthnDisabled)
+	_, err = service.BeginWebAuthnLogin(context.Background())
+	assert.ErrorIs(t, err, errs.ErrWebAuthnDisabled)
+	_, err = service.FinishWebAuthnLogin(context.Background(), dto.FinishWebAuthnLoginReq{})
+	assert.ErrorIs(t, err, errs.ErrWebAuthnDisabled)
+
+	webAuthn, err := NewWebAuthn(&config.WebAuthnConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, webAuthn, "no relying party is created while WebAuthn is disabled")
+}
@@ -3,58 +3,219 @@ package workers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 	"wallet-user-svc/internal/app/model/domain"
 	"wallet-user-svc/internal/app/model/dto"
 	"wallet-user-svc/internal/app/model/events"
+	"wallet-user-svc/pkg/utils/cx"
 	"github.com/google/uuid"
-	"github.com/hibiken/asynq"
 	"github.com/sirupsen/logrus"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// NotificationWorkerHealthService is the health check service name the worker reports under
+const NotificationWorkerHealthService = "notification-worker"
+
+// DefaultMaxBatchSize caps the events loaded per poll when no explicit cap is configured
+const DefaultMaxBatchSize = 5000
+
+// HealthReporter receives the worker's serving status so a persistently failing
+// worker is visible through the gRPC health service
+type HealthReporter interface {
+	SetServingStatus(service string, servingStatus healthpb.HealthCheckResponse_ServingStatus)
+}
+
 type NotificationRepository interface {
 	FindPendingEvents(ctx context.Context, eventName string, batchSize int) ([]*domain.NotificationEventLog, error)
 	UpdateStatusSuccess(ctx context.Context, id string) error
+	DeletePublishedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	UpdateStatusFailed(ctx context.Context, id string, nextRetryAt time.Time) error
+	MarkChannelDelivered(ctx context.Context, id string, channel string) error
+	FindRetryableEvents(ctx context.Context, maxAttempts int, batchSize int) ([]*domain.NotificationEventLog, error)
+}
+
+// CleanupOptions configures pruning of published events. A zero Retention disables it.
+type CleanupOptions struct {
+	// Retention is how long successfully published events are kept
+	Retention time.Duration
+	// Interval is how often the cleanup runs
+	Interval time.Duration
+	// BatchSize bounds the rows deleted per statement
+	BatchSize int
+}
+
+// RetryOptions configures the sweeper that retries failed events. A zero Interval
+// disables it; a failed send then leaves its event pending for the next poll.
+type RetryOptions struct {
+	// Interval is how often failed events are looked up
+	Interval time.Duration
+	// MaxAttempts is the number of failed attempts after which an event stays failed
+	MaxAttempts int
+	// Backoff is the wait before the first retry; it doubles with every further failure
+	Backoff time.Duration
+	// BatchSize bounds the events retried per sweep
+	BatchSize int
+}
+
+// maxBackoffDoublings bounds the retry backoff growth so the delay cannot overflow
+const maxBackoffDoublings = 10
+
+// enabled reports whether failed events are handed to the retry sweeper
+func (o RetryOptions) enabled() bool {
+	return o.Interval > 0
+}
+
+// delay returns the wait before retrying an event that has failed attempts times
+func (o RetryOptions) delay(attempts int) time.Duration {
+	return o.Backoff << min(max(attempts-1, 0), maxBackoffDoublings)
+}
+
+// TemplateOptions configures the template metadata attached to notification tasks
+type TemplateOptions struct {
+	// SenderEmail and SenderName identify who notifications are sent from
+	SenderEmail string
+	SenderName  string
+	// Templates maps event types to the template the consumer renders
+	Templates map[events.EventType]string
+}
+
+// For returns the template metadata of an event type
+func (o TemplateOptions) For(eventType events.EventType) events.NotificationTemplate {
+	return events.NotificationTemplate{
+		TemplateID:  o.Templates[eventType],
+		SenderEmail: o.SenderEmail,
+		SenderName:  o.SenderName,
+	}
+}
+
+// ReadinessChecker reports the serving status of a health check service. The gRPC
+// health server implements it.
+type ReadinessChecker interface {
+	Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error)
+}
+
+// StartupOptions postpones the first processing run. The zero value starts immediately.
+type StartupOptions struct {
+	// Delay is waited before the first run
+	Delay time.Duration
+	// Readiness, when set, is polled after Delay until Service reports SERVING
+	Readiness ReadinessChecker
+	// Service is the health check service to wait for; "" is the overall server
+	Service string
+	// PollInterval is how often Readiness is polled; defaults to one second
+	PollInterval time.Duration
+}
+
+// BatchResult counts the outcomes of the events processed in one poll
+type BatchResult struct {
+	Succeeded int
+	Failed    int
+	// Retried counts the events that had already failed in an earlier poll, whatever
+	// their outcome in this one
+	Retried int
+	// Skipped counts the events left unprocessed because the worker was stopped
+	Skipped int
+}
+
+// Add accumulates other into r
+func (r *BatchResult) Add(other BatchResult) {
+	r.Succeeded += other.Succeeded
+	r.Failed += other.Failed
+	r.Retried += other.Retried
+	r.Skipped += other.Skipped
 }
 
 type NotificationWorker struct {
 	logger                   *logrus.Logger
-	asyncQClient             *asynq.Client
 	notificationEventLogRepo NotificationRepository
 	ticker                   *time.Ticker
 	wg                       *sync.WaitGroup
 	interval                 time.Duration
-	maxRetries               int
 	batchSize                int
-	shutdownChan             chan struct{}
+	notifiers                map[events.EventType][]Notifier
+	failureThreshold         int
+	consecutiveFailures      int
+	healthReporter           HealthReporter
+	cleanup                  CleanupOptions
+	startup                  StartupOptions
+	templates                TemplateOptions
+	sendTimeout              time.Duration
+	retry                    RetryOptions
+	// attempts counts processing attempts per pending event within this process
+	attempts     map[string]int
+	// totals accumulates the batch results since the worker was created
+	totals       BatchResult
+	totalsMu     sync.Mutex
+	// processing is set while processPendingEvents runs so an overlapping call is skipped
+	processing   atomic.Bool
+	shutdownChan chan struct{}
 	shutdownOnce             sync.Once
 }
 
 func NewNotificationWorker(
 	logger *logrus.Logger,
-	asyncQClient *asynq.Client,
 	notificationEventLogRepo NotificationRepository,
 	wg *sync.WaitGroup,
 	interval time.Duration,
-	maxRetries int,
 	batchSize int,
+	maxBatchSize int,
+	notifiers map[events.EventType][]Notifier,
+	failureThreshold int,
+	healthReporter HealthReporter,
+	cleanup CleanupOptions,
+	startup StartupOptions,
+	templates TemplateOptions,
+	sendTimeout time.Duration,
+	retry RetryOptions,
 ) *NotificationWorker {
 	ticker := time.NewTicker(interval)
 
 	return &NotificationWorker{
 		logger:                   logger,
-		asyncQClient:             asyncQClient,
 		notificationEventLogRepo: notificationEventLogRepo,
 		interval:                 interval,
 		ticker:                   ticker,
 		wg:                       wg,
-		maxRetries:               maxRetries,
-		batchSize:                batchSize,
+		batchSize:                clampBatchSize(batchSize, maxBatchSize, logger),
+		notifiers:                notifiers,
+		failureThreshold:         failureThreshold,
+		healthReporter:           healthReporter,
+		cleanup:                  cleanup,
+		startup:                  startup,
+		templates:                templates,
+		sendTimeout:              sendTimeout,
+		retry:                    retry,
+		attempts:                 make(map[string]int),
 		shutdownChan:             make(chan struct{}),
 	}
 }
 
+// clampBatchSize bounds the poll batch size to [1, maxBatchSize] so a configuration
+// mistake cannot load an unbounded number of events into memory
+func clampBatchSize(batchSize, maxBatchSize int, logger *logrus.Logger) int {
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+
+	switch {
+	case batchSize > maxBatchSize:
+		logger.WithFields(logrus.Fields{
+			"batch_size":     batchSize,
+			"max_batch_size": maxBatchSize,
+		}).Warn("Notification batch size exceeds the cap; clamping")
+		return maxBatchSize
+	case batchSize <= 0:
+		logger.WithField("batch_size", batchSize).Warn("Notification batch size must be positive; using 1")
+		return 1
+	}
+	return batchSize
+}
+
 func (s *NotificationWorker) Start(ctx context.Context) {
 	s.logger.Info("Starting notification worker")
 
@@ -66,8 +227,24 @@ func (s *NotificationWorker) Start(ctx context.Context) {
 			s.logger.Info("Notification worker stopped")
 		}()
 
+		// Hold the first run until the configured delay has passed and the
+		// dependencies report ready
+		if !s.waitForStartup(ctx) {
+			s.logger.Info("Notification worker stopped before its first run")
+			return
+		}
+
+		// Failed events are retried on their own ticker but from this goroutine, so a
+		// retry never runs alongside a poll
+		var retryTicks <-chan time.Time
+		if s.retry.enabled() {
+			retryTicker := time.NewTicker(s.retry.Interval)
+			defer retryTicker.Stop()
+			retryTicks = retryTicker.C
+		}
+
 		// Process events immediately on startup
-		s.processPendingLoginEvents(ctx)
+		s.processPendingEvents(ctx)
 
 		for {
 			select {
@@ -82,12 +259,123 @@ func (s *NotificationWorker) Start(ctx context.Context) {
 				s.processRemainingEvents()
 				return
 			case <-s.ticker.C:
-				s.processPendingLoginEvents(ctx)
+				s.processPendingEvents(ctx)
+			case <-retryTicks:
+				s.retryFailedEvents(ctx)
+			}
+		}
+	}()
+
+	if s.cleanup.Retention > 0 && s.cleanup.Interval > 0 {
+		s.startCleanup(ctx)
+	}
+}
+
+// waitForStartup blocks until the startup delay has elapsed and, when a readiness
+// checker is set, its service reports SERVING. It returns false if the worker is
+// stopped first.
+func (s *NotificationWorker) waitForStartup(ctx context.Context) bool {
+	if s.startup.Delay > 0 {
+		s.logger.WithField("delay", s.startup.Delay).Info("Delaying notification worker startup")
+		if !s.sleep(ctx, s.startup.Delay) {
+			return false
+		}
+	}
+
+	if s.startup.Readiness == nil {
+		return true
+	}
+
+	pollInterval := s.startup.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	req := &healthpb.HealthCheckRequest{Service: s.startup.Service}
+	for {
+		resp, err := s.startup.Readiness.Check(ctx, req)
+		if err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING {
+			return true
+		}
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"service": s.startup.Service,
+			"status":  resp.GetStatus().String(),
+		}).Debug("Waiting for dependencies before processing notification events")
+
+		if !s.sleep(ctx, pollInterval) {
+			return false
+		}
+	}
+}
+
+// sleep waits for d and reports whether the worker is still running
+func (s *NotificationWorker) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.shutdownChan:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// startCleanup runs the published event cleanup on its own ticker so long deletes
+// never delay event processing
+func (s *NotificationWorker) startCleanup(ctx context.Context) {
+	s.logger.WithFields(logrus.Fields{
+		"retention": s.cleanup.Retention,
+		"interval":  s.cleanup.Interval,
+	}).Info("Starting notification event cleanup")
+
+	s.wg.Add(1)
+	go func() {
+		ticker := time.NewTicker(s.cleanup.Interval)
+		defer func() {
+			ticker.Stop()
+			s.wg.Done()
+			s.logger.Info("Notification event cleanup stopped")
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.shutdownChan:
+				return
+			case <-ticker.C:
+				s.deletePublishedEvents(ctx)
 			}
 		}
 	}()
 }
 
+// deletePublishedEvents prunes published events older than the retention period in
+// batches until none are left or the context is cancelled
+func (s *NotificationWorker) deletePublishedEvents(ctx context.Context) {
+	cutoff := time.Now().Add(-s.cleanup.Retention)
+
+	var pruned int64
+	for ctx.Err() == nil {
+		deleted, err := s.notificationEventLogRepo.DeletePublishedBefore(ctx, cutoff, s.cleanup.BatchSize)
+		if err != nil {
+			s.logger.WithError(err).WithField("pruned", pruned).Error("Could not delete published events")
+			return
+		}
+		pruned += deleted
+		if deleted < int64(s.cleanup.BatchSize) {
+			break
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"pruned": pruned,
+		"cutoff": cutoff,
+	}).Info("Pruned published notification events")
+}
+
 func (s *NotificationWorker) processRemainingEvents() {
 	s.logger.Info("Processing remaining events before shutdown")
 
@@ -95,104 +383,413 @@ func (s *NotificationWorker) processRemainingEvents() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	s.processPendingLoginEvents(ctx)
+	s.processPendingEvents(ctx)
 }
 
-func (s *NotificationWorker) processPendingLoginEvents(ctx context.Context) {
-	s.logger.Debug("Processing pending login events")
+// deliveredEventTypes are the notification events the worker publishes. Verification
+// codes come first so they are not held up behind a backlog of login notifications.
+var deliveredEventTypes = []events.EventType{
+	events.EmailVerificationEventType,
+	events.PhoneVerificationEventType,
+	events.LoginEventType,
+}
 
-	events, err := s.notificationEventLogRepo.FindPendingEvents(
-		ctx,
-		string(events.LoginEventType),
-		s.batchSize,
-	)
-	if err != nil {
-		s.logger.WithError(err).Error("Could not find pending events")
+// processPendingEvents publishes up to a batch of pending events across the delivered
+// event types. The types are fetched in order and each is only asked for the room left
+// in the batch, so a poll never handles more than batchSize events. A poll counts as a
+// single fetch failure however many lookups failed.
+// A call made while an earlier batch is still running is skipped rather than run
+// alongside it, so a batch slower than the interval never overlaps the next tick.
+func (s *NotificationWorker) processPendingEvents(ctx context.Context) {
+	if !s.processing.CompareAndSwap(false, true) {
+		s.logger.WithField("interval", s.interval.String()).Warn("Skipping notification poll; the previous batch is still running")
 		return
 	}
+	defer s.processing.Store(false)
 
-	if len(events) == 0 {
+	s.logger.Debug("Processing pending notification events")
+
+	var pending []*domain.NotificationEventLog
+	for _, eventType := range deliveredEventTypes {
+		remaining := s.batchSize - len(pending)
+		if remaining <= 0 {
+			break
+		}
+		found, err := s.notificationEventLogRepo.FindPendingEvents(ctx, string(eventType), remaining)
+		if err != nil {
+			s.recordFetchFailure(err)
+			return
+		}
+		pending = append(pending, found...)
+	}
+	s.recordFetchSuccess()
+
+	if len(pending) == 0 {
 		s.logger.Debug("No pending events found")
 		return
 	}
 
-	s.logger.WithField("count", len(events)).Info("Found pending events to process")
+	s.logger.WithField("count", len(pending)).Info("Found pending events to process")
 
-	// Process events sequentially in a single thread
-	for _, event := range events {
+	result := s.processBatch(ctx, pending)
+	s.recordBatchResult(len(pending), result)
+}
+
+// retryFailedEvents re-sends up to a batch of failed events whose retry time has come.
+// An event failing again is rescheduled with a longer backoff until it has used up
+// its attempts.
+func (s *NotificationWorker) retryFailedEvents(ctx context.Context) {
+	s.logger.Debug("Retrying failed notification events")
+
+	failed, err := s.notificationEventLogRepo.FindRetryableEvents(ctx, s.retry.MaxAttempts, s.retry.BatchSize)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch failed notification events to retry")
+		return
+	}
+	if len(failed) == 0 {
+		return
+	}
+
+	s.logger.WithField("count", len(failed)).Info("Found failed events to retry")
+
+	result := s.processBatch(ctx, failed)
+	s.recordBatchResult(len(failed), result)
+}
+
+// processBatch processes events sequentially in a single thread. A failed event is
+// counted and left pending, or handed to the retry sweeper; it never stops the rest
+// of the batch.
+func (s *NotificationWorker) processBatch(ctx context.Context, events []*domain.NotificationEventLog) BatchResult {
+	var result BatchResult
+	for i, event := range events {
 		// Check for context cancellation before processing each event
-		select {
-		case <-ctx.Done():
+		if ctx.Err() != nil {
 			s.logger.Info("Context cancelled, stopping event processing")
-			return
-		default:
+			result.Skipped = len(events) - i
+			break
 		}
 
+		if s.attempts[event.ID] > 0 || event.Attempts > 0 {
+			result.Retried++
+		}
 		if err := s.processEvent(ctx, event); err != nil {
-			s.logger.WithError(err).WithField("eventID", event.ID).Error("Failed to process event")
+			result.Failed++
+			continue
 		}
+		result.Succeeded++
+	}
+	return result
+}
+
+// recordBatchResult logs a single summary of a poll and adds it to the totals
+func (s *NotificationWorker) recordBatchResult(count int, result BatchResult) {
+	s.totalsMu.Lock()
+	s.totals.Add(result)
+	s.totalsMu.Unlock()
+
+	logger := s.logger.WithFields(logrus.Fields{
+		"count":     count,
+		"succeeded": result.Succeeded,
+		"failed":    result.Failed,
+		"retried":   result.Retried,
+		"skipped":   result.Skipped,
+	})
+	if result.Failed > 0 {
+		logger.Warn("Processed pending events with failures")
+		return
 	}
+	logger.Info("Processed pending events")
+}
 
-	s.logger.WithField("count", len(events)).Info("Processed pending events")
+// Totals returns the accumulated batch results of the worker
+func (s *NotificationWorker) Totals() BatchResult {
+	s.totalsMu.Lock()
+	defer s.totalsMu.Unlock()
+	return s.totals
 }
 
+// processEvent publishes a single event, returning why it is still pending on failure.
+// It runs with a context logger carrying the event ID, name and attempt so every log
+// line of the event can be correlated.
 func (s *NotificationWorker) processEvent(ctx context.Context, event *domain.NotificationEventLog) error {
-	var params dto.SendLoginNotificationParams
-	if err := json.Unmarshal(event.Payload, &params); err != nil {
-		s.logger.WithError(err).WithField("eventID", event.ID).Error("Could not unmarshal payload")
-		return err
+	s.attempts[event.ID]++
+	ctx = cx.WithLogger(ctx, s.logger.WithFields(logrus.Fields{
+		"event_id":   event.ID,
+		"event_name": event.EventName,
+		"attempt":    s.attempts[event.ID],
+	}))
+	logger := cx.GetLoggerOrDefault(ctx)
+
+	send, err := s.sender(event)
+	if err != nil {
+		logger.WithError(err).Error("Could not unmarshal payload")
+		s.scheduleRetry(ctx, event)
+		return fmt.Errorf("unmarshal payload: %w", err)
 	}
 
-	// Send notification
-	if err := s.SendLoginNotification(ctx, &params); err != nil {
-		s.logger.WithError(err).WithField("eventID", event.ID).Error("Failed to send login notification")
-		return err
+	// Each attempt has its own deadline so one slow enqueue cannot stall the batch; an
+	// attempt that times out is a failure like any other and leaves the event pending
+	sendCtx, cancel := s.withSendTimeout(ctx)
+	err = send(sendCtx)
+	cancel()
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			logger.WithField("timeout", s.sendTimeout).Warn("Sending notification timed out")
+		} else {
+			logger.WithError(err).Error("Failed to send notification")
+		}
+		s.scheduleRetry(ctx, event)
+		return fmt.Errorf("send %s notification: %w", event.EventName, err)
 	}
 
 	// Update status to success
 	if err := s.notificationEventLogRepo.UpdateStatusSuccess(ctx, event.ID); err != nil {
-		s.logger.WithError(err).WithField("eventID", event.ID).Error("Could not update status")
-		return err
+		logger.WithError(err).Error("Could not update status")
+		return fmt.Errorf("update status: %w", err)
 	}
 
-	s.logger.WithField("eventID", event.ID).Debug("Event processed successfully")
-
+	delete(s.attempts, event.ID)
+	logger.Debug("Event processed successfully")
 	return nil
 }
 
+// scheduleRetry hands a failed event to the retry sweeper by marking it failed with the
+// time of its next retry. Without the sweeper, or if the update fails, the event stays
+// pending for the next poll.
+func (s *NotificationWorker) scheduleRetry(ctx context.Context, event *domain.NotificationEventLog) {
+	if !s.retry.enabled() {
+		return
+	}
+
+	logger := cx.GetLoggerOrDefault(ctx)
+	attempts := event.Attempts + 1
+	nextRetryAt := time.Now().Add(s.retry.delay(attempts))
+	if err := s.notificationEventLogRepo.UpdateStatusFailed(ctx, event.ID, nextRetryAt); err != nil {
+		logger.WithError(err).Error("Could not mark event failed, leaving it pending")
+		return
+	}
+	delete(s.attempts, event.ID)
+
+	if attempts >= s.retry.MaxAttempts {
+		logger.WithField("attempts", attempts).Error("Notification event used up its retries and stays failed")
+		return
+	}
+	logger.WithFields(logrus.Fields{
+		"attempts":      attempts,
+		"next_retry_at": nextRetryAt,
+	}).Warn("Notification event failed, scheduled for retry")
+}
+
+// sender decodes the payload of event and returns the call that sends it. The call
+// skips the channels the event was already delivered to and records each new delivery.
+func (s *NotificationWorker) sender(event *domain.NotificationEventLog) (func(context.Context) error, error) {
+	switch eventType := events.EventType(event.EventName); eventType {
+	case events.EmailVerificationEventType, events.PhoneVerificationEventType:
+		var params dto.SendContactVerificationParams
+		if err := json.Unmarshal(event.Payload, &params); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) error {
+			notification, err := s.contactVerificationNotification(ctx, eventType, &params)
+			if err != nil {
+				return err
+			}
+			return s.dispatch(ctx, notification, event)
+		}, nil
+	default:
+		var params dto.SendLoginNotificationParams
+		if err := json.Unmarshal(event.Payload, &params); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) error {
+			notification, err := s.loginNotification(ctx, &params)
+			if err != nil {
+				return err
+			}
+			return s.dispatch(ctx, notification, event)
+		}, nil
+	}
+}
+
+// withSendTimeout bounds a send attempt by the configured timeout; a non-positive
+// timeout leaves the attempt bound only by ctx
+func (s *NotificationWorker) withSendTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.sendTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.sendTimeout)
+}
+
 func (s *NotificationWorker) SendLoginNotification(
 	ctx context.Context,
 	params *dto.SendLoginNotificationParams,
 ) error {
+	notification, err := s.loginNotification(ctx, params)
+	if err != nil {
+		return err
+	}
+	return s.dispatch(ctx, notification, nil)
+}
+
+// loginNotification builds the notification of a login event
+func (s *NotificationWorker) loginNotification(
+	ctx context.Context,
+	params *dto.SendLoginNotificationParams,
+) (*Notification, error) {
 	loginEvent := events.LoginEvent{
 		EventMetadata: events.EventMetadata{
 			EventID:   uuid.New().String(),
 			EventName: string(events.LoginEventType),
 		},
 		UserID:   params.UserID,
-		Email:    params.Email,
-		Username: params.Username,
-		LoginAt:  params.LoginAt,
+		Email:       params.Email,
+		CountryCode: params.CountryCode,
+		Phone:       params.Phone,
+		Username:    params.Username,
+		LoginAt:     params.LoginAt,
 	}
 
-	task, err := loginEvent.ToTask()
+	task, err := loginEvent.ToTask(s.templates.For(events.LoginEventType))
 	if err != nil {
-		s.logger.WithError(err).Error("Could not create task")
-		return err
+		cx.GetLoggerOrDefault(ctx).WithError(err).Error("Could not marshal login event")
+		return nil, err
 	}
 
-	info, err := s.asyncQClient.Enqueue(task, asynq.MaxRetry(s.maxRetries))
+	return &Notification{
+		EventType: events.LoginEventType,
+		EventID:   loginEvent.EventMetadata.EventID,
+		Payload:   task.Payload(),
+	}, nil
+}
+
+// SendContactVerification sends the code of an email or phone verification event to the
+// contact being verified
+func (s *NotificationWorker) SendContactVerification(
+	ctx context.Context,
+	eventType events.EventType,
+	params *dto.SendContactVerificationParams,
+) error {
+	notification, err := s.contactVerificationNotification(ctx, eventType, params)
 	if err != nil {
-		s.logger.WithError(err).Error("Could not enqueue task")
 		return err
 	}
+	return s.dispatch(ctx, notification, nil)
+}
 
-	s.logger.WithFields(logrus.Fields{
-		"id":    info.ID,
-		"queue": info.Queue,
-	}).Debug("Enqueued task")
+// contactVerificationNotification builds the notification of an email or phone
+// verification event
+func (s *NotificationWorker) contactVerificationNotification(
+	ctx context.Context,
+	eventType events.EventType,
+	params *dto.SendContactVerificationParams,
+) (*Notification, error) {
+	verificationEvent := events.ContactVerificationEvent{
+		EventMetadata: events.EventMetadata{
+			EventID:   uuid.New().String(),
+			EventName: string(eventType),
+		},
+		UserID:         params.UserID,
+		Username:       params.Username,
+		VerificationID: params.VerificationID,
+		Email:          params.Email,
+		CountryCode:    params.CountryCode,
+		Phone:          params.Phone,
+		Code:           params.Code,
+		ExpiresAt:      params.ExpiresAt,
+	}
 
-	return nil
+	task, err := verificationEvent.ToTask(eventType, s.templates.For(eventType))
+	if err != nil {
+		cx.GetLoggerOrDefault(ctx).WithError(err).Error("Could not marshal contact verification event")
+		return nil, err
+	}
+
+	return &Notification{
+		EventType: eventType,
+		EventID:   verificationEvent.EventMetadata.EventID,
+		Payload:   task.Payload(),
+	}, nil
+}
+
+// dispatch delivers the notification to every notifier configured for its event type.
+// All notifiers are attempted; the event stays pending if any of them fails. When the
+// notification comes from a stored event, channels the event was already delivered to
+// are skipped and each successful channel is recorded on it, so a retry only resends
+// to the channels that failed.
+func (s *NotificationWorker) dispatch(ctx context.Context, notification *Notification, event *domain.NotificationEventLog) error {
+	logger := cx.GetLoggerOrDefault(ctx)
+
+	notifiers := s.notifiers[notification.EventType]
+	if len(notifiers) == 0 {
+		logger.WithField("eventType", notification.EventType).Warn("No notifiers configured for event type")
+		return nil
+	}
+
+	var errs []error
+	for _, notifier := range notifiers {
+		channel := notifier.Channel()
+		if event != nil && slices.Contains(event.DeliveredChannels, channel) {
+			logger.WithField("channel", channel).Debug("Notification already delivered over channel, skipping")
+			continue
+		}
+
+		if err := notifier.Notify(ctx, notification); err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"eventID": notification.EventID,
+				"channel": channel,
+			}).Error("Could not deliver notification")
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+			continue
+		}
+
+		if event != nil {
+			event.DeliveredChannels = append(event.DeliveredChannels, channel)
+			// Failing to record the delivery only means a retry may send it again
+			if err := s.notificationEventLogRepo.MarkChannelDelivered(ctx, event.ID, channel); err != nil {
+				logger.WithError(err).WithField("channel", channel).Warn("Could not record notification delivery")
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// recordFetchFailure tracks consecutive FindPendingEvents failures. Once the failure
+// threshold is reached the worker escalates the log and reports itself as not serving.
+func (s *NotificationWorker) recordFetchFailure(err error) {
+	s.consecutiveFailures++
+
+	logger := s.logger.WithError(err).WithField("consecutiveFailures", s.consecutiveFailures)
+	if s.failureThreshold <= 0 || s.consecutiveFailures < s.failureThreshold {
+		logger.Error("Could not find pending events")
+		return
+	}
+
+	if s.consecutiveFailures == s.failureThreshold {
+		logger.WithField("threshold", s.failureThreshold).
+			Error("Notification worker is persistently failing to fetch pending events, marking unhealthy")
+		s.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		return
+	}
+
+	logger.Error("Could not find pending events, notification worker still unhealthy")
+}
+
+// recordFetchSuccess resets the failure count and restores the serving status after an outage
+func (s *NotificationWorker) recordFetchSuccess() {
+	if s.failureThreshold > 0 && s.consecutiveFailures >= s.failureThreshold {
+		s.logger.WithField("consecutiveFailures", s.consecutiveFailures).
+			Info("Notification worker recovered, fetching pending events again")
+		s.setServingStatus(healthpb.HealthCheckResponse_SERVING)
+	}
+	s.consecutiveFailures = 0
+}
+
+func (s *NotificationWorker) setServingStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	if s.healthReporter == nil {
+		return
+	}
+	s.healthReporter.SetServingStatus(NotificationWorkerHealthService, status)
 }
 
 // Stop gracefully stops the worker
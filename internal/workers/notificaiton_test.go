@@ -0,0 +1,478 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/internal/app/model/events"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type stubNotificationRepository struct {
+	err     error
+	pending []*domain.NotificationEventLog
+	// published holds the number of published rows left to delete
+	published int64
+	cutoffs   []time.Time
+	succeeded []string
+	// retryable is returned to the retry sweeper and failed records each rescheduled
+	// event with its next retry time
+	retryable []*domain.NotificationEventLog
+	failed    map[string]time.Time
+	// delivered records the channels marked delivered per event
+	delivered map[string][]string
+}
+
+func (r *stubNotificationRepository) FindPendingEvents(_ context.Context, eventName string, batchSize int) ([]*domain.NotificationEventLog, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var pending []*domain.NotificationEventLog
+	for _, event := range r.pending {
+		if event.EventName == eventName && len(pending) < batchSize {
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+func (r *stubNotificationRepository) UpdateStatusSuccess(_ context.Context, id string) error {
+	r.succeeded = append(r.succeeded, id)
+	return nil
+}
+
+func (r *stubNotificationRepository) UpdateStatusFailed(_ context.Context, id string, nextRetryAt time.Time) error {
+	if r.failed == nil {
+		r.failed = make(map[string]time.Time)
+	}
+	r.failed[id] = nextRetryAt
+	return nil
+}
+
+func (r *stubNotificationRepository) MarkChannelDelivered(_ context.Context, id string, channel string) error {
+	if r.delivered == nil {
+		r.delivered = make(map[string][]string)
+	}
+	r.delivered[id] = append(r.delivered[id], channel)
+	return nil
+}
+
+func (r *stubNotificationRepository) FindRetryableEvents(_ context.Context, _ int, _ int) ([]*domain.NotificationEventLog, error) {
+	return r.retryable, r.err
+}
+
+func (r *stubNotificationRepository) DeletePublishedBefore(_ context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.cutoffs = append(r.cutoffs, cutoff)
+	deleted := min(r.published, int64(limit))
+	r.published -= deleted
+	return deleted, nil
+}
+
+type recordingHealthReporter struct {
+	statuses []healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (r *recordingHealthReporter) SetServingStatus(_ string, status healthpb.HealthCheckResponse_ServingStatus) {
+	r.statuses = append(r.statuses, status)
+}
+
+func TestProcessPendingEvents_ConsecutiveFailures(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repo := &stubNotificationRepository{err: errors.New("connection refused")}
+	reporter := &recordingHealthReporter{}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0, nil, 3, reporter, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+
+	ctx := context.Background()
+	worker.processPendingEvents(ctx)
+	worker.processPendingEvents(ctx)
+	assert.Empty(t, reporter.statuses, "should not report unhealthy before the threshold")
+
+	worker.processPendingEvents(ctx)
+	worker.processPendingEvents(ctx)
+	assert.Equal(t, []healthpb.HealthCheckResponse_ServingStatus{healthpb.HealthCheckResponse_NOT_SERVING}, reporter.statuses)
+	assert.Equal(t, 4, worker.consecutiveFailures)
+
+	repo.err = nil
+	worker.processPendingEvents(ctx)
+	assert.Equal(t, []healthpb.HealthCheckResponse_ServingStatus{
+		healthpb.HealthCheckResponse_NOT_SERVING,
+		healthpb.HealthCheckResponse_SERVING,
+	}, reporter.statuses)
+	assert.Zero(t, worker.consecutiveFailures)
+}
+
+// blockingNotificationRepository holds FindPendingEvents until release is closed
+type blockingNotificationRepository struct {
+	stubNotificationRepository
+	entered chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (r *blockingNotificationRepository) FindPendingEvents(ctx context.Context, eventName string, limit int) ([]*domain.NotificationEventLog, error) {
+	if r.calls.Add(1) == 1 {
+		close(r.entered)
+		<-r.release
+	}
+	return r.stubNotificationRepository.FindPendingEvents(ctx, eventName, limit)
+}
+
+func TestProcessPendingEvents_SkipsWhileBatchRunning(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+	repo := &blockingNotificationRepository{entered: make(chan struct{}), release: make(chan struct{})}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0, nil, 3, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		worker.processPendingEvents(context.Background())
+	}()
+	<-repo.entered
+
+	worker.processPendingEvents(context.Background())
+	assert.Equal(t, int32(1), repo.calls.Load(), "the overlapping poll must not query the repository")
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, "Skipping notification poll; the previous batch is still running", hook.LastEntry().Message)
+
+	close(repo.release)
+	<-done
+
+	worker.processPendingEvents(context.Background())
+	assert.Greater(t, repo.calls.Load(), int32(len(deliveredEventTypes)), "polls run again once the batch finished")
+}
+
+func TestDeletePublishedEvents_DeletesInBatches(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repo := &stubNotificationRepository{published: 25}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0, nil, 0, nil, CleanupOptions{
+		Retention: 24 * time.Hour,
+		Interval:  time.Hour,
+		BatchSize: 10,
+	}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+
+	worker.deletePublishedEvents(context.Background())
+
+	assert.Zero(t, repo.published)
+	assert.Len(t, repo.cutoffs, 3, "should delete batches until a partial batch is returned")
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), repo.cutoffs[0], time.Minute)
+}
+
+func TestClampBatchSize(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	assert.Equal(t, 100, clampBatchSize(100, 5000, logger))
+	assert.Equal(t, 5000, clampBatchSize(1_000_000, 5000, logger))
+	assert.Equal(t, DefaultMaxBatchSize, clampBatchSize(1_000_000, 0, logger))
+	assert.Equal(t, 1, clampBatchSize(0, 5000, logger))
+	assert.Equal(t, 1, clampBatchSize(-10, 5000, logger))
+}
+
+func TestProcessEvent_LogsCarryEventFields(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+
+	failing := &recordingNotifier{channel: ChannelEmail, err: errors.New("smtp down")}
+	worker := NewNotificationWorker(logger, &stubNotificationRepository{}, &sync.WaitGroup{}, time.Minute, 10, 0,
+		map[events.EventType][]Notifier{events.LoginEventType: {failing}}, 0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+	event := &domain.NotificationEventLog{
+		ID:        "event-1",
+		EventName: string(events.LoginEventType),
+		Payload:   []byte(`{"userId":"user-123"}`),
+	}
+
+	worker.processEvent(context.Background(), event)
+	worker.processEvent(context.Background(), event)
+
+	entries := hook.AllEntries()
+	require.NotEmpty(t, entries)
+	for _, entry := range entries {
+		assert.Equal(t, "event-1", entry.Data["event_id"])
+		assert.Equal(t, string(events.LoginEventType), entry.Data["event_name"])
+	}
+	assert.Equal(t, 2, hook.LastEntry().Data["attempt"])
+
+	failing.err = nil
+	worker.processEvent(context.Background(), event)
+	assert.NotContains(t, worker.attempts, "event-1", "attempts are forgotten once the event is published")
+}
+
+type countingReadinessChecker struct {
+	// ready is the number of checks after which the service reports SERVING
+	ready  int
+	checks int
+}
+
+func (c *countingReadinessChecker) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	c.checks++
+	if c.checks < c.ready {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func TestWaitForStartup(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	newWorker := func(startup StartupOptions) *NotificationWorker {
+		return NewNotificationWorker(logger, &stubNotificationRepository{}, &sync.WaitGroup{}, time.Minute, 10, 0, nil, 0, nil, CleanupOptions{}, startup, TemplateOptions{}, time.Second, RetryOptions{})
+	}
+
+	t.Run("starts immediately by default", func(t *testing.T) {
+		assert.True(t, newWorker(StartupOptions{}).waitForStartup(context.Background()))
+	})
+
+	t.Run("waits for the delay", func(t *testing.T) {
+		start := time.Now()
+		assert.True(t, newWorker(StartupOptions{Delay: 20 * time.Millisecond}).waitForStartup(context.Background()))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("polls readiness until serving", func(t *testing.T) {
+		readiness := &countingReadinessChecker{ready: 3}
+		worker := newWorker(StartupOptions{Readiness: readiness, PollInterval: time.Millisecond})
+
+		assert.True(t, worker.waitForStartup(context.Background()))
+		assert.Equal(t, 3, readiness.checks)
+	})
+
+	t.Run("gives up when stopped", func(t *testing.T) {
+		worker := newWorker(StartupOptions{Readiness: &countingReadinessChecker{ready: 1 << 30}, PollInterval: time.Millisecond})
+		worker.Stop()
+
+		assert.False(t, worker.waitForStartup(context.Background()))
+	})
+}
+
+func TestProcessPendingEvents_PartialBatchFailure(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+
+	repo := &stubNotificationRepository{pending: []*domain.NotificationEventLog{
+		{ID: "event-1", EventName: string(events.LoginEventType), Payload: []byte(`{"userId":"user-1"}`)},
+		{ID: "event-2", EventName: string(events.LoginEventType), Payload: []byte(`not json`)},
+		{ID: "event-3", EventName: string(events.LoginEventType), Payload: []byte(`{"userId":"user-3"}`)},
+	}}
+	notifier := &recordingNotifier{channel: ChannelEmail}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0,
+		map[events.EventType][]Notifier{events.LoginEventType: {notifier}}, 0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+
+	worker.processPendingEvents(context.Background())
+
+	assert.Equal(t, []string{"event-1", "event-3"}, repo.succeeded, "a failed event must not stop the rest of the batch")
+	assert.Equal(t, BatchResult{Succeeded: 2, Failed: 1}, worker.Totals())
+
+	summary := hook.LastEntry()
+	assert.Equal(t, logrus.WarnLevel, summary.Level)
+	assert.Equal(t, 3, summary.Data["count"])
+	assert.Equal(t, 1, summary.Data["failed"])
+
+	repo.pending = repo.pending[1:2]
+	worker.processPendingEvents(context.Background())
+	assert.Equal(t, BatchResult{Succeeded: 2, Failed: 2, Retried: 1}, worker.Totals())
+}
+
+func TestProcessPendingEvents_ContactVerification(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := &stubNotificationRepository{pending: []*domain.NotificationEventLog{
+		{ID: "event-1", EventName: string(events.LoginEventType), Payload: []byte(`{"userID":"user-1"}`)},
+		{ID: "event-2", EventName: string(events.EmailVerificationEventType), Payload: []byte(`{"userID":"user-1","email":"new@example.com","code":"123456"}`)},
+		{ID: "event-3", EventName: string(events.PhoneVerificationEventType), Payload: []byte(`{"userID":"user-1","countryCode":"TW","phone":"+886912345678","code":"654321"}`)},
+	}}
+	email := &recordingNotifier{channel: ChannelEmail}
+	sms := &recordingNotifier{channel: ChannelSMS}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0,
+		map[events.EventType][]Notifier{
+			events.LoginEventType:             {email},
+			events.EmailVerificationEventType: {email},
+			events.PhoneVerificationEventType: {sms},
+		}, 0, nil, CleanupOptions{}, StartupOptions{},
+		TemplateOptions{Templates: map[events.EventType]string{events.PhoneVerificationEventType: "phone_verification"}}, time.Second, RetryOptions{})
+
+	worker.processPendingEvents(context.Background())
+
+	assert.Equal(t, []string{"event-2", "event-3", "event-1"}, repo.succeeded, "verification codes are sent before login notifications")
+	require.Len(t, email.notifications, 2)
+	assert.Equal(t, events.EmailVerificationEventType, email.notifications[0].EventType)
+
+	require.Len(t, sms.notifications, 1)
+	var event events.ContactVerificationEvent
+	require.NoError(t, json.Unmarshal(sms.notifications[0].Payload, &event))
+	assert.Equal(t, "654321", event.Code)
+	assert.Equal(t, "+886912345678", *event.Phone)
+	assert.Equal(t, "phone_verification", event.Template.TemplateID)
+}
+
+func TestProcessPendingEvents_BatchSizeCoversAllEventTypes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := &stubNotificationRepository{pending: []*domain.NotificationEventLog{
+		{ID: "event-1", EventName: string(events.LoginEventType), Payload: []byte(`{"userID":"user-1"}`)},
+		{ID: "event-2", EventName: string(events.EmailVerificationEventType), Payload: []byte(`{"userID":"user-1","email":"new@example.com","code":"123456"}`)},
+		{ID: "event-3", EventName: string(events.LoginEventType), Payload: []byte(`{"userID":"user-2"}`)},
+		{ID: "event-4", EventName: string(events.LoginEventType), Payload: []byte(`{"userID":"user-3"}`)},
+	}}
+	email := &recordingNotifier{channel: ChannelEmail}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 2, 0,
+		map[events.EventType][]Notifier{
+			events.LoginEventType:             {email},
+			events.EmailVerificationEventType: {email},
+		}, 0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+
+	worker.processPendingEvents(context.Background())
+	assert.Equal(t, []string{"event-2", "event-1"}, repo.succeeded, "one poll handles at most batchSize events of all types together")
+}
+
+func TestProcessPendingEvents_RetriesOnlyFailedChannels(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	event := &domain.NotificationEventLog{ID: "event-1", EventName: string(events.LoginEventType), Payload: []byte(`{"userID":"user-1"}`)}
+	repo := &stubNotificationRepository{pending: []*domain.NotificationEventLog{event}}
+	email := &recordingNotifier{channel: ChannelEmail}
+	sms := &recordingNotifier{channel: ChannelSMS, err: errors.New("gateway unavailable")}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0,
+		map[events.EventType][]Notifier{events.LoginEventType: {email, sms}},
+		0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+
+	worker.processPendingEvents(context.Background())
+	assert.Empty(t, repo.succeeded)
+	assert.Equal(t, []string{ChannelEmail}, repo.delivered["event-1"])
+
+	// The event is fetched again as the database stores it, with the delivered channel
+	repo.pending = []*domain.NotificationEventLog{{ID: event.ID, EventName: event.EventName, Payload: event.Payload, DeliveredChannels: []string{ChannelEmail}}}
+	sms.err = nil
+	worker.processPendingEvents(context.Background())
+
+	assert.Equal(t, []string{"event-1"}, repo.succeeded)
+	assert.Len(t, email.notifications, 1, "the channel that already succeeded must not be sent again")
+	assert.Len(t, sms.notifications, 2)
+	assert.Equal(t, []string{ChannelEmail, ChannelSMS}, repo.delivered["event-1"])
+}
+
+// stalledNotifier blocks its first call until the context ends, like an enqueue to an
+// unresponsive Redis, and delivers every later call
+type stalledNotifier struct {
+	calls int
+}
+
+func (n *stalledNotifier) Channel() string {
+	return ChannelEmail
+}
+
+func (n *stalledNotifier) Notify(ctx context.Context, _ *Notification) error {
+	n.calls++
+	if n.calls == 1 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestProcessPendingEvents_SendTimeout(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := &stubNotificationRepository{pending: []*domain.NotificationEventLog{
+		{ID: "event-1", EventName: string(events.LoginEventType), Payload: []byte(`{"userId":"user-1"}`)},
+		{ID: "event-2", EventName: string(events.LoginEventType), Payload: []byte(`{"userId":"user-2"}`)},
+	}}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0,
+		map[events.EventType][]Notifier{events.LoginEventType: {&stalledNotifier{}}}, 0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, 20*time.Millisecond, RetryOptions{})
+
+	worker.processPendingEvents(context.Background())
+
+	assert.Equal(t, []string{"event-2"}, repo.succeeded, "a timed out event stays pending without stalling the batch")
+	assert.Equal(t, BatchResult{Succeeded: 1, Failed: 1}, worker.Totals())
+}
+
+func TestProcessBatch_SkipsRemainingEventsWhenCancelled(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	worker := NewNotificationWorker(logger, &stubNotificationRepository{}, &sync.WaitGroup{}, time.Minute, 10, 0, nil, 0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := worker.processBatch(ctx, []*domain.NotificationEventLog{{ID: "event-1"}, {ID: "event-2"}})
+	assert.Equal(t, BatchResult{Skipped: 2}, result)
+}
+
+func TestProcessPendingEvents_SchedulesRetry(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := &stubNotificationRepository{pending: []*domain.NotificationEventLog{
+		{ID: "event-1", EventName: string(events.LoginEventType), Payload: []byte(`{"userId":"user-1"}`)},
+	}}
+	failing := &recordingNotifier{channel: ChannelEmail, err: errors.New("smtp down")}
+	retry := RetryOptions{Interval: time.Minute, MaxAttempts: 3, Backoff: time.Minute, BatchSize: 10}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0,
+		map[events.EventType][]Notifier{events.LoginEventType: {failing}}, 0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, retry)
+
+	worker.processPendingEvents(context.Background())
+
+	require.Contains(t, repo.failed, "event-1", "a failed send is handed to the retry sweeper")
+	assert.WithinDuration(t, time.Now().Add(time.Minute), repo.failed["event-1"], time.Second)
+	assert.NotContains(t, worker.attempts, "event-1")
+}
+
+func TestRetryFailedEvents(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+
+	repo := &stubNotificationRepository{retryable: []*domain.NotificationEventLog{
+		{ID: "event-1", EventName: string(events.LoginEventType), Payload: []byte(`{"userId":"user-1"}`), Status: domain.NotificationEventLogStatusFailed, Attempts: 1},
+		{ID: "event-2", EventName: string(events.LoginEventType), Payload: []byte(`not json`), Status: domain.NotificationEventLogStatusFailed, Attempts: 1},
+		{ID: "event-3", EventName: string(events.LoginEventType), Payload: []byte(`not json`), Status: domain.NotificationEventLogStatusFailed, Attempts: 2},
+	}}
+	retry := RetryOptions{Interval: time.Minute, MaxAttempts: 3, Backoff: time.Minute, BatchSize: 10}
+	worker := NewNotificationWorker(logger, repo, &sync.WaitGroup{}, time.Minute, 10, 0,
+		map[events.EventType][]Notifier{events.LoginEventType: {&recordingNotifier{channel: ChannelEmail}}}, 0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, retry)
+
+	worker.retryFailedEvents(context.Background())
+
+	assert.Equal(t, []string{"event-1"}, repo.succeeded, "a recovered event is published")
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), repo.failed["event-2"], time.Second, "the backoff doubles after the second failure")
+	assert.Contains(t, repo.failed, "event-3")
+	assert.Equal(t, BatchResult{Succeeded: 1, Failed: 2, Retried: 3}, worker.Totals())
+
+	var exhausted bool
+	for _, entry := range hook.AllEntries() {
+		if entry.Data["event_id"] == "event-3" && entry.Message == "Notification event used up its retries and stays failed" {
+			exhausted = true
+		}
+	}
+	assert.True(t, exhausted, "the event reaching max attempts is reported")
+}
+
+func TestRetryOptions_Delay(t *testing.T) {
+	retry := RetryOptions{Backoff: 30 * time.Second}
+
+	assert.Equal(t, 30*time.Second, retry.delay(1))
+	assert.Equal(t, time.Minute, retry.delay(2))
+	assert.Equal(t, 4*time.Minute, retry.delay(4))
+	assert.Equal(t, retry.delay(maxBackoffDoublings+1), retry.delay(100), "the backoff stops growing")
+}
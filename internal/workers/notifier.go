@@ -0,0 +1,175 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+	"wallet-user-svc/internal/app/model/events"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+)
+
+// Notification channel names used to select notifiers in configuration
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+	ChannelNoop    = "noop"
+)
+
+// Notification is a channel-agnostic message built from a notification event
+type Notification struct {
+	EventType events.EventType
+	EventID   string
+	// Payload is the JSON encoded event delivered to the channel
+	Payload []byte
+}
+
+// Notifier delivers a notification over a single channel
+type Notifier interface {
+	Channel() string
+	Notify(ctx context.Context, notification *Notification) error
+}
+
+// TaskNotifier hands notifications to asynq as tasks on a channel specific queue,
+// where the channel consumer (email sender, SMS gateway) picks them up
+type TaskNotifier struct {
+	channel     string
+	client      *asynq.Client
+	queue       string
+	maxRetries  int
+	taskOptions map[events.EventType]events.TaskOptions
+	logger      *logrus.Logger
+}
+
+// NewEmailNotifier creates a notifier that enqueues email tasks on the given queue
+func NewEmailNotifier(
+	client *asynq.Client,
+	queue string,
+	maxRetries int,
+	taskOptions map[events.EventType]events.TaskOptions,
+	logger *logrus.Logger,
+) *TaskNotifier {
+	return newTaskNotifier(ChannelEmail, client, queue, maxRetries, taskOptions, logger)
+}
+
+// NewSMSNotifier creates a notifier that enqueues SMS tasks on the given queue
+func NewSMSNotifier(
+	client *asynq.Client,
+	queue string,
+	maxRetries int,
+	taskOptions map[events.EventType]events.TaskOptions,
+	logger *logrus.Logger,
+) *TaskNotifier {
+	return newTaskNotifier(ChannelSMS, client, queue, maxRetries, taskOptions, logger)
+}
+
+func newTaskNotifier(
+	channel string,
+	client *asynq.Client,
+	queue string,
+	maxRetries int,
+	taskOptions map[events.EventType]events.TaskOptions,
+	logger *logrus.Logger,
+) *TaskNotifier {
+	return &TaskNotifier{
+		channel:     channel,
+		client:      client,
+		queue:       queue,
+		maxRetries:  maxRetries,
+		taskOptions: taskOptions,
+		logger:      logger,
+	}
+}
+
+func (n *TaskNotifier) Channel() string {
+	return n.channel
+}
+
+func (n *TaskNotifier) Notify(ctx context.Context, notification *Notification) error {
+	task := asynq.NewTask(string(notification.EventType), notification.Payload, n.enqueueOptions(notification.EventType)...)
+
+	info, err := n.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("enqueue %s task: %w", n.channel, err)
+	}
+
+	n.logger.WithFields(logrus.Fields{
+		"id":      info.ID,
+		"queue":   info.Queue,
+		"channel": n.channel,
+	}).Debug("Enqueued task")
+
+	return nil
+}
+
+// enqueueOptions returns the asynq options for an event type: the channel queue and
+// retry limit followed by any per-event scheduling options from configuration
+func (n *TaskNotifier) enqueueOptions(eventType events.EventType) []asynq.Option {
+	opts := []asynq.Option{asynq.MaxRetry(n.maxRetries)}
+	if n.queue != "" {
+		opts = append(opts, asynq.Queue(n.queue))
+	}
+	return append(opts, n.taskOptions[eventType].AsynqOptions(time.Now())...)
+}
+
+// WebhookNotifier posts notifications as JSON to an HTTP endpoint
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+	logger *logrus.Logger
+}
+
+// NewWebhookNotifier creates a notifier posting to url, bounding each request by timeout
+func NewWebhookNotifier(url string, timeout time.Duration, logger *logrus.Logger) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		logger: logger,
+	}
+}
+
+func (n *WebhookNotifier) Channel() string {
+	return ChannelWebhook
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, notification *Notification) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(notification.Payload))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", string(notification.EventType))
+	req.Header.Set("X-Event-ID", notification.EventID)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	n.logger.WithFields(logrus.Fields{
+		"eventID": notification.EventID,
+		"status":  resp.StatusCode,
+	}).Debug("Delivered webhook")
+
+	return nil
+}
+
+// NopNotifier discards notifications; useful for tests and for disabling a channel
+type NopNotifier struct{}
+
+func (NopNotifier) Channel() string {
+	return ChannelNoop
+}
+
+func (NopNotifier) Notify(context.Context, *Notification) error {
+	return nil
+}
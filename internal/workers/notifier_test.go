@@ -0,0 +1,143 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/model/dto"
+	"wallet-user-svc/internal/app/model/events"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	channel       string
+	err           error
+	notifications []*Notification
+}
+
+func (n *recordingNotifier) Channel() string {
+	return n.channel
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification *Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return n.err
+}
+
+func newTestWorker(notifiers map[events.EventType][]Notifier) *NotificationWorker {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewNotificationWorker(logger, nil, &sync.WaitGroup{}, time.Minute, 10, 0, notifiers, 0, nil, CleanupOptions{}, StartupOptions{}, TemplateOptions{}, time.Second, RetryOptions{})
+}
+
+func TestSendLoginNotification_DispatchesToConfiguredChannels(t *testing.T) {
+	email := &recordingNotifier{channel: ChannelEmail}
+	sms := &recordingNotifier{channel: ChannelSMS}
+	worker := newTestWorker(map[events.EventType][]Notifier{
+		events.LoginEventType: {email},
+		"otp":                 {sms},
+	})
+
+	err := worker.SendLoginNotification(context.Background(), &dto.SendLoginNotificationParams{
+		UserID:   "user-123",
+		Username: "testuser",
+		LoginAt:  time.Now(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, email.notifications, 1)
+	assert.Empty(t, sms.notifications)
+
+	notification := email.notifications[0]
+	assert.Equal(t, events.LoginEventType, notification.EventType)
+	assert.NotEmpty(t, notification.EventID)
+
+	var loginEvent events.LoginEvent
+	require.NoError(t, json.Unmarshal(notification.Payload, &loginEvent))
+	assert.Equal(t, "user-123", loginEvent.UserID)
+	assert.Equal(t, notification.EventID, loginEvent.EventMetadata.EventID)
+}
+
+func TestSendLoginNotification_IncludesTemplateMetadata(t *testing.T) {
+	email := &recordingNotifier{channel: ChannelEmail}
+	worker := newTestWorker(map[events.EventType][]Notifier{events.LoginEventType: {email}})
+	worker.templates = TemplateOptions{
+		SenderEmail: "no-reply@example.com",
+		SenderName:  "Wallet",
+		Templates:   map[events.EventType]string{events.LoginEventType: "login_notification"},
+	}
+
+	err := worker.SendLoginNotification(context.Background(), &dto.SendLoginNotificationParams{UserID: "user-123", LoginAt: time.Now()})
+	require.NoError(t, err)
+	require.Len(t, email.notifications, 1)
+
+	var loginEvent events.LoginEvent
+	require.NoError(t, json.Unmarshal(email.notifications[0].Payload, &loginEvent))
+	assert.Equal(t, events.NotificationTemplate{
+		TemplateID:  "login_notification",
+		SenderEmail: "no-reply@example.com",
+		SenderName:  "Wallet",
+	}, loginEvent.Template)
+}
+
+func TestSendLoginNotification_AttemptsAllNotifiersOnFailure(t *testing.T) {
+	failing := &recordingNotifier{channel: ChannelEmail, err: errors.New("smtp down")}
+	webhook := &recordingNotifier{channel: ChannelWebhook}
+	worker := newTestWorker(map[events.EventType][]Notifier{
+		events.LoginEventType: {failing, webhook},
+	})
+
+	err := worker.SendLoginNotification(context.Background(), &dto.SendLoginNotificationParams{UserID: "user-123"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ChannelEmail)
+	assert.Len(t, webhook.notifications, 1)
+}
+
+func TestSendLoginNotification_NoNotifiersConfigured(t *testing.T) {
+	worker := newTestWorker(nil)
+
+	err := worker.SendLoginNotification(context.Background(), &dto.SendLoginNotificationParams{UserID: "user-123"})
+	assert.NoError(t, err)
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var received []byte
+	var eventType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		eventType = r.Header.Get("X-Event-Type")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, time.Second, logrus.New())
+	err := notifier.Notify(context.Background(), &Notification{
+		EventType: events.LoginEventType,
+		EventID:   "event-1",
+		Payload:   []byte(`{"userId":"user-123"}`),
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"userId":"user-123"}`, string(received))
+	assert.Equal(t, string(events.LoginEventType), eventType)
+}
+
+func TestWebhookNotifier_NonSuccessStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, time.Second, logrus.New())
+	err := notifier.Notify(context.Background(), &Notification{EventType: events.LoginEventType})
+	assert.Error(t, err)
+}
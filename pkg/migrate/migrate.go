@@ -2,11 +2,15 @@ package migrate
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
 )
@@ -114,3 +118,82 @@ func GetMigrationStatus(config Config) (version uint, dirty bool, err error) {
 
 	return version, dirty, nil
 }
+
+// RecoverDirty repairs a database left dirty by a failed migration: it forces the
+// version back to the migration before the failed one and runs all pending migrations
+// again. The failed migration must therefore be safe to re-run (e.g. IF NOT EXISTS).
+// It reports whether the database was dirty and the failed version.
+func RecoverDirty(config Config) (recovered bool, failedVersion uint, err error) {
+	// Create database connection
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	// Create postgres driver instance
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to create postgres driver: %w", err)
+	}
+
+	sourceURL := fmt.Sprintf("file://%s", config.MigrationsPath)
+
+	// Create migrate instance
+	m, err := migrate.NewWithDatabaseInstance(sourceURL, "postgres", driver)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+	defer m.Close()
+
+	version, dirty, err := m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return false, 0, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	if !dirty {
+		return false, version, nil
+	}
+
+	src, err := source.Open(sourceURL)
+	if err != nil {
+		return false, version, fmt.Errorf("failed to open migration source: %w", err)
+	}
+	defer src.Close()
+
+	lastClean, err := previousVersion(src, version)
+	if err != nil {
+		return false, version, err
+	}
+
+	log.Printf("Database is dirty at version %d, forcing version %d and migrating up again", version, lastClean)
+	if err := m.Force(lastClean); err != nil {
+		return false, version, fmt.Errorf("failed to force version %d: %w", lastClean, err)
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return false, version, fmt.Errorf("failed to run migrations after recovery: %w", err)
+	}
+
+	log.Printf("Recovered from dirty migration %d", version)
+	return true, version, nil
+}
+
+// previousVersion returns the migration applied before version, or database.NilVersion
+// when version is the first migration. A version unknown to the source is an error.
+func previousVersion(src source.Driver, version uint) (int, error) {
+	first, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the first migration: %w", err)
+	}
+	if version == first {
+		return database.NilVersion, nil
+	}
+
+	prev, err := src.Prev(version)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("migration %d is not in the migration source", version)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the migration before %d: %w", version, err)
+	}
+	return int(prev), nil
+}
@@ -0,0 +1,37 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviousVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"1_init", "5_add_index", "9_add_column"} {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, name+"."+direction+".sql")
+			require.NoError(t, os.WriteFile(path, []byte("SELECT 1;"), 0o600))
+		}
+	}
+
+	src, err := source.Open("file://" + dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { src.Close() })
+
+	prev, err := previousVersion(src, 9)
+	require.NoError(t, err)
+	assert.Equal(t, 5, prev)
+
+	prev, err = previousVersion(src, 1)
+	require.NoError(t, err)
+	assert.Equal(t, database.NilVersion, prev, "recovering the first migration starts from scratch")
+
+	_, err = previousVersion(src, 7)
+	assert.Error(t, err, "an unknown version must not be forced to scratch")
+}
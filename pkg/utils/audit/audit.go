@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Audited actions
+const (
+	ActionVerifyPassword = "verify_password"
+	ActionEnrollTOTP     = "enroll_totp"
+	ActionVerifyTOTP     = "verify_totp"
+	ActionCompleteLogin  = "complete_login"
+	ActionCreateUser     = "create_user"
+	ActionRevokeSession  = "revoke_session"
+	ActionRefreshToken   = "refresh_token"
+
+	ActionRegenerateBackupCodes         = "regenerate_backup_codes"
+	ActionUpdateNotificationPreferences = "update_notification_preferences"
+	ActionReEncryptSecrets              = "re_encrypt_secrets"
+	ActionAddContactMethod              = "add_contact_method"
+	ActionConfirmContactMethod          = "confirm_contact_method"
+	ActionSetPrimaryContact             = "set_primary_contact"
+	ActionRegisterWebAuthnCredential    = "register_webauthn_credential"
+	ActionWebAuthnLogin                 = "webauthn_login"
+)
+
+// Event describes a security relevant action for the audit trail
+type Event struct {
+	// Action names the audited operation, e.g. "verify_password"
+	Action  string
+	UserID  string
+	Success bool
+	// Reason explains a failure
+	Reason string
+	// Fields carries additional action specific data
+	Fields logrus.Fields
+}
+
+// Logger writes audit events as structured log entries tagged with audit=true,
+// so they can be routed separately from application logs
+type Logger struct{}
+
+// NewLogger creates a new audit logger. The client IP stored in the request context
+// by the client IP interceptor is recorded with each event.
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+// Log records an audit event using the request scoped logger from ctx
+func (l *Logger) Log(ctx context.Context, event Event) {
+	entry := logutils.GetLoggerOrDefault(ctx).WithFields(event.Fields).WithFields(logrus.Fields{
+		"audit":   true,
+		"action":  event.Action,
+		"success": event.Success,
+	})
+	if event.UserID != "" {
+		entry = entry.WithField("user_id", event.UserID)
+	}
+	if clientIP, _ := cx.GetClientIP(ctx); clientIP != "" {
+		entry = entry.WithField("client_ip", clientIP)
+	}
+	if event.Reason != "" {
+		entry = entry.WithField("reason", event.Reason)
+	}
+
+	if event.Success {
+		entry.Info("Audit event")
+		return
+	}
+	entry.Warn("Audit event")
+}
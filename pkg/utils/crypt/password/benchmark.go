@@ -0,0 +1,42 @@
+package password
+
+import (
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// benchmarkPassword is the sample plain text hashed while measuring cost latency
+const benchmarkPassword = "BenchmarkPassword123!"
+
+// BenchmarkCost returns the highest bcrypt cost whose hash time stays under the target latency.
+// Costs are measured in increasing order starting at bcrypt.MinCost, stopping at the first cost
+// that exceeds the target. If even the minimum cost is too slow, bcrypt.MinCost is returned.
+func BenchmarkCost(targetLatency time.Duration) int {
+	best := bcrypt.MinCost
+
+	for cost := bcrypt.MinCost; cost <= bcrypt.MaxCost; cost++ {
+		elapsed, err := measureCost(cost)
+		if err != nil || elapsed > targetLatency {
+			break
+		}
+		best = cost
+
+		// Each cost increment doubles the work, so stop before measuring a cost
+		// that is already expected to blow the budget
+		if elapsed*2 > targetLatency {
+			break
+		}
+	}
+
+	return best
+}
+
+// measureCost returns how long a single bcrypt hash takes at the given cost
+func measureCost(cost int) (time.Duration, error) {
+	start := time.Now()
+	if _, err := bcrypt.GenerateFromPassword([]byte(benchmarkPassword), cost); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
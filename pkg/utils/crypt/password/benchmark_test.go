@@ -0,0 +1,24 @@
+package password
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBenchmarkCost_ValidRange(t *testing.T) {
+	targets := []time.Duration{0, 10 * time.Millisecond, 100 * time.Millisecond}
+	for _, target := range targets {
+		cost := BenchmarkCost(target)
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			t.Errorf("BenchmarkCost(%s) = %d, want value in [%d, %d]", target, cost, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	}
+}
+
+func TestBenchmarkCost_ZeroTargetReturnsMinCost(t *testing.T) {
+	if cost := BenchmarkCost(0); cost != bcrypt.MinCost {
+		t.Errorf("BenchmarkCost(0) = %d, want %d", cost, bcrypt.MinCost)
+	}
+}
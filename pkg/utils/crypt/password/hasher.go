@@ -1,12 +1,17 @@
 package password
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
 // Hasher provides password hashing and verification functionality
 type Hasher struct {
-	cost int
+	cost   int
+	pepper []byte
 }
 
 // NewHasher creates a new password hasher with the specified cost
@@ -17,9 +22,19 @@ func NewHasher(cost int) *Hasher {
 	return &Hasher{cost: cost}
 }
 
+// WithPepper returns a copy of the hasher that mixes a server-side secret into every
+// password before bcrypt, so hashes leaked without the pepper cannot be cracked offline.
+// An empty pepper disables peppering. Hashes only verify with the pepper they were
+// created with.
+func (h *Hasher) WithPepper(pepper string) *Hasher {
+	peppered := *h
+	peppered.pepper = []byte(pepper)
+	return &peppered
+}
+
 // HashPassword hashes a plain text password using bcrypt
 func (h *Hasher) HashPassword(password string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	hashedBytes, err := bcrypt.GenerateFromPassword(h.peppered(password), h.cost)
 	if err != nil {
 		return "", err
 	}
@@ -28,10 +43,22 @@ func (h *Hasher) HashPassword(password string) (string, error) {
 
 // VerifyPassword verifies a plain text password against a hashed password
 func (h *Hasher) VerifyPassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), h.peppered(password))
 	return err == nil
 }
 
+// peppered returns the bcrypt input for a password. Rather than appending the pepper,
+// which bcrypt would cut off for passwords near its 72 byte limit, the password is
+// keyed with it through HMAC-SHA256; the base64 encoded MAC is 44 bytes.
+func (h *Hasher) peppered(password string) []byte {
+	if len(h.pepper) == 0 {
+		return []byte(password)
+	}
+	mac := hmac.New(sha256.New, h.pepper)
+	mac.Write([]byte(password))
+	return []byte(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
 // DefaultHasher returns a hasher with default bcrypt cost
 func DefaultHasher() *Hasher {
 	return NewHasher(bcrypt.DefaultCost)
@@ -1,6 +1,7 @@
 package password
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -64,3 +65,35 @@ func TestHasher_DifferentCosts(t *testing.T) {
 		}
 	}
 }
+
+func TestHasher_Pepper(t *testing.T) {
+	hasher := NewHasher(4).WithPepper("server-pepper")
+	password := "testPassword123!"
+
+	hashedPassword, err := hasher.HashPassword(password)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+
+	if !hasher.VerifyPassword(hashedPassword, password) {
+		t.Error("Password verification should succeed with the pepper the hash was created with")
+	}
+
+	if NewHasher(4).WithPepper("other-pepper").VerifyPassword(hashedPassword, password) {
+		t.Error("Password verification should fail with a different pepper")
+	}
+
+	if NewHasher(4).VerifyPassword(hashedPassword, password) {
+		t.Error("Password verification should fail without the pepper")
+	}
+
+	// The pepper must not be cut off by bcrypt's 72 byte input limit
+	longPassword := strings.Repeat("a", 72)
+	hashedPassword, err = hasher.HashPassword(longPassword)
+	if err != nil {
+		t.Fatalf("Failed to hash long password: %v", err)
+	}
+	if NewHasher(4).WithPepper("other-pepper").VerifyPassword(hashedPassword, longPassword) {
+		t.Error("Password verification of a long password should fail with a different pepper")
+	}
+}
@@ -0,0 +1,137 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCiphertext is returned when a value cannot be decrypted with the key
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// Cipher encrypts small secrets (e.g. TOTP seeds) for storage using AES-256-GCM
+type Cipher struct {
+	aead cipher.AEAD
+	// previous decrypt values encrypted before the key was rotated
+	previous []cipher.AEAD
+}
+
+// NewCipher creates a cipher from a passphrase. The passphrase is stretched to a
+// 256-bit key with SHA-256, so it should itself be long and random. Values encrypted
+// with one of the previous passphrases still decrypt, and ReEncrypt moves them to
+// the current one.
+func NewCipher(passphrase string, previousPassphrases ...string) (*Cipher, error) {
+	if passphrase == "" {
+		return nil, errors.New("encryption key is required")
+	}
+
+	aead, err := newAEAD(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cipher{aead: aead}
+	for _, previousPassphrase := range previousPassphrases {
+		if previousPassphrase == "" {
+			return nil, errors.New("previous encryption keys must not be empty")
+		}
+		previous, err := newAEAD(previousPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		c.previous = append(c.previous, previous)
+	}
+
+	return c, nil
+}
+
+// newAEAD derives the AES-256-GCM AEAD of a passphrase
+func newAEAD(passphrase string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+// Encrypt returns the base64 encoded nonce and ciphertext of plaintext
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt, with the current or one of the previous keys
+func (c *Cipher) Decrypt(encoded string) (string, error) {
+	plaintext, _, err := c.decrypt(encoded)
+	return plaintext, err
+}
+
+// ReEncrypt re-encrypts a value encrypted with one of the previous keys under the
+// current key, reporting whether it did. Values already under the current key are
+// returned unchanged, so running it again is harmless.
+func (c *Cipher) ReEncrypt(encoded string) (string, bool, error) {
+	plaintext, current, err := c.decrypt(encoded)
+	if err != nil {
+		return "", false, err
+	}
+	if current {
+		return encoded, false, nil
+	}
+
+	reEncrypted, err := c.Encrypt(plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return reEncrypted, true, nil
+}
+
+// decrypt opens a value with the current key, falling back to the previous keys, and
+// reports whether the current key opened it
+func (c *Cipher) decrypt(encoded string) (string, bool, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", false, ErrInvalidCiphertext
+	}
+
+	if plaintext, ok := open(c.aead, sealed); ok {
+		return plaintext, true, nil
+	}
+	for _, previous := range c.previous {
+		if plaintext, ok := open(previous, sealed); ok {
+			return plaintext, false, nil
+		}
+	}
+
+	return "", false, ErrInvalidCiphertext
+}
+
+// open splits the nonce off a sealed value and opens it with aead
+func open(aead cipher.AEAD, sealed []byte) (string, bool) {
+	if len(sealed) < aead.NonceSize() {
+		return "", false
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", false
+	}
+
+	return string(plaintext), true
+}
@@ -0,0 +1,74 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher("test-encryption-key")
+	require.NoError(t, err)
+
+	encrypted, err := c.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "JBSWY3DPEHPK3PXP")
+
+	decrypted, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", decrypted)
+}
+
+func TestCipher_DecryptWithWrongKey(t *testing.T) {
+	c, err := NewCipher("test-encryption-key")
+	require.NoError(t, err)
+	other, err := NewCipher("another-encryption-key")
+	require.NoError(t, err)
+
+	encrypted, err := c.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = c.Decrypt("not-base64!")
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func TestNewCipher_RequiresKey(t *testing.T) {
+	_, err := NewCipher("")
+	assert.Error(t, err)
+}
+
+func TestCipher_ReEncryptWithPreviousKey(t *testing.T) {
+	old, err := NewCipher("old-encryption-key")
+	require.NoError(t, err)
+	rotated, err := NewCipher("new-encryption-key", "old-encryption-key")
+	require.NoError(t, err)
+
+	encrypted, err := old.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+
+	decrypted, err := rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", decrypted, "values under a previous key still decrypt")
+
+	reEncrypted, changed, err := rotated.ReEncrypt(encrypted)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	current, err := NewCipher("new-encryption-key")
+	require.NoError(t, err)
+	decrypted, err = current.Decrypt(reEncrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", decrypted)
+
+	again, changed, err := rotated.ReEncrypt(reEncrypted)
+	require.NoError(t, err)
+	assert.False(t, changed, "values under the current key are left alone")
+	assert.Equal(t, reEncrypted, again)
+
+	_, _, err = rotated.ReEncrypt("not-base64!")
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+}
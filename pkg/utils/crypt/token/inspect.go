@@ -0,0 +1,18 @@
+package token
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ParseUnverified decodes the claims of token WITHOUT checking its signature, expiry or
+// any other claim. Anyone can forge a token that parses, so the result must never be
+// used for authentication or authorization decisions; use JWTTokenMaker.VerifyAccessToken
+// for that. It is meant for debugging tools and tests that read claims without the secret.
+func ParseUnverified(token string) (*Payload, error) {
+	payload := &Payload{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, payload); err != nil {
+		return nil, mapVerifyError(err)
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,37 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUnverified(t *testing.T) {
+	userID := uuid.New().String()
+	signed, _, err := NewJWTTokenMaker("another-secret-key-with-at-least-32-chars", 0).
+		CreateAccessToken(userID, "testuser", "admin", "", time.Minute)
+	require.NoError(t, err)
+
+	payload, err := ParseUnverified(signed)
+	require.NoError(t, err, "claims are readable without the secret")
+	assert.Equal(t, userID, payload.UserID)
+	assert.Equal(t, "testuser", payload.Username)
+	assert.Equal(t, "admin", payload.Role)
+
+	expired := signClaims(t, jwt.MapClaims{
+		"user_id":    userID,
+		"expired_at": time.Now().Add(-time.Hour).Unix(),
+	})
+	payload, err = ParseUnverified(expired)
+	require.NoError(t, err, "expiry and required claims are not checked")
+	assert.Equal(t, userID, payload.UserID)
+
+	_, err = ParseUnverified("not-a-jwt")
+	assert.ErrorIs(t, err, errs.ErrMalformedToken)
+}
@@ -2,6 +2,9 @@ package token
 
 import (
 	"errors"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -15,44 +18,62 @@ const minSecretKeySize = 32
 
 type JWTTokenMaker struct {
 	secretKey string
+	// previousSecretKeys still verify tokens issued before the secret was rotated
+	previousSecretKeys []string
+	// leeway tolerates clock skew between the issuing and the verifying service
+	leeway time.Duration
 }
 
-func NewJWTTokenMaker(secretKey string) *JWTTokenMaker {
+// NewJWTTokenMaker creates a token maker. Verification accepts tokens that expired or
+// were issued in the future by at most leeway, to tolerate clock skew between services.
+// Tokens are always signed with secretKey; tokens signed with any of previousSecretKeys
+// still verify, so tokens issued before a secret rotation keep working until they expire.
+func NewJWTTokenMaker(secretKey string, leeway time.Duration, previousSecretKeys ...string) *JWTTokenMaker {
 	if len(secretKey) < minSecretKeySize {
 		panic("invalid secret key size: must be at least 32 characters")
 	}
+	for _, previous := range previousSecretKeys {
+		if len(previous) < minSecretKeySize {
+			panic("invalid previous secret key size: must be at least 32 characters")
+		}
+	}
 
-	return &JWTTokenMaker{secretKey: secretKey}
+	return &JWTTokenMaker{secretKey: secretKey, previousSecretKeys: previousSecretKeys, leeway: leeway}
 }
 
-func (maker *JWTTokenMaker) CreateAccessToken(userID string, username string, duration int64) (string, error) {
-	payload, err := NewPayload(userID, username, duration)
+func (maker *JWTTokenMaker) CreateAccessToken(userID string, username string, role string, tenantID string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, username, role, tenantID, duration)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 
-	return token.SignedString([]byte(maker.secretKey))
+	signed, err := token.SignedString([]byte(maker.secretKey))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, payload, nil
 }
 
-func (maker *JWTTokenMaker) CreateTokenPair(userID string, username string, duration int64) (string, string, error) {
+func (maker *JWTTokenMaker) CreateTokenPair(userID string, username string, role string, tenantID string, duration time.Duration) (string, string, *Payload, error) {
 
-	accessToken, err := maker.CreateAccessToken(userID, username, duration)
+	accessToken, payload, err := maker.CreateAccessToken(userID, username, role, tenantID, duration)
 	if err != nil {
-		return "", "", err
+		return "", "", nil, err
 	}
 
-	refreshToken, err := maker.CreateRefreshToken(userID, username, duration)
+	refreshToken, err := maker.CreateRefreshToken(userID, username, role, tenantID, duration)
 	if err != nil {
-		return "", "", err
+		return "", "", nil, err
 	}
 
-	return accessToken, refreshToken, nil
+	return accessToken, refreshToken, payload, nil
 }
 
-func (maker *JWTTokenMaker) CreateRefreshToken(userID string, username string, duration int64) (string, error) {
-	payload, err := NewPayload(userID, username, duration)
+func (maker *JWTTokenMaker) CreateRefreshToken(userID string, username string, role string, tenantID string, duration time.Duration) (string, error) {
+	payload, err := NewPayload(userID, username, role, tenantID, duration)
 	if err != nil {
 		return "", err
 	}
@@ -63,32 +84,77 @@ func (maker *JWTTokenMaker) CreateRefreshToken(userID string, username string, d
 }
 
 func (maker *JWTTokenMaker) VerifyAccessToken(token string) (*Payload, error) {
+	jwtToken, err := maker.parse(token, maker.secretKey)
+	// Only a signature mismatch means the token may have been signed with an older secret
+	for i := 0; i < len(maker.previousSecretKeys) && errors.Is(err, jwt.ErrTokenSignatureInvalid); i++ {
+		jwtToken, err = maker.parse(token, maker.previousSecretKeys[i])
+	}
+	if err != nil {
+		return nil, mapVerifyError(err)
+	}
+
+	payload, ok := jwtToken.Claims.(*Payload)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	return payload, nil
+}
+
+// parse parses and validates token, checking its signature against secretKey
+func (maker *JWTTokenMaker) parse(token string, secretKey string) (*jwt.Token, error) {
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
 		if !ok {
 			return nil, ErrInvalidToken
 		}
 
-		return []byte(maker.secretKey), nil
+		return []byte(secretKey), nil
 	}
 
-	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
-	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
-		}
-		return nil, ErrInvalidToken
+	return jwt.ParseWithClaims(token, &Payload{}, keyFunc, jwt.WithLeeway(maker.leeway), jwt.WithIssuedAt())
+}
+
+// VerifyAccessTokenWithGrace verifies token like VerifyAccessToken, but also accepts a
+// token that expired at most grace ago and reports whether the grace was needed. Issue
+// times are still only checked with the leeway.
+func (maker *JWTTokenMaker) VerifyAccessTokenWithGrace(token string, grace time.Duration) (*Payload, bool, error) {
+	payload, err := maker.VerifyAccessToken(token)
+	if grace <= 0 || !errors.Is(err, ErrExpiredToken) {
+		return payload, false, err
 	}
 
-	payload, ok := jwtToken.Claims.(*Payload)
-	if !ok {
-		return nil, ErrInvalidToken
+	lenient := *maker
+	lenient.leeway += grace
+	payload, err = lenient.VerifyAccessToken(token)
+	if err != nil {
+		return nil, false, err
+	}
+	if time.Now().Add(maker.leeway).Before(time.Unix(payload.IssuedAt, 0)) {
+		return nil, false, ErrInvalidToken
 	}
 
-	return payload, nil
+	return payload, true, nil
 }
 
 func (maker *JWTTokenMaker) VerifyRefreshToken(token string) (*Payload, error) {
 	payload, err := maker.VerifyAccessToken(token)
 	return payload, err
 }
+
+// mapVerifyError translates jwt parse/validation failures into specific errors so
+// an expired token can be told apart from a malformed or tampered one
+func mapVerifyError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrExpiredToken
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return errs.ErrMalformedToken
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return errs.ErrTokenMissingClaims
+	case errors.Is(err, jwt.ErrTokenInvalidId):
+		return errs.ErrInvalidTokenID
+	default:
+		return ErrInvalidToken
+	}
+}
@@ -0,0 +1,260 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecretKey = "test-secret-key-with-at-least-32-chars"
+
+// signClaims signs hand-crafted claims with the test secret, bypassing NewPayload
+func signClaims(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecretKey))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTTokenMaker_VerifyAccessToken(t *testing.T) {
+	maker := NewJWTTokenMaker(testSecretKey, 0)
+	future := time.Now().Add(time.Hour).Unix()
+
+	validToken, _, err := maker.CreateAccessToken(uuid.New().String(), "testuser", "user", "", time.Minute)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		token       string
+		expectedErr error
+	}{
+		{
+			name:  "valid token",
+			token: validToken,
+		},
+		{
+			name: "expired token",
+			token: signClaims(t, jwt.MapClaims{
+				"id":         uuid.New().String(),
+				"user_id":    uuid.New().String(),
+				"username":   "testuser",
+				"expired_at": time.Now().Add(-time.Hour).Unix(),
+			}),
+			expectedErr: ErrExpiredToken,
+		},
+		{
+			name: "missing user id claim",
+			token: signClaims(t, jwt.MapClaims{
+				"id":         uuid.New().String(),
+				"username":   "testuser",
+				"expired_at": future,
+			}),
+			expectedErr: errs.ErrTokenMissingClaims,
+		},
+		{
+			name: "missing username claim",
+			token: signClaims(t, jwt.MapClaims{
+				"id":         uuid.New().String(),
+				"user_id":    uuid.New().String(),
+				"expired_at": future,
+			}),
+			expectedErr: errs.ErrTokenMissingClaims,
+		},
+		{
+			name: "missing token id",
+			token: signClaims(t, jwt.MapClaims{
+				"user_id":    uuid.New().String(),
+				"username":   "testuser",
+				"expired_at": future,
+			}),
+			expectedErr: errs.ErrInvalidTokenID,
+		},
+		{
+			name: "subject differs from user id",
+			token: signClaims(t, jwt.MapClaims{
+				"id":         uuid.New().String(),
+				"user_id":    uuid.New().String(),
+				"sub":        uuid.New().String(),
+				"username":   "testuser",
+				"expired_at": future,
+			}),
+			expectedErr: ErrInvalidToken,
+		},
+		{
+			name: "token issued without a subject",
+			token: signClaims(t, jwt.MapClaims{
+				"id":         uuid.New().String(),
+				"user_id":    uuid.New().String(),
+				"username":   "testuser",
+				"expired_at": future,
+			}),
+		},
+		{
+			name:        "malformed token",
+			token:       "not-a-jwt",
+			expectedErr: errs.ErrMalformedToken,
+		},
+		{
+			name:        "token signed with another secret",
+			token:       mustCreateToken(t, NewJWTTokenMaker("another-secret-key-with-at-least-32-chars", 0)),
+			expectedErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := maker.VerifyAccessToken(tt.token)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, payload)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, payload)
+		})
+	}
+}
+
+func TestJWTTokenMaker_Subject(t *testing.T) {
+	maker := NewJWTTokenMaker(testSecretKey, 0)
+	userID := uuid.New().String()
+
+	signed, _, err := maker.CreateAccessToken(userID, "testuser", "user", "", time.Minute)
+	require.NoError(t, err)
+
+	// Third-party inspectors read the standard claims without knowing the secret
+	unverified, err := ParseUnverified(signed)
+	require.NoError(t, err)
+	assert.Equal(t, userID, unverified.Subject)
+
+	payload, err := maker.VerifyAccessToken(signed)
+	require.NoError(t, err)
+	subject, err := payload.GetSubject()
+	require.NoError(t, err)
+	assert.Equal(t, userID, subject)
+}
+
+func TestJWTTokenMaker_Leeway(t *testing.T) {
+	claims := func(issuedAt, expiredAt time.Time) jwt.MapClaims {
+		return jwt.MapClaims{
+			"id":         uuid.New().String(),
+			"user_id":    uuid.New().String(),
+			"username":   "testuser",
+			"issued_at":  issuedAt.Unix(),
+			"expired_at": expiredAt.Unix(),
+		}
+	}
+	now := time.Now()
+	justExpired := signClaims(t, claims(now.Add(-time.Minute), now.Add(-10*time.Second)))
+	issuedAhead := signClaims(t, claims(now.Add(10*time.Second), now.Add(time.Minute)))
+
+	strict := NewJWTTokenMaker(testSecretKey, 0)
+	_, err := strict.VerifyAccessToken(justExpired)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+	_, err = strict.VerifyAccessToken(issuedAhead)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	lenient := NewJWTTokenMaker(testSecretKey, 30*time.Second)
+	_, err = lenient.VerifyAccessToken(justExpired)
+	assert.NoError(t, err, "expiry within the leeway is tolerated")
+	_, err = lenient.VerifyAccessToken(issuedAhead)
+	assert.NoError(t, err, "issue time within the leeway is tolerated")
+
+	tooOld := signClaims(t, claims(now.Add(-time.Hour), now.Add(-time.Minute)))
+	_, err = lenient.VerifyAccessToken(tooOld)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestJWTTokenMaker_FutureIssuedAt(t *testing.T) {
+	now := time.Now()
+	token := signClaims(t, jwt.MapClaims{
+		"id":         uuid.New().String(),
+		"user_id":    uuid.New().String(),
+		"username":   "testuser",
+		"issued_at":  now.Add(10 * time.Minute).Unix(),
+		"expired_at": now.Add(time.Hour).Unix(),
+	})
+
+	maker := NewJWTTokenMaker(testSecretKey, 30*time.Second)
+	payload, err := maker.VerifyAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "a token issued beyond the leeway in the future is rejected")
+	assert.Nil(t, payload)
+
+	payload, err = NewPayload(uuid.New().String(), "testuser", "user", "", time.Minute)
+	require.NoError(t, err)
+	payload.IssuedAt = now.Add(10 * time.Minute).Unix()
+	assert.ErrorIs(t, payload.Valid(30*time.Second), jwt.ErrTokenUsedBeforeIssued)
+	assert.NoError(t, payload.Valid(time.Hour))
+}
+
+func TestJWTTokenMaker_VerifyAccessTokenWithGrace(t *testing.T) {
+	maker := NewJWTTokenMaker(testSecretKey, 0)
+	expired, _, err := maker.CreateAccessToken(uuid.New().String(), "testuser", "user", "", -time.Minute)
+	require.NoError(t, err)
+
+	_, graced, err := maker.VerifyAccessTokenWithGrace(expired, 0)
+	assert.ErrorIs(t, err, ErrExpiredToken, "no grace keeps verification strict")
+	assert.False(t, graced)
+
+	_, _, err = maker.VerifyAccessTokenWithGrace(expired, 30*time.Second)
+	assert.ErrorIs(t, err, ErrExpiredToken, "a token expired longer ago than the grace is rejected")
+
+	payload, graced, err := maker.VerifyAccessTokenWithGrace(expired, 2*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, graced)
+	assert.Equal(t, "testuser", payload.Username)
+
+	valid, _, err := maker.CreateAccessToken(uuid.New().String(), "testuser", "user", "", time.Minute)
+	require.NoError(t, err)
+	_, graced, err = maker.VerifyAccessTokenWithGrace(valid, 2*time.Minute)
+	require.NoError(t, err)
+	assert.False(t, graced, "an unexpired token does not use the grace")
+
+	_, _, err = maker.VerifyAccessTokenWithGrace("not-a-jwt", 2*time.Minute)
+	assert.Error(t, err)
+}
+
+func TestJWTTokenMaker_PreviousSecretKeys(t *testing.T) {
+	const previousSecretKey = "previous-secret-key-with-at-least-32-chars"
+	const unknownSecretKey = "unknown-secret-key-with-at-least-32-chars"
+
+	oldMaker := NewJWTTokenMaker(previousSecretKey, 0)
+	rotated := NewJWTTokenMaker(testSecretKey, 0, previousSecretKey)
+
+	_, err := rotated.VerifyAccessToken(mustCreateToken(t, oldMaker))
+	assert.NoError(t, err, "tokens signed with a previous secret still verify")
+
+	_, err = rotated.VerifyAccessToken(mustCreateToken(t, NewJWTTokenMaker(unknownSecretKey, 0)))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// New tokens are signed with the current secret only
+	_, err = oldMaker.VerifyAccessToken(mustCreateToken(t, rotated))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = NewJWTTokenMaker(testSecretKey, 0).VerifyAccessToken(mustCreateToken(t, rotated))
+	assert.NoError(t, err)
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":         uuid.New().String(),
+		"user_id":    uuid.New().String(),
+		"username":   "testuser",
+		"expired_at": time.Now().Add(-time.Hour).Unix(),
+	}).SignedString([]byte(previousSecretKey))
+	require.NoError(t, err)
+	_, err = rotated.VerifyAccessToken(expired)
+	assert.ErrorIs(t, err, ErrExpiredToken, "expiry is still enforced for tokens signed with a previous secret")
+}
+
+func mustCreateToken(t *testing.T, maker *JWTTokenMaker) string {
+	t.Helper()
+	signed, _, err := maker.CreateAccessToken(uuid.New().String(), "testuser", "user", "", time.Minute)
+	require.NoError(t, err)
+	return signed
+}
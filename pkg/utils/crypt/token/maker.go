@@ -1,9 +1,13 @@
 package token
 
+import "time"
+
 type TokenMaker interface {
-	CreateTokenPair(userID string, username string, duration int64) (string, string, error)
-	CreateAccessToken(userID string, username string, duration int64) (string, error)
-	CreateRefreshToken(userID string, username string, duration int64) (string, error)
+	// CreateTokenPair and CreateAccessToken also return the access token's claims, so
+	// callers report the expiry that was actually signed into it
+	CreateTokenPair(userID string, username string, role string, tenantID string, duration time.Duration) (string, string, *Payload, error)
+	CreateAccessToken(userID string, username string, role string, tenantID string, duration time.Duration) (string, *Payload, error)
+	CreateRefreshToken(userID string, username string, role string, tenantID string, duration time.Duration) (string, error)
 	VerifyAccessToken(token string) (*Payload, error)
 	VerifyRefreshToken(token string) (*Payload, error)
 }
@@ -1,6 +1,7 @@
 package token
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,33 +11,53 @@ import (
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	UserID    string    `json:"user_id"`
+	Subject   string    `json:"sub,omitempty"` // standard claim equal to UserID, absent on older tokens
 	Username  string    `json:"username"`
+	Role      string    `json:"role,omitempty"`
+	TenantID  string    `json:"tenant_id,omitempty"` // tenant the token was issued in, empty when tenancy is disabled
 	ExpiredAt int64     `json:"expired_at"`
 	IssuedAt  int64     `json:"issued_at"`
 }
 
-func NewPayload(userID string, username string, duration int64) (*Payload, error) {
+// NewPayload creates the claims of a token for the user of tenantID that expires after duration
+func NewPayload(userID string, username string, role string, tenantID string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	payload := &Payload{
 		ID:        tokenID,
 		UserID:    userID,
+		Subject:   userID,
 		Username:  username,
-		IssuedAt:  time.Now().Unix(),
-		ExpiredAt: time.Now().Add(time.Duration(duration) * time.Second).Unix(),
+		Role:      role,
+		TenantID:  tenantID,
+		IssuedAt:  now.Unix(),
+		ExpiredAt: now.Add(duration).Unix(),
 	}
 
 	return payload, nil
 }
 
-func (payload *Payload) Valid() error {
-	if time.Now().Unix() > payload.ExpiredAt {
+// Valid checks the time and non-time claims, allowing the expiry and issue times to be
+// off by at most leeway
+func (payload *Payload) Valid(leeway time.Duration) error {
+	now := time.Now()
+	if now.Add(-leeway).After(time.Unix(payload.ExpiredAt, 0)) {
 		return jwt.ErrTokenExpired
 	}
+	if now.Add(leeway).Before(time.Unix(payload.IssuedAt, 0)) {
+		return jwt.ErrTokenUsedBeforeIssued
+	}
+
+	return payload.Validate()
+}
 
+// Validate checks the non-time claims. It implements jwt.ClaimsValidator so the
+// parser runs it after the standard expiry checks.
+func (payload *Payload) Validate() error {
 	if payload.ID == uuid.Nil {
 		return jwt.ErrTokenInvalidId
 	}
@@ -45,6 +66,10 @@ func (payload *Payload) Valid() error {
 		return jwt.ErrTokenRequiredClaimMissing
 	}
 
+	if payload.Subject != "" && payload.Subject != payload.UserID {
+		return jwt.ErrTokenInvalidSubject
+	}
+
 	if payload.Username == "" {
 		return jwt.ErrTokenRequiredClaimMissing
 	}
@@ -52,6 +77,20 @@ func (payload *Payload) Valid() error {
 	return nil
 }
 
+// UserUUID parses the user ID claim into a UUID
+func (payload *Payload) UserUUID() (uuid.UUID, error) {
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid user id claim: %w", err)
+	}
+	return userID, nil
+}
+
+// ExpiresAt returns when the token expires
+func (payload *Payload) ExpiresAt() time.Time {
+	return time.Unix(payload.ExpiredAt, 0)
+}
+
 func (payload *Payload) GetExpirationTime() (*jwt.NumericDate, error) {
 	return jwt.NewNumericDate(time.Unix(payload.ExpiredAt, 0)), nil
 }
@@ -69,7 +108,7 @@ func (payload *Payload) GetIssuer() (string, error) {
 }
 
 func (payload *Payload) GetSubject() (string, error) {
-	return "", nil
+	return payload.Subject, nil
 }
 
 func (payload *Payload) GetAudience() (jwt.ClaimStrings, error) {
@@ -1,38 +1,36 @@
 package token
 
 import (
-	"time"
-
 	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
 
 	"github.com/google/uuid"
 )
 
 // RefreshToken represents a refresh token in the authentication system
 type RefreshToken struct {
-	ID        string    `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
-	TokenHash string    `json:"-"`
-	ExpiresAt int64     `json:"expires_at"`
-	IsRevoked bool      `json:"is_revoked"`
-	CreatedAt int64     `json:"created_at"`
-	UpdatedAt int64     `json:"updated_at"`
+	ID        string           `json:"id"`
+	UserID    uuid.UUID        `json:"userId"`
+	TokenHash string           `json:"-"`
+	ExpiresAt domain.Timestamp `json:"expiresAt"`
+	IsRevoked bool             `json:"isRevoked"`
+	CreatedAt domain.Timestamp `json:"createdAt"`
+	UpdatedAt domain.Timestamp `json:"updatedAt"`
 }
 
 // NewRefreshToken creates a new refresh token with generated ID and timestamps
-func NewRefreshToken(userID uuid.UUID, tokenHash string, expiresAt int64) (*RefreshToken, error) {
+func NewRefreshToken(userID uuid.UUID, tokenHash string, expiresAt domain.Timestamp) (*RefreshToken, error) {
 	if userID == uuid.Nil {
 		return nil, errs.ErrUserNotFound
 	}
 	if tokenHash == "" {
 		return nil, errs.ErrInvalidToken
 	}
-	if expiresAt <= time.Now().Unix() {
+	now := domain.Now()
+	if expiresAt <= now {
 		return nil, errs.ErrInvalidToken
 	}
 
-	now := time.Now().UnixMilli()
-
 	return &RefreshToken{
 		ID:        uuid.New().String(),
 		UserID:    userID,
@@ -55,7 +53,7 @@ func (rt *RefreshToken) IsValid() error {
 	if rt.IsRevoked {
 		return errs.ErrTokenRevoked
 	}
-	if time.Now().Unix() > rt.ExpiresAt {
+	if rt.IsExpired() {
 		return errs.ErrTokenExpired
 	}
 
@@ -64,11 +62,11 @@ func (rt *RefreshToken) IsValid() error {
 
 // IsExpired checks if the refresh token has expired
 func (rt *RefreshToken) IsExpired() bool {
-	return time.Now().Unix() > rt.ExpiresAt
+	return rt.ExpiresAt.Before(domain.Now())
 }
 
 // Revoke marks the refresh token as revoked
 func (rt *RefreshToken) Revoke() {
 	rt.IsRevoked = true
-	rt.UpdatedAt = time.Now().UnixMilli()
+	rt.UpdatedAt = domain.Now()
 }
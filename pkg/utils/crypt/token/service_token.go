@@ -0,0 +1,53 @@
+package token
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ServiceRole is the synthetic role granted to callers authenticated with a service token
+const ServiceRole = "service"
+
+type serviceTokenHash struct {
+	caller string
+	hash   []byte
+}
+
+// ServiceTokens verifies static tokens presented by trusted internal callers. Only the
+// SHA-256 hashes of the tokens are held; a caller may have several tokens so they can
+// be rotated without downtime.
+type ServiceTokens struct {
+	hashes []serviceTokenHash
+}
+
+// NewServiceTokens creates a verifier from hex encoded SHA-256 token hashes (see
+// HashToken) keyed by caller name
+func NewServiceTokens(hashesByCaller map[string][]string) (*ServiceTokens, error) {
+	tokens := &ServiceTokens{}
+	for caller, hashes := range hashesByCaller {
+		for _, encoded := range hashes {
+			hash, err := hex.DecodeString(strings.TrimSpace(encoded))
+			if err != nil || len(hash) != 32 {
+				return nil, fmt.Errorf("service token hash for %q must be a hex encoded SHA-256 digest", caller)
+			}
+			tokens.hashes = append(tokens.hashes, serviceTokenHash{caller: caller, hash: hash})
+		}
+	}
+	return tokens, nil
+}
+
+// VerifyServiceToken returns the caller owning token. Every configured hash is
+// compared in constant time so the check does not leak which tokens exist.
+func (t *ServiceTokens) VerifyServiceToken(token string) (string, bool) {
+	digest, _ := hex.DecodeString(HashToken(token))
+
+	caller := ""
+	for _, h := range t.hashes {
+		if subtle.ConstantTimeCompare(digest, h.hash) == 1 {
+			caller = h.caller
+		}
+	}
+	return caller, caller != ""
+}
@@ -0,0 +1,38 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceTokens(t *testing.T) {
+	tokens, err := NewServiceTokens(map[string][]string{
+		"wallet-api": {HashToken("old-token"), HashToken("new-token")},
+		"billing":    {HashToken("billing-token")},
+	})
+	require.NoError(t, err)
+
+	caller, ok := tokens.VerifyServiceToken("old-token")
+	assert.True(t, ok)
+	assert.Equal(t, "wallet-api", caller)
+
+	caller, ok = tokens.VerifyServiceToken("new-token")
+	assert.True(t, ok)
+	assert.Equal(t, "wallet-api", caller)
+
+	caller, ok = tokens.VerifyServiceToken("billing-token")
+	assert.True(t, ok)
+	assert.Equal(t, "billing", caller)
+
+	_, ok = tokens.VerifyServiceToken("unknown-token")
+	assert.False(t, ok)
+	_, ok = tokens.VerifyServiceToken("")
+	assert.False(t, ok)
+}
+
+func TestNewServiceTokens_InvalidHash(t *testing.T) {
+	_, err := NewServiceTokens(map[string][]string{"wallet-api": {"plaintext-token"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package cx
+
+import (
+	"context"
+	"time"
+
+	"wallet-user-svc/pkg/utils/crypt/token"
+
+	"github.com/google/uuid"
+)
+
+type authPayloadContextKey struct{}
+
+type authUserIDContextKey struct{}
+
+type authExpiryContextKey struct{}
+
+// WithAuthPayload adds the verified access token payload to the context
+func WithAuthPayload(ctx context.Context, payload *token.Payload) context.Context {
+	return context.WithValue(ctx, authPayloadContextKey{}, payload)
+}
+
+// GetAuthPayload retrieves the verified access token payload from the context
+func GetAuthPayload(ctx context.Context) (*token.Payload, bool) {
+	payload, ok := ctx.Value(authPayloadContextKey{}).(*token.Payload)
+	return payload, ok
+}
+
+// WithAuthUserID adds the authenticated user's parsed ID to the context
+func WithAuthUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, authUserIDContextKey{}, userID)
+}
+
+// GetAuthUserID retrieves the authenticated user's ID from the context
+func GetAuthUserID(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(authUserIDContextKey{}).(uuid.UUID)
+	return userID, ok
+}
+
+// WithAuthExpiry adds the expiry of the verified access token to the context
+func WithAuthExpiry(ctx context.Context, expiry time.Time) context.Context {
+	return context.WithValue(ctx, authExpiryContextKey{}, expiry)
+}
+
+// GetAuthExpiry retrieves the expiry of the verified access token from the context. The
+// token is only checked once by the auth interceptor, so a long-running handler can use
+// it to stop work that would finish after the caller's credentials lapsed. It is absent
+// for public methods and service tokens.
+func GetAuthExpiry(ctx context.Context) (time.Time, bool) {
+	expiry, ok := ctx.Value(authExpiryContextKey{}).(time.Time)
+	return expiry, ok
+}
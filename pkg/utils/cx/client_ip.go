@@ -0,0 +1,17 @@
+package cx
+
+import "context"
+
+type clientIPContextKey struct{}
+
+// WithClientIP adds the originating client IP of the request to the context
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPContextKey{}, ip)
+}
+
+// GetClientIP retrieves the client IP of the request from the context. It is empty when
+// the peer address was unknown.
+func GetClientIP(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPContextKey{}).(string)
+	return ip, ok
+}
@@ -12,27 +12,22 @@ type contextKey string
 
 const (
 	TransactionContextKey contextKey = "txKey"
-	LoggerContextKey      contextKey = "loggerKey"
 )
 
-// WithLogger adds a logger to the context
+// WithLogger adds a logger to the context. It shares the context key of the log
+// package, so loggers stored here are seen by logutils.GetLoggerOrDefault and vice versa.
 func WithLogger(ctx context.Context, logger *logrus.Entry) context.Context {
-	return context.WithValue(ctx, LoggerContextKey, logger)
+	return logutils.WithLogger(ctx, logger)
 }
 
 // GetLoggerFromContext retrieves a logger from the context
 func GetLoggerFromContext(ctx context.Context) (*logrus.Entry, bool) {
-	logger, ok := ctx.Value(LoggerContextKey).(*logrus.Entry)
-	return logger, ok
+	return logutils.GetLoggerFromContext(ctx)
 }
 
 // GetLoggerOrDefault retrieves a logger from context or returns the default logger
 func GetLoggerOrDefault(ctx context.Context) *logrus.Entry {
-	if logger, ok := GetLoggerFromContext(ctx); ok {
-		return logger
-	}
-
-	return logutils.GetLogger().WithField("context", "default").WithField("service", "context")
+	return logutils.GetLoggerOrDefault(ctx)
 }
 
 // WithRequestID adds a request ID to the logger and context
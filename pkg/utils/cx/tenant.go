@@ -0,0 +1,17 @@
+package cx
+
+import "context"
+
+type tenantIDContextKey struct{}
+
+// WithTenantID adds the tenant the request is scoped to to the context
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDContextKey{}, tenantID)
+}
+
+// GetTenantID retrieves the tenant of the request from the context. It is absent when
+// tenancy is disabled.
+func GetTenantID(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantIDContextKey{}).(string)
+	return tenantID, ok
+}
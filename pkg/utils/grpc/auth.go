@@ -0,0 +1,186 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/internal/app/model/domain"
+	"wallet-user-svc/pkg/utils/crypt/token"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AccessLevel describes who is allowed to call a gRPC method
+type AccessLevel int
+
+const (
+	// AccessAuthenticated requires a valid access token. It is the zero value so
+	// methods missing from the policy map are protected by default.
+	AccessAuthenticated AccessLevel = iota
+	// AccessPublic allows unauthenticated callers
+	AccessPublic
+	// AccessAdmin requires a valid access token carrying the admin role
+	AccessAdmin
+	// AccessInternal requires a service token of a trusted internal caller
+	AccessInternal
+)
+
+// AccessTokenVerifier verifies access tokens presented by callers
+type AccessTokenVerifier interface {
+	VerifyAccessToken(token string) (*token.Payload, error)
+	// VerifyAccessTokenWithGrace also accepts tokens that expired at most grace ago and
+	// reports whether the grace was needed
+	VerifyAccessTokenWithGrace(token string, grace time.Duration) (*token.Payload, bool, error)
+}
+
+// ExpiryGrace accepts access tokens that expired at most Grace ago on read-only methods,
+// so a client racing its token refresh is not failed for a harmless read. The zero value
+// accepts no expired token anywhere.
+type ExpiryGrace struct {
+	Grace time.Duration
+	// ReadOnlyMethods lists the full method names the grace applies to
+	ReadOnlyMethods map[string]bool
+}
+
+// For returns the grace that applies to method
+func (g ExpiryGrace) For(method string) time.Duration {
+	if !g.ReadOnlyMethods[method] {
+		return 0
+	}
+	return max(g.Grace, 0)
+}
+
+// ServiceTokenVerifier verifies static service tokens and returns the owning caller
+type ServiceTokenVerifier interface {
+	VerifyServiceToken(token string) (string, bool)
+}
+
+// AuthInterceptor is a gRPC interceptor that enforces the access level of each method.
+// The verified token payload and the parsed user ID are stored in the context for
+// handlers via cx.GetAuthPayload and cx.GetAuthUserID. Internal methods instead accept
+// a service token and store a synthetic payload with the service role and the caller
+// name as username.
+//
+// The auth decision is made once, here: a token that expires while the handler runs
+// does not fail the request. Handlers that must not outlive the token read its expiry
+// with cx.GetAuthExpiry.
+//
+// When the request is scoped to a tenant (see TenantInterceptor, which must run first),
+// access tokens issued in any other tenant are rejected.
+//
+// Methods marked read-only in grace also accept a recently expired access token; each
+// such use is logged.
+func AuthInterceptor(verifier AccessTokenVerifier, serviceTokens ServiceTokenVerifier, policies map[string]AccessLevel, grace ExpiryGrace) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		level := policies[info.FullMethod]
+		if level == AccessPublic {
+			return handler(ctx, req)
+		}
+
+		// Get logger from context, fallback to default if not available
+		logger := logutils.GetLoggerOrDefault(ctx)
+
+		accessToken, ok := bearerTokenFromContext(ctx)
+		if !ok {
+			logger.WithField("method", info.FullMethod).Warn("Missing access token")
+			return nil, errs.ErrUnauthenticated
+		}
+
+		if level == AccessInternal {
+			caller, ok := serviceTokens.VerifyServiceToken(accessToken)
+			if !ok {
+				logger.WithField("method", info.FullMethod).Warn("Service token verification failed")
+				return nil, errs.ErrPermissionDenied
+			}
+
+			ctx = logutils.WithContextFields(ctx, logrus.Fields{"service_caller": caller})
+			ctx = cx.WithAuthPayload(ctx, &token.Payload{Username: caller, Role: token.ServiceRole})
+			return handler(ctx, req)
+		}
+
+		payload, graced, err := verifier.VerifyAccessTokenWithGrace(accessToken, grace.For(info.FullMethod))
+		if err != nil {
+			logger.WithError(err).WithField("method", info.FullMethod).Warn("Access token verification failed")
+			return nil, authError(err)
+		}
+		if graced {
+			logger.WithFields(logrus.Fields{
+				"method":      info.FullMethod,
+				"user_id":     payload.UserID,
+				"expired_for": time.Since(time.Unix(payload.ExpiredAt, 0)).Round(time.Second).String(),
+			}).Info("Accepted expired access token within the read-only grace period")
+		}
+
+		userID, err := payload.UserUUID()
+		if err != nil {
+			logger.WithError(err).WithField("method", info.FullMethod).Warn("Access token carries an invalid user ID")
+			return nil, errs.ErrUnauthenticated
+		}
+
+		// A token is only good in the tenant it was issued in; the x-tenant-id metadata
+		// alone must not let a caller, admins included, act on another tenant
+		if tenantID, ok := cx.GetTenantID(ctx); ok && payload.TenantID != tenantID {
+			logger.WithFields(logrus.Fields{
+				"method":       info.FullMethod,
+				"user_id":      payload.UserID,
+				"token_tenant": payload.TenantID,
+			}).Warn("Access token used outside its tenant")
+			return nil, errs.ErrPermissionDenied
+		}
+
+		if level == AccessAdmin && !domain.Role(payload.Role).IsAdmin() {
+			logger.WithFields(logrus.Fields{
+				"method":  info.FullMethod,
+				"user_id": payload.UserID,
+				"role":    payload.Role,
+			}).Warn("Admin access denied")
+			return nil, errs.ErrPermissionDenied
+		}
+
+		ctx = logutils.WithUserID(ctx, payload.UserID)
+		ctx = cx.WithAuthPayload(ctx, payload)
+		ctx = cx.WithAuthUserID(ctx, userID)
+		ctx = cx.WithAuthExpiry(ctx, time.Unix(payload.ExpiredAt, 0))
+
+		return handler(ctx, req)
+	}
+}
+
+// bearerTokenFromContext extracts the token from the "authorization: Bearer <token>" metadata
+func bearerTokenFromContext(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+
+	scheme, accessToken, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || accessToken == "" {
+		return "", false
+	}
+
+	return accessToken, true
+}
+
+// authError converts a token verification failure into a client-facing error
+func authError(err error) error {
+	var wrapper *errs.ErrorWrapper
+	if errors.As(err, &wrapper) {
+		return wrapper
+	}
+	if errors.Is(err, token.ErrExpiredToken) {
+		return errs.ErrTokenExpired
+	}
+	return errs.ErrUnauthenticated
+}
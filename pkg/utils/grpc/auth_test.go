@@ -0,0 +1,211 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/pkg/utils/crypt/token"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	testSecretKey     = "test-secret-key-with-at-least-32-chars"
+	publicMethod      = "/user.UserService/Login"
+	protectedMethod   = "/user.UserService/Protected"
+	adminMethod       = "/user.UserService/GetRuntimeInfo"
+	unregisteredRoute = "/user.UserService/Unknown"
+	internalMethod    = "/user.UserService/Internal"
+	testServiceToken  = "test-service-token"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	maker := token.NewJWTTokenMaker(testSecretKey, 0)
+	serviceTokens, err := token.NewServiceTokens(map[string][]string{"wallet-api": {token.HashToken(testServiceToken)}})
+	require.NoError(t, err)
+	interceptor := AuthInterceptor(maker, serviceTokens, map[string]AccessLevel{
+		publicMethod:    AccessPublic,
+		protectedMethod: AccessAuthenticated,
+		adminMethod:     AccessAdmin,
+		internalMethod:  AccessInternal,
+	}, ExpiryGrace{})
+
+	userToken, _, err := maker.CreateAccessToken(uuid.New().String(), "testuser", "user", "", time.Minute)
+	require.NoError(t, err)
+	adminToken, _, err := maker.CreateAccessToken(uuid.New().String(), "adminuser", "admin", "", time.Minute)
+	require.NoError(t, err)
+	badUserIDToken, _, err := maker.CreateAccessToken("not-a-uuid", "testuser", "user", "", time.Minute)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		expectedErr   error
+	}{
+		{name: "public method without token", method: publicMethod},
+		{name: "protected method without token", method: protectedMethod, expectedErr: errs.ErrUnauthenticated},
+		{name: "unlisted method defaults to authenticated", method: unregisteredRoute, expectedErr: errs.ErrUnauthenticated},
+		{name: "protected method with user token", method: protectedMethod, authorization: "Bearer " + userToken},
+		{name: "protected method with malformed header", method: protectedMethod, authorization: userToken, expectedErr: errs.ErrUnauthenticated},
+		{name: "protected method with invalid token", method: protectedMethod, authorization: "Bearer not-a-jwt", expectedErr: errs.ErrMalformedToken},
+		{name: "admin method with user token", method: adminMethod, authorization: "Bearer " + userToken, expectedErr: errs.ErrPermissionDenied},
+		{name: "admin method with admin token", method: adminMethod, authorization: "bearer " + adminToken},
+		{name: "token with non-UUID user ID", method: protectedMethod, authorization: "Bearer " + badUserIDToken, expectedErr: errs.ErrUnauthenticated},
+		{name: "internal method without token", method: internalMethod, expectedErr: errs.ErrUnauthenticated},
+		{name: "internal method with user token", method: internalMethod, authorization: "Bearer " + adminToken, expectedErr: errs.ErrPermissionDenied},
+		{name: "service token on protected method", method: protectedMethod, authorization: "Bearer " + testServiceToken, expectedErr: errs.ErrMalformedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+
+			var handlerCtx context.Context
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCtx = ctx
+				return "ok", nil
+			}
+
+			resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp)
+			if tt.authorization != "" {
+				_, ok := cx.GetAuthPayload(handlerCtx)
+				assert.True(t, ok, "verified payload should be stored in context")
+				userID, ok := cx.GetAuthUserID(handlerCtx)
+				assert.True(t, ok, "parsed user ID should be stored in context")
+				assert.NotEqual(t, uuid.Nil, userID)
+				expiry, ok := cx.GetAuthExpiry(handlerCtx)
+				assert.True(t, ok, "token expiry should be stored in context")
+				assert.WithinDuration(t, time.Now().Add(60*time.Second), expiry, 5*time.Second)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_ExpiryGrace(t *testing.T) {
+	const readOnlyMethod = "/user.UserService/ListSessions"
+	maker := token.NewJWTTokenMaker(testSecretKey, 0)
+	expiredToken, _, err := maker.CreateAccessToken(uuid.New().String(), "testuser", "user", "", -30*time.Second)
+	require.NoError(t, err)
+	policies := map[string]AccessLevel{readOnlyMethod: AccessAuthenticated, protectedMethod: AccessAuthenticated}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+expiredToken))
+
+	tests := []struct {
+		name        string
+		grace       ExpiryGrace
+		method      string
+		expectedErr error
+	}{
+		{name: "read-only method within grace", grace: ExpiryGrace{Grace: time.Minute, ReadOnlyMethods: map[string]bool{readOnlyMethod: true}}, method: readOnlyMethod},
+		{name: "write method stays strict", grace: ExpiryGrace{Grace: time.Minute, ReadOnlyMethods: map[string]bool{readOnlyMethod: true}}, method: protectedMethod, expectedErr: errs.ErrTokenExpired},
+		{name: "read-only method beyond grace", grace: ExpiryGrace{Grace: 10 * time.Second, ReadOnlyMethods: map[string]bool{readOnlyMethod: true}}, method: readOnlyMethod, expectedErr: errs.ErrTokenExpired},
+		{name: "no grace by default", method: readOnlyMethod, expectedErr: errs.ErrTokenExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := AuthInterceptor(maker, nil, policies, tt.grace)
+
+			resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp)
+		})
+	}
+}
+
+func TestAuthInterceptor_TenantBinding(t *testing.T) {
+	maker := token.NewJWTTokenMaker(testSecretKey, 0)
+	auth := AuthInterceptor(maker, nil, map[string]AccessLevel{adminMethod: AccessAdmin}, ExpiryGrace{})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	tenantAAdmin, _, err := maker.CreateAccessToken(uuid.New().String(), "adminuser", "admin", "tenant-a", time.Minute)
+	require.NoError(t, err)
+	untenantedAdmin, _, err := maker.CreateAccessToken(uuid.New().String(), "adminuser", "admin", "", time.Minute)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		tenancy       bool
+		tenant        string
+		authorization string
+		expectedErr   error
+	}{
+		{name: "admin in own tenant", tenancy: true, tenant: "tenant-a", authorization: tenantAAdmin},
+		{name: "admin of another tenant", tenancy: true, tenant: "tenant-b", authorization: tenantAAdmin, expectedErr: errs.ErrPermissionDenied},
+		{name: "token without tenant under tenancy", tenancy: true, tenant: "tenant-a", authorization: untenantedAdmin, expectedErr: errs.ErrPermissionDenied},
+		{name: "tenancy disabled", authorization: untenantedAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := TenantInterceptor(tt.tenancy, nil)
+			md := metadata.Pairs("authorization", "Bearer "+tt.authorization)
+			if tt.tenant != "" {
+				md.Set(TenantMetadataKey, tt.tenant)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+			info := &grpc.UnaryServerInfo{FullMethod: adminMethod}
+
+			resp, err := tenant(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return auth(ctx, req, info, handler)
+			})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resp)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp)
+		})
+	}
+}
+
+func TestAuthInterceptor_ServiceToken(t *testing.T) {
+	serviceTokens, err := token.NewServiceTokens(map[string][]string{"wallet-api": {token.HashToken(testServiceToken)}})
+	require.NoError(t, err)
+	interceptor := AuthInterceptor(token.NewJWTTokenMaker(testSecretKey, 0), serviceTokens, map[string]AccessLevel{
+		internalMethod: AccessInternal,
+	}, ExpiryGrace{})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+testServiceToken))
+	var payload *token.Payload
+	var handlerCtx context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		payload, _ = cx.GetAuthPayload(ctx)
+		handlerCtx = ctx
+		return "ok", nil
+	}
+
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: internalMethod}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+	require.NotNil(t, payload)
+	assert.Equal(t, token.ServiceRole, payload.Role)
+	assert.Equal(t, "wallet-api", payload.Username)
+	_, ok := cx.GetAuthExpiry(handlerCtx)
+	assert.False(t, ok, "service tokens do not expire")
+}
@@ -5,27 +5,32 @@ import (
 	"google.golang.org/grpc"
 )
 
-// GetUnaryInterceptors returns a single chained unary interceptor as server option
-func GetUnaryInterceptors(logger *logrus.Logger) []grpc.ServerOption {
+// GetUnaryInterceptors returns a single chained unary interceptor as server option.
+// Additional interceptors (e.g. authentication) run after the built-in ones, so they
+// benefit from the context logger, panic recovery, logging and error conversion.
+// exposePanicDetails returns recovered panic values to clients and capturePanicStack logs
+// their stack traces (see PanicRecoveryInterceptor).
+func GetUnaryInterceptors(logger *logrus.Logger, exposePanicDetails, capturePanicStack bool, additional ...grpc.UnaryServerInterceptor) []grpc.ServerOption {
 	// Chain the interceptors in the desired order
 	// ContextLoggerInterceptor should be first to ensure logger is available in context
-	chainedInterceptor := grpc.ChainUnaryInterceptor(
+	interceptors := []grpc.UnaryServerInterceptor{
 		ContextLoggerInterceptor(logger),
-		PanicRecoveryInterceptor(),
+		PanicRecoveryInterceptor(exposePanicDetails, capturePanicStack),
 		LoggingInterceptor(),
 		ErrorHandlingInterceptor(),
-	)
+	}
+	chainedInterceptor := grpc.ChainUnaryInterceptor(append(interceptors, additional...)...)
 
 	return []grpc.ServerOption{chainedInterceptor}
 }
 
 // GetStreamInterceptors returns a single chained stream interceptor as server option
-func GetStreamInterceptors(logger *logrus.Logger) []grpc.ServerOption {
+func GetStreamInterceptors(logger *logrus.Logger, exposePanicDetails, capturePanicStack bool) []grpc.ServerOption {
 	// Chain the stream interceptors in the desired order
 	// ContextLoggerStreamInterceptor should be first to ensure logger is available in context
 	chainedInterceptor := grpc.ChainStreamInterceptor(
 		ContextLoggerStreamInterceptor(logger),
-		StreamPanicRecoveryInterceptor(),
+		StreamPanicRecoveryInterceptor(exposePanicDetails, capturePanicStack),
 		StreamLoggingInterceptor(),
 	)
 
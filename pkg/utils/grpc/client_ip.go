@@ -0,0 +1,24 @@
+package grpc
+
+import (
+	"context"
+
+	"wallet-user-svc/pkg/utils/cx"
+
+	"google.golang.org/grpc"
+)
+
+// ClientIPResolver determines the originating client IP of a request, honouring
+// forwarding headers only from trusted proxies
+type ClientIPResolver interface {
+	ClientIP(ctx context.Context) string
+}
+
+// ClientIPInterceptor resolves the client IP once per request and stores it with
+// cx.WithClientIP, so rate limiting, auditing and other consumers read the same
+// address instead of each re-deriving it from the peer
+func ClientIPInterceptor(resolver ClientIPResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(cx.WithClientIP(ctx, resolver.ClientIP(ctx)), req)
+	}
+}
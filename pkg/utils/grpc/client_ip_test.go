@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"wallet-user-svc/pkg/utils/cx"
+	"wallet-user-svc/pkg/utils/netutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIPInterceptor(t *testing.T) {
+	trustedProxies, err := netutil.ParseCIDRs([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	interceptor := ClientIPInterceptor(netutil.NewClientIPResolver(trustedProxies))
+	info := &grpc.UnaryServerInfo{FullMethod: publicMethod}
+
+	clientIP := func(peerAddr string, md metadata.MD) string {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(peerAddr), Port: 50000}})
+		ctx = metadata.NewIncomingContext(ctx, md)
+
+		var got string
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			ip, ok := cx.GetClientIP(ctx)
+			require.True(t, ok, "the client IP must be in the handler context")
+			got = ip
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return got
+	}
+
+	forwarded := metadata.Pairs(netutil.ForwardedForHeader, "203.0.113.7")
+	assert.Equal(t, "203.0.113.7", clientIP("10.0.0.1", forwarded), "trusted proxies may forward the client IP")
+	assert.Equal(t, "198.51.100.1", clientIP("198.51.100.1", forwarded), "untrusted peers cannot spoof it")
+}
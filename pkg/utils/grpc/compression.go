@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// CompressionInterceptor is a gRPC interceptor that gzip-compresses responses for
+// clients advertising gzip in grpc-accept-encoding. Importing this package registers
+// the gzip codec, so gzip-compressed requests are always accepted. When disabled,
+// responses are sent uncompressed even to clients that compressed their request.
+func CompressionInterceptor(enabled bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		compressor := encoding.Identity
+		if enabled {
+			if supported, err := grpc.ClientSupportedCompressors(ctx); err == nil && slices.Contains(supported, gzip.Name) {
+				compressor = gzip.Name
+			}
+		}
+
+		// Fails only outside a real server stream, e.g. in unit tests calling the handler directly
+		_ = grpc.SetSendCompressor(ctx, compressor)
+
+		return handler(ctx, req)
+	}
+}
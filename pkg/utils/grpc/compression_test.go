@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// responseEncodingRecorder records the grpc-encoding of every response header received
+type responseEncodingRecorder struct {
+	mu        sync.Mutex
+	encodings []string
+}
+
+func (r *responseEncodingRecorder) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (r *responseEncodingRecorder) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if header, ok := s.(*stats.InHeader); ok && header.Client {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.encodings = append(r.encodings, header.Compression)
+	}
+}
+
+func (r *responseEncodingRecorder) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (r *responseEncodingRecorder) HandleConn(context.Context, stats.ConnStats) {}
+
+// checkResponseEncoding calls the health service through CompressionInterceptor and
+// returns the encoding of the response
+func checkResponseEncoding(t *testing.T, enabled bool, callOptions ...grpc.CallOption) string {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnaryInterceptor(CompressionInterceptor(enabled)))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	recorder := &responseEncodingRecorder{}
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(recorder),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}, callOptions...)
+	require.NoError(t, err)
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.Len(t, recorder.encodings, 1)
+	return recorder.encodings[0]
+}
+
+func TestCompressionInterceptor(t *testing.T) {
+	// Clients advertise every registered codec, including gzip, in grpc-accept-encoding
+	assert.Equal(t, gzip.Name, checkResponseEncoding(t, true))
+
+	assert.Equal(t, encoding.Identity, checkResponseEncoding(t, false), "responses stay uncompressed when disabled")
+	assert.Equal(t, encoding.Identity, checkResponseEncoding(t, false, grpc.UseCompressor(gzip.Name)),
+		"compressed requests are accepted but not answered compressed when disabled")
+}
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestContextLoggerInterceptor_LoggerReachesHandler(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	interceptor := ContextLoggerInterceptor(logger)
+	info := &grpc.UnaryServerInfo{FullMethod: publicMethod}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		fromLog, ok := logutils.GetLoggerFromContext(ctx)
+		require.True(t, ok)
+		assert.Same(t, logger, fromLog.Logger)
+		assert.Equal(t, publicMethod, fromLog.Data["grpc_method"])
+
+		fromCx, ok := cx.GetLoggerFromContext(ctx)
+		require.True(t, ok, "cx and log share the logger context key")
+		assert.Same(t, fromLog, fromCx)
+		assert.Same(t, fromLog, cx.GetLoggerOrDefault(ctx))
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
@@ -2,6 +2,9 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strconv"
 	"time"
 
 	"wallet-user-svc/internal/app/errs"
@@ -9,10 +12,13 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-// ErrorHandlingInterceptor is a gRPC interceptor that handles errors and converts them to proper gRPC status codes
+// ErrorHandlingInterceptor is a gRPC interceptor that handles errors and converts them to proper gRPC status codes.
+// Rate limit errors also set the retry-after, x-ratelimit-limit and x-ratelimit-remaining response headers,
+// which HTTP proxies transcoding gRPC to REST can pass on to browser clients.
 func ErrorHandlingInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		// Get logger from context, fallback to default if not available
@@ -23,12 +29,28 @@ func ErrorHandlingInterceptor() grpc.UnaryServerInterceptor {
 
 		// If there's an error, handle it
 		if err != nil {
-			// Log the error
-			logger.WithFields(logrus.Fields{
+			// Record where the error originated unless the service already did. Sentinel
+			// errors are shared between requests, so a copy is annotated.
+			if wrapper, ok := err.(*errs.ErrorWrapper); ok && wrapper.Operation == "" {
+				err = wrapper.Clone().WithOperation(info.FullMethod)
+			}
+
+			var rateLimitErr *errs.RateLimitError
+			if errors.As(err, &rateLimitErr) {
+				setRateLimitHeaders(ctx, rateLimitErr)
+			}
+
+			// Log the error, with the stack trace captured when it was created if any
+			fields := logrus.Fields{
 				"method":    info.FullMethod,
 				"error":     err.Error(),
 				"timestamp": time.Now().UTC(),
-			}).Error("gRPC error occurred")
+			}
+			var wrapper *errs.ErrorWrapper
+			if errors.As(err, &wrapper) && wrapper.StackTrace != "" {
+				fields["stack_trace"] = wrapper.StackTrace
+			}
+			logger.WithFields(fields).Error("gRPC error occurred")
 
 			// Convert to gRPC error if it's not already
 			if _, ok := status.FromError(err); !ok {
@@ -40,6 +62,21 @@ func ErrorHandlingInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// setRateLimitHeaders sends the HTTP rate limit headers for a rate limit error as response
+// header metadata. retry-after is in whole seconds, rounded up.
+func setRateLimitHeaders(ctx context.Context, err *errs.RateLimitError) {
+	md := metadata.Pairs("x-ratelimit-remaining", "0")
+	if err.Limit > 0 {
+		md.Set("x-ratelimit-limit", strconv.Itoa(err.Limit))
+	}
+	if err.RetryAfter > 0 {
+		md.Set("retry-after", strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	}
+
+	// Fails only outside a real server stream, e.g. in unit tests calling the handler directly
+	_ = grpc.SetHeader(ctx, md)
+}
+
 // CustomErrorHandler provides custom error handling for gRPC streams
 func CustomErrorHandler(logger *logrus.Logger) func(error) {
 	return func(err error) {
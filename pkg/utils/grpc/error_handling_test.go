@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const getUserMethod = "/user.UserService/GetUser"
+
+func TestErrorHandlingInterceptor_SetsOperation(t *testing.T) {
+	interceptor := ErrorHandlingInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, errs.ErrRegistrationDisabled
+	})
+
+	var wrapper *errs.ErrorWrapper
+	require.ErrorAs(t, err, &wrapper)
+	assert.Equal(t, getUserMethod, wrapper.Operation)
+	assert.Empty(t, errs.ErrRegistrationDisabled.Operation, "the shared sentinel must not be modified")
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, getUserMethod, st.Details()[0].(*errdetails.ErrorInfo).Metadata["operation"])
+}
+
+func TestErrorHandlingInterceptor_KeepsExistingOperation(t *testing.T) {
+	interceptor := ErrorHandlingInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, errs.NewError(codes.Internal, "lookup failed").WithOperation("UserRepository.GetByID")
+	})
+
+	var wrapper *errs.ErrorWrapper
+	require.ErrorAs(t, err, &wrapper)
+	assert.Equal(t, "UserRepository.GetByID", wrapper.Operation)
+}
+
+// headerRecordingStream is a grpc.ServerTransportStream that records the headers set by handlers
+type headerRecordingStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerRecordingStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestErrorHandlingInterceptor_RateLimitHeaders(t *testing.T) {
+	interceptor := ErrorHandlingInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+	stream := &headerRecordingStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+	_, err := interceptor(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, errs.NewRateLimitError(5, 1500*time.Millisecond)
+	})
+
+	assert.ErrorIs(t, err, errs.ErrTooManyRequests)
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, 1500*time.Millisecond, st.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+
+	assert.Equal(t, []string{"2"}, stream.header.Get("retry-after"))
+	assert.Equal(t, []string{"5"}, stream.header.Get("x-ratelimit-limit"))
+	assert.Equal(t, []string{"0"}, stream.header.Get("x-ratelimit-remaining"))
+}
+
+func TestErrorHandlingInterceptor_NoRateLimitHeadersForOtherErrors(t *testing.T) {
+	interceptor := ErrorHandlingInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+	stream := &headerRecordingStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+	_, err := interceptor(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, errs.ErrUserNotFound
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Empty(t, stream.header)
+}
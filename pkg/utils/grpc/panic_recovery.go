@@ -2,9 +2,11 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"runtime/debug"
 	"time"
 
+	"wallet-user-svc/internal/app/errs"
 	logutils "wallet-user-svc/pkg/utils/log"
 
 	"github.com/sirupsen/logrus"
@@ -13,24 +15,19 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// PanicRecoveryInterceptor is a gRPC interceptor that recovers from panics
-func PanicRecoveryInterceptor() grpc.UnaryServerInterceptor {
+// PanicRecoveryInterceptor is a gRPC interceptor that recovers from panics. Clients get
+// an opaque Internal error unless exposeDetails is set, which is meant for non-production
+// environments only. The panic is logged with its stack trace if captureStack is set.
+func PanicRecoveryInterceptor(exposeDetails, captureStack bool) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		// Get logger from context, fallback to default if not available
 		logger := logutils.GetLoggerOrDefault(ctx)
 
 		defer func() {
 			if r := recover(); r != nil {
-				// Log the panic with stack trace
-				logger.WithFields(logrus.Fields{
-					"method":      info.FullMethod,
-					"panic":       r,
-					"stack_trace": string(debug.Stack()),
-					"timestamp":   time.Now().UTC(),
-				}).Error("gRPC panic recovered")
+				logger.WithFields(panicFields(info.FullMethod, r, captureStack)).Error("gRPC panic recovered")
 
-				// Create a proper gRPC error response
-				err = status.Error(codes.Internal, "Internal server error occurred")
+				err = panicError(r, info.FullMethod, exposeDetails)
 			}
 		}()
 
@@ -39,24 +36,18 @@ func PanicRecoveryInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
-// StreamPanicRecoveryInterceptor is a gRPC stream interceptor that recovers from panics
-func StreamPanicRecoveryInterceptor() grpc.StreamServerInterceptor {
+// StreamPanicRecoveryInterceptor is a gRPC stream interceptor that recovers from panics,
+// exposing their details to clients and logging their stack like PanicRecoveryInterceptor
+func StreamPanicRecoveryInterceptor(exposeDetails, captureStack bool) grpc.StreamServerInterceptor {
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 		// Get logger from context, fallback to default if not available
 		logger := logutils.GetLoggerOrDefault(stream.Context())
 
 		defer func() {
 			if r := recover(); r != nil {
-				// Log the panic with stack trace
-				logger.WithFields(logrus.Fields{
-					"method":      info.FullMethod,
-					"panic":       r,
-					"stack_trace": string(debug.Stack()),
-					"timestamp":   time.Now().UTC(),
-				}).Error("gRPC stream panic recovered")
+				logger.WithFields(panicFields(info.FullMethod, r, captureStack)).Error("gRPC stream panic recovered")
 
-				// Create a proper gRPC error response
-				err = status.Error(codes.Internal, "Internal server error occurred")
+				err = panicError(r, info.FullMethod, exposeDetails)
 			}
 		}()
 
@@ -64,3 +55,29 @@ func StreamPanicRecoveryInterceptor() grpc.StreamServerInterceptor {
 		return handler(srv, stream)
 	}
 }
+
+// panicFields returns the log fields of a recovered panic, including the stack trace of
+// the panicking goroutine if captureStack is set
+func panicFields(method string, recovered interface{}, captureStack bool) logrus.Fields {
+	fields := logrus.Fields{
+		"method":    method,
+		"panic":     recovered,
+		"timestamp": time.Now().UTC(),
+	}
+	if captureStack {
+		fields["stack_trace"] = string(debug.Stack())
+	}
+	return fields
+}
+
+// panicError creates the error returned for a recovered panic. With exposeDetails the
+// panic value is attached as a detail, which clients receive in the ErrorInfo metadata.
+func panicError(recovered interface{}, method string, exposeDetails bool) error {
+	if !exposeDetails {
+		return status.Error(codes.Internal, "Internal server error occurred")
+	}
+
+	return errs.ErrPanicRecovered.Clone().
+		WithDetail("panic", fmt.Sprint(recovered)).
+		WithOperation(method)
+}
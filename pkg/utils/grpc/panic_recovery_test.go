@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func panickingHandler(context.Context, interface{}) (interface{}, error) {
+	panic("nil map write in handler")
+}
+
+func TestPanicRecoveryInterceptor_Opaque(t *testing.T) {
+	interceptor := PanicRecoveryInterceptor(false, false)
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	resp, err := interceptor(context.Background(), nil, info, panickingHandler)
+
+	assert.Nil(t, resp)
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "Internal server error occurred", st.Message())
+	assert.Empty(t, st.Details())
+}
+
+func TestPanicRecoveryInterceptor_ExposeDetails(t *testing.T) {
+	interceptor := PanicRecoveryInterceptor(true, false)
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	resp, err := interceptor(context.Background(), nil, info, panickingHandler)
+
+	assert.Nil(t, resp)
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "Internal server error occurred", st.Message())
+	require.Len(t, st.Details(), 1)
+	errorInfo := st.Details()[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, "panic_recovered", errorInfo.Reason)
+	assert.Equal(t, "nil map write in handler", errorInfo.Metadata["panic"])
+	assert.Equal(t, getUserMethod, errorInfo.Metadata["operation"])
+	assert.Empty(t, errs.ErrPanicRecovered.Details, "the shared sentinel must not be modified")
+}
+
+func TestPanicRecoveryInterceptor_CaptureStack(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	for _, captureStack := range []bool{false, true} {
+		ctx, hook := newPayloadTestContext(logrus.InfoLevel)
+		_, err := PanicRecoveryInterceptor(false, captureStack)(ctx, nil, info, panickingHandler)
+		require.Error(t, err)
+
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		assert.Equal(t, "nil map write in handler", entry.Data["panic"])
+		stack, logged := entry.Data["stack_trace"]
+		assert.Equal(t, captureStack, logged, "captureStack %v", captureStack)
+		if captureStack {
+			assert.Contains(t, stack, "panickingHandler")
+		}
+	}
+}
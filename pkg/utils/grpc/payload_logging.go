@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// redactedPayloadValue replaces secret fields in logged payloads
+const redactedPayloadValue = "[REDACTED]"
+
+// secretPayloadFields are request fields whose values are never logged
+var secretPayloadFields = map[string]bool{
+	"password":      true,
+	"access_token":  true,
+	"refresh_token": true,
+	"code":          true,
+	"backup_code":   true,
+	"backup_codes":  true,
+	"secret":        true,
+}
+
+// PayloadLoggingInterceptor is a gRPC interceptor that logs the request of the listed
+// methods at Debug level, to help reproduce field validation bugs. Secrets such as
+// passwords, tokens and codes are redacted and emails and phone numbers are masked
+// before logging. It does nothing unless enabled and methods is not empty.
+func PayloadLoggingInterceptor(enabled bool, methods []string) grpc.UnaryServerInterceptor {
+	logged := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		logged[method] = true
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !enabled || !logged[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		logger := logutils.GetLoggerOrDefault(ctx)
+		if logger.Logger.IsLevelEnabled(logrus.DebugLevel) {
+			payload, err := maskedPayload(req)
+			if err != nil {
+				logger.WithError(err).WithField("method", info.FullMethod).Warn("Failed to marshal request payload for logging")
+			} else {
+				logger.WithFields(logrus.Fields{
+					"method":  info.FullMethod,
+					"payload": payload,
+				}).Debug("gRPC request payload")
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// maskedPayload marshals req to JSON with proto field names, redacting secrets and
+// masking personal data
+func maskedPayload(req interface{}) (string, error) {
+	var data []byte
+	var err error
+	if message, ok := req.(proto.Message); ok {
+		data, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(message)
+	} else {
+		data, err = json.Marshal(req)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", err
+	}
+	maskPayloadValue(payload)
+
+	masked, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(masked), nil
+}
+
+// maskPayloadFields masks the sensitive fields of a decoded payload in place, including
+// those of nested messages
+func maskPayloadFields(fields map[string]interface{}) {
+	for name, value := range fields {
+		switch {
+		case secretPayloadFields[name]:
+			fields[name] = redactedPayloadValue
+		case name == "email":
+			if email, ok := value.(string); ok {
+				fields[name] = maskEmail(email)
+			}
+		case name == "phone":
+			if phone, ok := value.(string); ok {
+				fields[name] = maskTail(phone, 2)
+			}
+		default:
+			maskPayloadValue(value)
+		}
+	}
+}
+
+func maskPayloadValue(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		maskPayloadFields(v)
+	case []interface{}:
+		for _, item := range v {
+			maskPayloadValue(item)
+		}
+	}
+}
+
+// maskEmail keeps the first character of the local part and the domain, which is
+// usually enough to tell test accounts apart without logging the address
+func maskEmail(email string) string {
+	local, domain, found := strings.Cut(email, "@")
+	if !found || local == "" {
+		return maskTail(email, 0)
+	}
+	return local[:1] + "***@" + domain
+}
+
+// maskTail replaces all but the last keep characters of value with asterisks
+func maskTail(value string, keep int) string {
+	if len(value) <= keep {
+		return strings.Repeat("*", len(value))
+	}
+	return strings.Repeat("*", len(value)-keep) + value[len(value)-keep:]
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	pb "wallet-user-svc/api/proto"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const registerMethod = "/user.UserService/Register"
+
+func newPayloadTestContext(level logrus.Level) (context.Context, *test.Hook) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(level)
+	hook := test.NewLocal(logger)
+	return logutils.WithLogger(context.Background(), logrus.NewEntry(logger)), hook
+}
+
+func TestPayloadLoggingInterceptor_MasksPII(t *testing.T) {
+	ctx, hook := newPayloadTestContext(logrus.DebugLevel)
+	interceptor := PayloadLoggingInterceptor(true, []string{registerMethod})
+	req := &pb.RegisterRequest{
+		Email:       "alice@example.com",
+		Username:    "alice",
+		Password:    "Password123!",
+		CountryCode: "TW",
+		Phone:       "+886912345678",
+	}
+
+	_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: registerMethod}, func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.DebugLevel, entry.Level)
+	assert.JSONEq(t, `{
+		"email": "a***@example.com",
+		"username": "alice",
+		"password": "[REDACTED]",
+		"country_code": "TW",
+		"phone": "***********78"
+	}`, entry.Data["payload"].(string))
+}
+
+func TestPayloadLoggingInterceptor_Skipped(t *testing.T) {
+	handler := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+	req := &pb.RegisterRequest{Email: "alice@example.com"}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		methods []string
+		method  string
+		level   logrus.Level
+	}{
+		{name: "disabled", methods: []string{registerMethod}, method: registerMethod, level: logrus.DebugLevel},
+		{name: "method not listed", enabled: true, methods: []string{registerMethod}, method: publicMethod, level: logrus.DebugLevel},
+		{name: "debug level off", enabled: true, methods: []string{registerMethod}, method: registerMethod, level: logrus.InfoLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, hook := newPayloadTestContext(tt.level)
+			interceptor := PayloadLoggingInterceptor(tt.enabled, tt.methods)
+
+			resp, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp)
+			assert.Empty(t, hook.AllEntries())
+		})
+	}
+}
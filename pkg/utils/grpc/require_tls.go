@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+
+	"wallet-user-svc/internal/app/errs"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// http2ALPNProtocol is the ALPN protocol gRPC negotiates over TLS
+const http2ALPNProtocol = "h2"
+
+// RequireTLSInterceptor is a gRPC interceptor that rejects requests not received over a
+// TLS connection that negotiated HTTP/2 through ALPN, logging the rejected peer. It looks
+// at the connection itself rather than the server options, so a server started without
+// TLS credentials by mistake refuses to serve auth traffic in the clear instead of
+// silently downgrading. The exempt methods (e.g. health checks, which probes often make
+// in plaintext) and every request when TLS is not required pass through unchecked.
+func RequireTLSInterceptor(required bool, exempt map[string]bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !required || exempt[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		var peerAddr string
+		var tlsInfo credentials.TLSInfo
+		var isTLS bool
+		if p, ok := peer.FromContext(ctx); ok {
+			if p.Addr != nil {
+				peerAddr = p.Addr.String()
+			}
+			tlsInfo, isTLS = p.AuthInfo.(credentials.TLSInfo)
+		}
+
+		if !isTLS || tlsInfo.State.NegotiatedProtocol != http2ALPNProtocol {
+			logutils.GetLoggerOrDefault(ctx).WithFields(logrus.Fields{
+				"method":    info.FullMethod,
+				"peer_addr": peerAddr,
+				"tls":       isTLS,
+				"alpn":      tlsInfo.State.NegotiatedProtocol,
+			}).Warn("Rejected request over a connection without TLS")
+			return nil, errs.ErrTLSRequired
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestRequireTLSInterceptor(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 40000}
+	tlsPeer := func(protocol string) *peer.Peer {
+		return &peer.Peer{Addr: addr, AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{NegotiatedProtocol: protocol}}}
+	}
+
+	tests := []struct {
+		name        string
+		required    bool
+		method      string
+		peer        *peer.Peer
+		expectedErr error
+	}{
+		{name: "TLS with h2", required: true, peer: tlsPeer("h2")},
+		{name: "plaintext", required: true, peer: &peer.Peer{Addr: addr}, expectedErr: errs.ErrTLSRequired},
+		{name: "TLS without ALPN", required: true, peer: tlsPeer(""), expectedErr: errs.ErrTLSRequired},
+		{name: "no peer", required: true, expectedErr: errs.ErrTLSRequired},
+		{name: "plaintext when not required", peer: &peer.Peer{Addr: addr}},
+		{name: "plaintext health check", required: true, method: healthCheckMethod, peer: &peer.Peer{Addr: addr}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.peer != nil {
+				ctx = peer.NewContext(ctx, tt.peer)
+			}
+			interceptor := RequireTLSInterceptor(tt.required, map[string]bool{healthCheckMethod: true})
+			method := getUserMethod
+			if tt.method != "" {
+				method = tt.method
+			}
+
+			resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, interface{}) (interface{}, error) {
+				return "ok", nil
+			})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// SlowRequestInterceptor is a gRPC interceptor that logs a warning for every request
+// taking longer than threshold, so tail latency stands out from the per-request logs.
+// The goroutine count at completion is included to hint at contention. A threshold
+// <= 0 disables the interceptor.
+func SlowRequestInterceptor(threshold time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if threshold <= 0 {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err = handler(ctx, req)
+		duration := time.Since(start)
+
+		if duration > threshold {
+			logutils.GetLoggerOrDefault(ctx).WithFields(logrus.Fields{
+				"method":     info.FullMethod,
+				"duration":   duration,
+				"threshold":  threshold,
+				"goroutines": runtime.NumGoroutine(),
+				"failed":     err != nil,
+			}).Warn("Slow gRPC request")
+		}
+
+		return resp, err
+	}
+}
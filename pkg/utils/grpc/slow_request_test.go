@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestSlowRequestInterceptor(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+	ctx := logutils.WithLogger(context.Background(), logrus.NewEntry(logger))
+	info := &grpc.UnaryServerInfo{FullMethod: publicMethod}
+
+	interceptor := SlowRequestInterceptor(20 * time.Millisecond)
+	fast := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+	slow := func(context.Context, interface{}) (interface{}, error) {
+		time.Sleep(30 * time.Millisecond)
+		return "ok", nil
+	}
+
+	_, err := interceptor(ctx, nil, info, fast)
+	require.NoError(t, err)
+	assert.Empty(t, hook.AllEntries())
+
+	resp, err := interceptor(ctx, nil, info, slow)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, publicMethod, entry.Data["method"])
+	assert.Contains(t, entry.Data, "duration")
+	assert.Contains(t, entry.Data, "goroutines")
+}
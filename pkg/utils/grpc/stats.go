@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/stats"
+)
+
+type connInfoKey struct{}
+
+type rpcInfoKey struct{}
+
+// connInfo identifies a connection in the log lines of its lifecycle
+type connInfo struct {
+	id         uint64
+	remoteAddr string
+	localAddr  string
+}
+
+// ConnectionLoggingHandler is a stats.Handler that logs connection begin/end with the
+// peer address and RPC begin/end at Debug level. Unlike interceptors it sees connections
+// that never complete an RPC, which helps to debug client churn.
+type ConnectionLoggingHandler struct {
+	logger *logrus.Logger
+	nextID atomic.Uint64
+}
+
+// NewConnectionLoggingHandler creates a handler logging to logger. Register it with
+// grpc.StatsHandler.
+func NewConnectionLoggingHandler(logger *logrus.Logger) *ConnectionLoggingHandler {
+	return &ConnectionLoggingHandler{logger: logger}
+}
+
+// TagConn implements stats.Handler
+func (h *ConnectionLoggingHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	conn := &connInfo{id: h.nextID.Add(1)}
+	if info.RemoteAddr != nil {
+		conn.remoteAddr = info.RemoteAddr.String()
+	}
+	if info.LocalAddr != nil {
+		conn.localAddr = info.LocalAddr.String()
+	}
+	return context.WithValue(ctx, connInfoKey{}, conn)
+}
+
+// HandleConn implements stats.Handler
+func (h *ConnectionLoggingHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	if !h.logger.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+
+	switch s.(type) {
+	case *stats.ConnBegin:
+		h.connLogger(ctx).Debug("gRPC connection opened")
+	case *stats.ConnEnd:
+		h.connLogger(ctx).Debug("gRPC connection closed")
+	}
+}
+
+// TagRPC implements stats.Handler
+func (h *ConnectionLoggingHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcInfoKey{}, info.FullMethodName)
+}
+
+// HandleRPC implements stats.Handler
+func (h *ConnectionLoggingHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if !h.logger.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+
+	method, _ := ctx.Value(rpcInfoKey{}).(string)
+	switch s := s.(type) {
+	case *stats.Begin:
+		h.connLogger(ctx).WithField("method", method).Debug("gRPC call started")
+	case *stats.End:
+		entry := h.connLogger(ctx).WithFields(logrus.Fields{
+			"method":   method,
+			"duration": s.EndTime.Sub(s.BeginTime).Round(time.Microsecond),
+		})
+		if s.Error != nil {
+			entry = entry.WithError(s.Error)
+		}
+		entry.Debug("gRPC call finished")
+	}
+}
+
+// connLogger returns a log entry carrying the connection tagged in ctx
+func (h *ConnectionLoggingHandler) connLogger(ctx context.Context) *logrus.Entry {
+	conn, ok := ctx.Value(connInfoKey{}).(*connInfo)
+	if !ok {
+		return logrus.NewEntry(h.logger)
+	}
+	return h.logger.WithFields(logrus.Fields{
+		"conn_id":     conn.id,
+		"remote_addr": conn.remoteAddr,
+		"local_addr":  conn.localAddr,
+	})
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// callHealthThroughStatsHandler serves the health service with a ConnectionLoggingHandler
+// logging to logger, performs one check and closes the client connection
+func callHealthThroughStatsHandler(t *testing.T, logger *logrus.Logger) {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.StatsHandler(NewConnectionLoggingHandler(logger)))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
+func TestConnectionLoggingHandler(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.DebugLevel)
+	hook := test.NewLocal(logger)
+
+	callHealthThroughStatsHandler(t, logger)
+
+	messages := func() []string {
+		var messages []string
+		for _, entry := range hook.AllEntries() {
+			messages = append(messages, entry.Message)
+		}
+		return messages
+	}
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{
+			"gRPC connection opened",
+			"gRPC call started",
+			"gRPC call finished",
+			"gRPC connection closed",
+		}, messages())
+	}, time.Second, 10*time.Millisecond, "logged %v", messages())
+
+	entries := hook.AllEntries()
+	for _, entry := range entries {
+		assert.Equal(t, logrus.DebugLevel, entry.Level)
+		assert.Equal(t, entries[0].Data["conn_id"], entry.Data["conn_id"], "all lines carry the connection")
+		assert.NotEmpty(t, entry.Data["remote_addr"])
+	}
+	assert.Equal(t, "/grpc.health.v1.Health/Check", entries[2].Data["method"])
+	assert.Contains(t, entries[2].Data, "duration")
+}
+
+func TestConnectionLoggingHandler_SilentAboveDebug(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := test.NewLocal(logger)
+
+	callHealthThroughStatsHandler(t, logger)
+
+	assert.Empty(t, hook.AllEntries())
+}
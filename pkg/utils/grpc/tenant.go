@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"regexp"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/pkg/utils/cx"
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TenantMetadataKey is the metadata key naming the tenant of a request
+const TenantMetadataKey = "x-tenant-id"
+
+// tenantIDPattern matches the tenant IDs accepted in TenantMetadataKey; it fits the
+// tenant_id columns of the users and refresh_tokens tables
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// TenantInterceptor is a gRPC interceptor that scopes each request to the tenant named
+// in the x-tenant-id metadata, storing it with cx.WithTenantID so repositories filter by
+// it. Requests without a valid tenant are rejected, except for the exempt methods (e.g.
+// health checks). When disabled, requests pass through without a tenant.
+func TenantInterceptor(enabled bool, exempt map[string]bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !enabled || exempt[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		var tenantID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(TenantMetadataKey); len(values) > 0 {
+				tenantID = values[0]
+			}
+		}
+		if tenantID == "" {
+			return nil, errs.ErrTenantRequired
+		}
+		if !tenantIDPattern.MatchString(tenantID) {
+			logutils.GetLoggerOrDefault(ctx).WithField("method", info.FullMethod).Warn("Invalid tenant id")
+			return nil, errs.ErrInvalidTenant
+		}
+
+		ctx = logutils.WithContextFields(ctx, logrus.Fields{"tenant_id": tenantID})
+		return handler(cx.WithTenantID(ctx, tenantID), req)
+	}
+}
@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+	"wallet-user-svc/pkg/utils/cx"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const healthCheckMethod = "/grpc.health.v1.Health/Check"
+
+func TestTenantInterceptor(t *testing.T) {
+	interceptor := TenantInterceptor(true, map[string]bool{healthCheckMethod: true})
+
+	tests := []struct {
+		name           string
+		method         string
+		tenant         string
+		expectedTenant string
+		expectedErr    error
+	}{
+		{name: "tenant from metadata", method: getUserMethod, tenant: "acme", expectedTenant: "acme"},
+		{name: "missing tenant", method: getUserMethod, expectedErr: errs.ErrTenantRequired},
+		{name: "invalid tenant", method: getUserMethod, tenant: "acme/../globex", expectedErr: errs.ErrInvalidTenant},
+		{name: "exempt method without tenant", method: healthCheckMethod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.tenant != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(TenantMetadataKey, tt.tenant))
+			}
+
+			var handlerCtx context.Context
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCtx = ctx
+				return "ok", nil
+			}
+
+			resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			tenantID, ok := cx.GetTenantID(handlerCtx)
+			assert.Equal(t, tt.expectedTenant != "", ok)
+			assert.Equal(t, tt.expectedTenant, tenantID)
+		})
+	}
+}
+
+func TestTenantInterceptor_Disabled(t *testing.T) {
+	interceptor := TenantInterceptor(false, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TenantMetadataKey, "acme"))
+
+	var handlerCtx context.Context
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerCtx = ctx
+		return "ok", nil
+	})
+
+	require.NoError(t, err)
+	_, ok := cx.GetTenantID(handlerCtx)
+	assert.False(t, ok, "the metadata is ignored when tenancy is disabled")
+}
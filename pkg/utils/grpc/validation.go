@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+
+	logutils "wallet-user-svc/pkg/utils/log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Validatable is implemented by requests that check the shape of their own fields,
+// such as required values, before any handler logic runs
+type Validatable interface {
+	Validate() error
+}
+
+// ValidationInterceptor is a gRPC interceptor that validates every request implementing
+// Validatable before its handler runs, so no handler can forget to. Requests of other
+// types, and of the skipped methods, pass through unchecked. Validation errors carrying
+// a gRPC status (such as errs.ValidationError with its field violations) are returned
+// as they are; any other error is reported as InvalidArgument.
+func ValidationInterceptor(skipMethods []string) grpc.UnaryServerInterceptor {
+	skipped := make(map[string]bool, len(skipMethods))
+	for _, method := range skipMethods {
+		skipped[method] = true
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		validatable, ok := req.(Validatable)
+		if !ok || skipped[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		if err := validatable.Validate(); err != nil {
+			logutils.GetLoggerOrDefault(ctx).WithError(err).WithField("method", info.FullMethod).Warn("Request failed validation")
+			if _, ok := status.FromError(err); !ok {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "wallet-user-svc/api/proto"
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const refreshTokenMethod = "/user.UserService/RefreshToken"
+
+// plainValidatable fails validation with an error that carries no gRPC status
+type plainValidatable struct{}
+
+func (plainValidatable) Validate() error {
+	return errors.New("broken request")
+}
+
+func TestValidationInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           interface{}
+		skip          []string
+		expectedCode  codes.Code
+		expectedField string
+	}{
+		{name: "valid request", req: &pb.RefreshTokenRequest{RefreshToken: "token"}, expectedCode: codes.OK},
+		{name: "invalid request", req: &pb.RefreshTokenRequest{}, expectedCode: codes.InvalidArgument, expectedField: "refresh_token"},
+		{name: "skipped method", req: &pb.RefreshTokenRequest{}, skip: []string{refreshTokenMethod}, expectedCode: codes.OK},
+		{name: "request without Validate", req: &pb.ListSessionsRequest{}, expectedCode: codes.OK},
+		{name: "error without status", req: plainValidatable{}, expectedCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := ValidationInterceptor(tt.skip)
+
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "ok", nil
+			}
+
+			resp, err := interceptor(context.Background(), tt.req, &grpc.UnaryServerInfo{FullMethod: refreshTokenMethod}, handler)
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				require.NoError(t, err)
+				assert.True(t, called)
+				assert.Equal(t, "ok", resp)
+				return
+			}
+
+			assert.False(t, called, "invalid requests must not reach the handler")
+			assert.Nil(t, resp)
+			if tt.expectedField != "" {
+				assert.ErrorIs(t, err, errs.ErrFieldRequired)
+				violations := errs.FieldViolations(err)
+				require.Len(t, violations, 1)
+				assert.Equal(t, tt.expectedField, violations[0].GetField())
+			}
+		})
+	}
+}
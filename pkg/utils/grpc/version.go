@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ServerVersionHeader is the response header naming the build that served a request
+const ServerVersionHeader = "x-server-version"
+
+// VersionInterceptor is a gRPC interceptor that sends the server version in the
+// x-server-version response header, so clients and their logs can tell which build
+// served a request, for example during a canary rollout. The header is set before the
+// handler runs, so failed calls carry it too.
+func VersionInterceptor(version string) grpc.UnaryServerInterceptor {
+	md := metadata.Pairs(ServerVersionHeader, version)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// Fails only outside a real server stream, e.g. in unit tests calling the handler directly
+		_ = grpc.SetHeader(ctx, md)
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"wallet-user-svc/internal/app/errs"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestVersionInterceptor(t *testing.T) {
+	interceptor := VersionInterceptor("v1.4.0 (3f2c9a1b7d4e)")
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	for _, handlerErr := range []error{nil, errs.ErrUserNotFound} {
+		stream := &headerRecordingStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+		_, err := interceptor(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+			return "ok", handlerErr
+		})
+
+		assert.Equal(t, handlerErr, err)
+		assert.Equal(t, []string{"v1.4.0 (3f2c9a1b7d4e)"}, stream.header.Get(ServerVersionHeader))
+	}
+}
@@ -6,7 +6,8 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// Context key for logger
+// Context key for logger. It is the only logger key; the cx helpers store and read
+// loggers through this package.
 type loggerContextKey struct{}
 
 var LoggerContextKey = loggerContextKey{}
@@ -16,10 +17,20 @@ func WithLogger(ctx context.Context, logger *logrus.Entry) context.Context {
 	return context.WithValue(ctx, LoggerContextKey, logger)
 }
 
-// GetLoggerFromContext retrieves a logger from the context
+// GetLoggerFromContext retrieves a logger from the context. A *logrus.Logger stored
+// under the key is accepted and wrapped in an entry.
 func GetLoggerFromContext(ctx context.Context) (*logrus.Entry, bool) {
-	logger, ok := ctx.Value(LoggerContextKey).(*logrus.Entry)
-	return logger, ok
+	switch logger := ctx.Value(LoggerContextKey).(type) {
+	case *logrus.Entry:
+		return logger, logger != nil
+	case *logrus.Logger:
+		if logger == nil {
+			return nil, false
+		}
+		return logrus.NewEntry(logger), true
+	default:
+		return nil, false
+	}
 }
 
 // GetLoggerOrDefault retrieves a logger from context or returns the default logger
@@ -0,0 +1,24 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLoggerFromContext(t *testing.T) {
+	logger := logrus.New()
+
+	entry, ok := GetLoggerFromContext(context.WithValue(context.Background(), LoggerContextKey, logger))
+	require.True(t, ok, "a *logrus.Logger is wrapped in an entry")
+	assert.Same(t, logger, entry.Logger)
+
+	_, ok = GetLoggerFromContext(context.WithValue(context.Background(), LoggerContextKey, "not a logger"))
+	assert.False(t, ok)
+
+	_, ok = GetLoggerFromContext(context.WithValue(context.Background(), LoggerContextKey, (*logrus.Entry)(nil)))
+	assert.False(t, ok)
+}
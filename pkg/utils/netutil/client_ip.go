@@ -0,0 +1,105 @@
+package netutil
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Forwarding metadata keys set by reverse proxies and load balancers
+const (
+	ForwardedForHeader = "x-forwarded-for"
+	RealIPHeader       = "x-real-ip"
+)
+
+// ClientIPResolver determines the originating client IP of a gRPC request. Forwarding
+// headers are only honoured when the immediate peer is a trusted proxy, so clients
+// connecting directly cannot spoof their address by sending the headers themselves.
+type ClientIPResolver struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewClientIPResolver creates a resolver that trusts forwarding headers from the given ranges
+func NewClientIPResolver(trustedProxies []*net.IPNet) *ClientIPResolver {
+	return &ClientIPResolver{trustedProxies: trustedProxies}
+}
+
+// ClientIP returns the client IP of the request in ctx, or an empty string if the
+// peer address is unknown
+func (r *ClientIPResolver) ClientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	peerIP := addrIP(p.Addr)
+	if peerIP == nil {
+		return ""
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	return r.resolve(peerIP, md.Get(ForwardedForHeader), md.Get(RealIPHeader)).String()
+}
+
+// resolve walks x-forwarded-for from the nearest hop back towards the client and returns
+// the first address that is not a trusted proxy. x-real-ip is used when no
+// x-forwarded-for is present.
+func (r *ClientIPResolver) resolve(peerIP net.IP, forwardedFor, realIP []string) net.IP {
+	if !r.isTrusted(peerIP) {
+		return peerIP
+	}
+
+	var hops []net.IP
+	for _, value := range forwardedFor {
+		for _, hop := range strings.Split(value, ",") {
+			ip := net.ParseIP(strings.TrimSpace(hop))
+			if ip == nil {
+				// A malformed hop breaks the chain; nothing before it can be trusted
+				hops = nil
+				continue
+			}
+			hops = append(hops, ip)
+		}
+	}
+
+	if len(hops) > 0 {
+		for i := len(hops) - 1; i >= 0; i-- {
+			if !r.isTrusted(hops[i]) {
+				return hops[i]
+			}
+		}
+		return hops[0]
+	}
+
+	if len(realIP) > 0 {
+		if ip := net.ParseIP(strings.TrimSpace(realIP[0])); ip != nil {
+			return ip
+		}
+	}
+
+	return peerIP
+}
+
+func (r *ClientIPResolver) isTrusted(ip net.IP) bool {
+	for _, network := range r.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func addrIP(addr net.Addr) net.IP {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.IP
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,90 @@
+package netutil
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIPResolver_ClientIP(t *testing.T) {
+	trusted, err := ParseCIDRs([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+	resolver := NewClientIPResolver(trusted)
+
+	tests := []struct {
+		name     string
+		peerAddr string
+		md       metadata.MD
+		expected string
+	}{
+		{
+			name:     "direct client without headers",
+			peerAddr: "203.0.113.7:5000",
+			expected: "203.0.113.7",
+		},
+		{
+			name:     "untrusted peer cannot spoof forwarded for",
+			peerAddr: "203.0.113.7:5000",
+			md:       metadata.Pairs(ForwardedForHeader, "198.51.100.1"),
+			expected: "203.0.113.7",
+		},
+		{
+			name:     "untrusted peer cannot spoof real ip",
+			peerAddr: "203.0.113.7:5000",
+			md:       metadata.Pairs(RealIPHeader, "198.51.100.1"),
+			expected: "203.0.113.7",
+		},
+		{
+			name:     "trusted proxy forwards client",
+			peerAddr: "10.1.2.3:5000",
+			md:       metadata.Pairs(ForwardedForHeader, "198.51.100.1"),
+			expected: "198.51.100.1",
+		},
+		{
+			name:     "spoofed leftmost hop is skipped",
+			peerAddr: "10.1.2.3:5000",
+			md:       metadata.Pairs(ForwardedForHeader, "1.2.3.4, 198.51.100.1, 192.168.1.1"),
+			expected: "198.51.100.1",
+		},
+		{
+			name:     "trusted proxy with real ip",
+			peerAddr: "10.1.2.3:5000",
+			md:       metadata.Pairs(RealIPHeader, "198.51.100.1"),
+			expected: "198.51.100.1",
+		},
+		{
+			name:     "trusted proxy without headers",
+			peerAddr: "10.1.2.3:5000",
+			expected: "10.1.2.3",
+		},
+		{
+			name:     "malformed header falls back to peer",
+			peerAddr: "10.1.2.3:5000",
+			md:       metadata.Pairs(ForwardedForHeader, "not-an-ip"),
+			expected: "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tt.peerAddr)
+			require.NoError(t, err)
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			assert.Equal(t, tt.expected, resolver.ClientIP(ctx))
+		})
+	}
+}
+
+func TestClientIPResolver_NoPeer(t *testing.T) {
+	assert.Empty(t, NewClientIPResolver(nil).ClientIP(context.Background()))
+}
@@ -0,0 +1,146 @@
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ipLimitListener wraps a net.Listener and caps concurrent connections per client IP
+type ipLimitListener struct {
+	net.Listener
+	logger    *logrus.Logger
+	maxPerIP  int
+	allowlist []*net.IPNet
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewIPLimitListener returns a listener that rejects new connections from a client IP
+// that already holds maxPerIP open connections. IPs inside the allowlist (e.g. internal
+// load balancer ranges) are never limited. A maxPerIP <= 0 disables the limit.
+func NewIPLimitListener(listener net.Listener, maxPerIP int, allowlist []*net.IPNet, logger *logrus.Logger) net.Listener {
+	if maxPerIP <= 0 {
+		return listener
+	}
+
+	return &ipLimitListener{
+		Listener:  listener,
+		logger:    logger,
+		maxPerIP:  maxPerIP,
+		allowlist: allowlist,
+		counts:    make(map[string]int),
+	}
+}
+
+// Accept waits for the next connection that is within its IP's limit
+func (l *ipLimitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		ip := remoteIP(conn)
+		if ip == nil || l.isAllowlisted(ip) {
+			return conn, nil
+		}
+
+		key := ip.String()
+		if !l.acquire(key) {
+			l.logger.WithFields(logrus.Fields{
+				"client_ip":   key,
+				"max_per_ip":  l.maxPerIP,
+				"local_addr":  conn.LocalAddr().String(),
+				"remote_addr": conn.RemoteAddr().String(),
+			}).Warn("Rejected connection: per-IP connection limit reached")
+			conn.Close()
+			continue
+		}
+
+		return &limitedConn{Conn: conn, release: func() { l.release(key) }}, nil
+	}
+}
+
+func (l *ipLimitListener) acquire(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.counts[ip] >= l.maxPerIP {
+		return false
+	}
+	l.counts[ip]++
+	return true
+}
+
+func (l *ipLimitListener) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.counts[ip]--
+	if l.counts[ip] <= 0 {
+		delete(l.counts, ip)
+	}
+}
+
+func (l *ipLimitListener) isAllowlisted(ip net.IP) bool {
+	for _, network := range l.allowlist {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedConn releases its IP slot exactly once when closed
+type limitedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
+
+func remoteIP(conn net.Conn) net.IP {
+	return addrIP(conn.RemoteAddr())
+}
+
+// ParseCIDRs parses a list of CIDR ranges. Plain IP addresses are treated as single-host ranges.
+func ParseCIDRs(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", value)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
@@ -0,0 +1,117 @@
+package netutil
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// acceptAsync accepts connections in the background and delivers them on the returned channel
+func acceptAsync(t *testing.T, listener net.Listener) <-chan net.Conn {
+	t.Helper()
+	accepted := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+	return accepted
+}
+
+func dial(t *testing.T, addr string) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func expectAccepted(t *testing.T, accepted <-chan net.Conn) net.Conn {
+	t.Helper()
+	select {
+	case conn := <-accepted:
+		return conn
+	case <-time.After(time.Second):
+		t.Fatal("expected connection to be accepted")
+		return nil
+	}
+}
+
+func expectNotAccepted(t *testing.T, accepted <-chan net.Conn) {
+	t.Helper()
+	select {
+	case <-accepted:
+		t.Fatal("expected connection to be rejected")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestIPLimitListener_RejectsOverLimit(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listener := NewIPLimitListener(inner, 2, nil, newTestLogger())
+	defer listener.Close()
+
+	accepted := acceptAsync(t, listener)
+	addr := listener.Addr().String()
+
+	dial(t, addr)
+	first := expectAccepted(t, accepted)
+	dial(t, addr)
+	expectAccepted(t, accepted)
+
+	// Third concurrent connection from the same IP is rejected
+	dial(t, addr)
+	expectNotAccepted(t, accepted)
+
+	// Closing a connection frees a slot
+	require.NoError(t, first.Close())
+	dial(t, addr)
+	expectAccepted(t, accepted)
+}
+
+func TestIPLimitListener_AllowlistBypassesLimit(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	allowlist, err := ParseCIDRs([]string{"127.0.0.0/8"})
+	require.NoError(t, err)
+	listener := NewIPLimitListener(inner, 1, allowlist, newTestLogger())
+	defer listener.Close()
+
+	accepted := acceptAsync(t, listener)
+	addr := listener.Addr().String()
+
+	for i := 0; i < 3; i++ {
+		dial(t, addr)
+		expectAccepted(t, accepted)
+	}
+}
+
+func TestParseCIDRs(t *testing.T) {
+	networks, err := ParseCIDRs([]string{"10.0.0.0/8", "192.168.1.10", " ", "::1"})
+	require.NoError(t, err)
+	require.Len(t, networks, 3)
+	assert.True(t, networks[0].Contains(net.ParseIP("10.1.2.3")))
+	assert.True(t, networks[1].Contains(net.ParseIP("192.168.1.10")))
+	assert.False(t, networks[1].Contains(net.ParseIP("192.168.1.11")))
+	assert.True(t, networks[2].Contains(net.ParseIP("::1")))
+
+	_, err = ParseCIDRs([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
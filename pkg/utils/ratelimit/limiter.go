@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	logutils "wallet-user-svc/pkg/utils/log"
+)
+
+// Store records rate limited events. Implementations must be safe for concurrent use;
+// a shared store such as RedisStore makes limits hold across replicas.
+type Store interface {
+	// Allow records an event for key and reports whether fewer than limit events were
+	// recorded for key within window before it. Rejected events are not recorded.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+	// Count returns the number of events recorded for key within window
+	Count(ctx context.Context, key string, window time.Duration) (int, error)
+	// Reset clears the recorded events for key
+	Reset(ctx context.Context, key string) error
+}
+
+// Limiter limits events per key (user ID, client IP, ...) to limit within each window.
+// It is safe for concurrent use.
+type Limiter struct {
+	store  Store
+	name   string
+	limit  int
+	window time.Duration
+}
+
+// NewLimiter creates a limiter backed by its own in-memory store, allowing limit events
+// per key within each window. A non-positive limit or window disables limiting.
+func NewLimiter(limit int, window time.Duration) *Limiter {
+	return NewStoreLimiter(NewMemoryStore(), "", limit, window)
+}
+
+// NewStoreLimiter creates a limiter recording events in store. name namespaces the keys
+// so several limiters can share one store. A non-positive limit or window disables limiting.
+func NewStoreLimiter(store Store, name string, limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		store:  store,
+		name:   name,
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Allow records an event for key and reports whether it is within the limit. If the
+// store fails, or ctx ends before it answers, the event is allowed, so an unavailable
+// store does not lock users out.
+func (l *Limiter) Allow(ctx context.Context, key string) bool {
+	if l.limit <= 0 || l.window <= 0 {
+		return true
+	}
+
+	allowed, err := l.store.Allow(ctx, l.key(key), l.limit, l.window)
+	if err != nil {
+		logutils.WithError(err).WithField("limiter", l.name).Error("Rate limit store failed, allowing event")
+		return true
+	}
+	return allowed
+}
+
+// Exceeded reports whether key has used up its limit, without recording an event. It
+// suits limits on failures: check Exceeded before an attempt and call Allow to record
+// each failure. Like Allow, it reports false if the store fails.
+func (l *Limiter) Exceeded(ctx context.Context, key string) bool {
+	if l.limit <= 0 || l.window <= 0 {
+		return false
+	}
+
+	count, err := l.store.Count(ctx, l.key(key), l.window)
+	if err != nil {
+		logutils.WithError(err).WithField("limiter", l.name).Error("Rate limit store failed, allowing event")
+		return false
+	}
+	return count >= l.limit
+}
+
+// Reset clears the recorded events for key
+func (l *Limiter) Reset(ctx context.Context, key string) {
+	if err := l.store.Reset(ctx, l.key(key)); err != nil {
+		logutils.WithError(err).WithField("limiter", l.name).Error("Failed to reset rate limit")
+	}
+}
+
+func (l *Limiter) key(key string) string {
+	if l.name == "" {
+		return key
+	}
+	return l.name + ":" + key
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limiter := NewStoreLimiter(store, "", 2, time.Minute)
+
+	assert.True(t, limiter.Allow(ctx, "user-1"))
+	assert.True(t, limiter.Allow(ctx, "user-1"))
+	assert.False(t, limiter.Allow(ctx, "user-1"), "third event in the window should be rejected")
+	assert.True(t, limiter.Allow(ctx, "user-2"), "keys are limited independently")
+
+	now = now.Add(time.Minute)
+	assert.True(t, limiter.Allow(ctx, "user-1"), "a new window should allow events again")
+}
+
+func TestLimiter_Reset(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLimiter(1, time.Minute)
+
+	assert.True(t, limiter.Allow(ctx, "user-1"))
+	assert.False(t, limiter.Allow(ctx, "user-1"))
+
+	limiter.Reset(ctx, "user-1")
+	assert.True(t, limiter.Allow(ctx, "user-1"))
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLimiter(0, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.Allow(ctx, "user-1"))
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, int, time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (failingStore) Count(context.Context, string, time.Duration) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (failingStore) Reset(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+func TestLimiter_StoreFailureAllows(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewStoreLimiter(failingStore{}, "login", 1, time.Minute)
+	assert.True(t, limiter.Allow(ctx, "user-1"))
+	assert.True(t, limiter.Allow(ctx, "user-1"))
+	assert.False(t, limiter.Exceeded(ctx, "user-1"))
+}
+
+func TestLimiter_Exceeded(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limiter := NewStoreLimiter(store, "refresh_token", 2, time.Minute)
+
+	assert.False(t, limiter.Exceeded(ctx, "203.0.113.7"))
+	limiter.Allow(ctx, "203.0.113.7")
+	assert.False(t, limiter.Exceeded(ctx, "203.0.113.7"), "checking must not record an event")
+	assert.False(t, limiter.Exceeded(ctx, "203.0.113.7"))
+	limiter.Allow(ctx, "203.0.113.7")
+	assert.True(t, limiter.Exceeded(ctx, "203.0.113.7"))
+	assert.False(t, limiter.Exceeded(ctx, "198.51.100.1"))
+
+	now = now.Add(time.Minute)
+	assert.False(t, limiter.Exceeded(ctx, "203.0.113.7"), "a new window should clear the limit")
+	assert.False(t, NewLimiter(0, time.Minute).Exceeded(ctx, "203.0.113.7"), "a disabled limiter is never exceeded")
+}
+
+func TestLimiter_NamespacesSharedStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	login := NewStoreLimiter(store, "login", 1, time.Minute)
+	verify := NewStoreLimiter(store, "verify_password", 1, time.Minute)
+
+	assert.True(t, login.Allow(ctx, "user-1"))
+	assert.True(t, verify.Allow(ctx, "user-1"), "limiters sharing a store count separately")
+	assert.False(t, login.Allow(ctx, "user-1"))
+}
+
+func TestMemoryStore_EvictsOnInterval(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	allowed, err := store.Allow(ctx, "user-1", 1, time.Second)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	now = now.Add(2 * time.Second)
+	allowed, err = store.Allow(ctx, "user-2", 1, time.Second)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Len(t, store.counters, 2, "ended windows are kept until the next sweep")
+
+	allowed, err = store.Allow(ctx, "user-1", 1, time.Second)
+	assert.NoError(t, err)
+	assert.True(t, allowed, "an ended window allows events again before it is swept")
+
+	now = now.Add(evictInterval)
+	_, err = store.Allow(ctx, "user-3", 1, time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, store.counters, 1, "the sweep drops ended windows")
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// evictInterval is how often Allow sweeps ended windows out of a MemoryStore. Sweeping
+// walks every key under the lock, so it runs at most this often instead of per event.
+const evictInterval = time.Minute
+
+// MemoryStore is an in-process fixed-window Store. Limits only hold within a single
+// replica, so it suits single-node deployments and tests.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+	now      func() time.Time
+	// nextEvict is when Allow next sweeps ended windows
+	nextEvict time.Time
+}
+
+type counter struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters: make(map[string]*counter),
+		now:      time.Now,
+	}
+}
+
+// Allow implements Store
+func (s *MemoryStore) Allow(_ context.Context, key string, limit int, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !now.Before(s.nextEvict) {
+		s.evictExpired(now)
+		s.nextEvict = now.Add(evictInterval)
+	}
+
+	w, ok := s.counters[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &counter{resetAt: now.Add(window)}
+		s.counters[key] = w
+	}
+
+	if w.count >= limit {
+		return false, nil
+	}
+
+	w.count++
+	return true, nil
+}
+
+// Count implements Store. The window is fixed when the first event of a key is recorded.
+func (s *MemoryStore) Count(_ context.Context, key string, _ time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.counters[key]
+	if !ok || !s.now().Before(w.resetAt) {
+		return 0, nil
+	}
+	return w.count, nil
+}
+
+// Reset implements Store
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	return nil
+}
+
+// evictExpired drops windows that have ended so idle keys do not accumulate
+func (s *MemoryStore) evictExpired(now time.Time) {
+	for key, w := range s.counters {
+		if !now.Before(w.resetAt) {
+			delete(s.counters, key)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript keeps one sorted set member per allowed event, scored by its time
+// in microseconds. Events older than the window are dropped before counting, and the
+// key expires once the window has passed without events.
+//
+// KEYS[1] key, ARGV[1] limit, ARGV[2] window (µs), ARGV[3] now (µs), ARGV[4] event ID
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+if redis.call('ZCARD', key) >= limit then
+	return 0
+end
+
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, math.ceil(window / 1000))
+return 1
+`)
+
+// RedisStore is a sliding-window Store shared by all replicas using the same Redis.
+// Each check runs atomically in a Lua script.
+type RedisStore struct {
+	client redis.Cmdable
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedisStore creates a store keeping its events under keys starting with prefix
+func NewRedisStore(client redis.Cmdable, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+// Allow implements Store
+func (s *RedisStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	allowed, err := slidingWindowScript.Run(ctx, s.client, []string{s.prefix + key},
+		limit,
+		window.Microseconds(),
+		strconv.FormatInt(s.now().UnixMicro(), 10),
+		uuid.NewString(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return allowed == 1, nil
+}
+
+// Count implements Store
+func (s *RedisStore) Count(ctx context.Context, key string, window time.Duration) (int, error) {
+	now := s.now()
+	count, err := s.client.ZCount(ctx, s.prefix+key,
+		"("+strconv.FormatInt(now.Add(-window).UnixMicro(), 10),
+		"+inf",
+	).Result()
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// Reset implements Store
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
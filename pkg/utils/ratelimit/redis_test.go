@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisStore(client, "ratelimit:"), server
+}
+
+func TestRedisStore_SlidingWindow(t *testing.T) {
+	store, server := newTestRedisStore(t)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	allow := func() bool {
+		allowed, err := store.Allow(ctx, "login:1.2.3.4", 2, time.Minute)
+		require.NoError(t, err)
+		return allowed
+	}
+
+	assert.True(t, allow())
+	now = now.Add(30 * time.Second)
+	assert.True(t, allow())
+	assert.False(t, allow(), "third event within the window should be rejected")
+
+	now = now.Add(31 * time.Second)
+	assert.True(t, allow(), "the first event slid out of the window")
+	assert.False(t, allow(), "the second event is still within the window")
+
+	assert.True(t, server.Exists("ratelimit:login:1.2.3.4"))
+	assert.LessOrEqual(t, server.TTL("ratelimit:login:1.2.3.4"), time.Minute, "idle keys expire")
+}
+
+func TestRedisStore_Count(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	count := func() int {
+		count, err := store.Count(ctx, "refresh_token:1.2.3.4", time.Minute)
+		require.NoError(t, err)
+		return count
+	}
+
+	assert.Zero(t, count())
+	_, err := store.Allow(ctx, "refresh_token:1.2.3.4", 5, time.Minute)
+	require.NoError(t, err)
+	now = now.Add(30 * time.Second)
+	_, err = store.Allow(ctx, "refresh_token:1.2.3.4", 5, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count())
+
+	now = now.Add(31 * time.Second)
+	assert.Equal(t, 1, count(), "events outside the window are not counted")
+}
+
+func TestRedisStore_Reset(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	ctx := context.Background()
+
+	allowed, err := store.Allow(ctx, "user-1", 1, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = store.Allow(ctx, "user-1", 1, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	require.NoError(t, store.Reset(ctx, "user-1"))
+	allowed, err = store.Allow(ctx, "user-1", 1, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestRedisStore_SharedAcrossLimiters(t *testing.T) {
+	store, server := newTestRedisStore(t)
+	ctx := context.Background()
+	otherClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { otherClient.Close() })
+
+	replicaA := NewStoreLimiter(store, "login", 2, time.Minute)
+	replicaB := NewStoreLimiter(NewRedisStore(otherClient, "ratelimit:"), "login", 2, time.Minute)
+
+	assert.True(t, replicaA.Allow(ctx, "1.2.3.4"))
+	assert.True(t, replicaB.Allow(ctx, "1.2.3.4"))
+	assert.False(t, replicaA.Allow(ctx, "1.2.3.4"), "replicas share the limit")
+}
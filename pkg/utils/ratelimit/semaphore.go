@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Semaphore bounds how many callers run an expensive operation, such as hashing a
+// password, at the same time. It is safe for concurrent use; a nil Semaphore never blocks.
+type Semaphore struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+// NewSemaphore creates a semaphore admitting size callers at once. Callers beyond that
+// wait up to wait for a slot; a zero wait fails them immediately. A non-positive size
+// disables the limit and returns nil.
+func NewSemaphore(size int, wait time.Duration) *Semaphore {
+	if size <= 0 {
+		return nil
+	}
+	return &Semaphore{
+		slots: make(chan struct{}, size),
+		wait:  max(wait, 0),
+	}
+}
+
+// Acquire takes a slot and reports whether it got one before the wait ran out or ctx
+// ended. Every successful Acquire must be followed by a Release.
+func (s *Semaphore) Acquire(ctx context.Context) bool {
+	if s == nil {
+		return true
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+	}
+	if s.wait == 0 {
+		return false
+	}
+
+	timer := time.NewTimer(s.wait)
+	defer timer.Stop()
+
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Release returns a slot taken by Acquire
+func (s *Semaphore) Release() {
+	if s == nil {
+		return
+	}
+	<-s.slots
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemaphore_AcquireRelease(t *testing.T) {
+	semaphore := NewSemaphore(1, 0)
+
+	assert.True(t, semaphore.Acquire(context.Background()))
+	assert.False(t, semaphore.Acquire(context.Background()), "a full semaphore fails fast without a wait")
+
+	semaphore.Release()
+	assert.True(t, semaphore.Acquire(context.Background()), "a released slot can be taken again")
+}
+
+func TestSemaphore_WaitsForSlot(t *testing.T) {
+	semaphore := NewSemaphore(1, time.Second)
+	assert.True(t, semaphore.Acquire(context.Background()))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		semaphore.Release()
+	}()
+	assert.True(t, semaphore.Acquire(context.Background()), "the waiter gets the released slot")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, semaphore.Acquire(ctx), "a cancelled caller stops waiting")
+}
+
+func TestSemaphore_Disabled(t *testing.T) {
+	semaphore := NewSemaphore(0, 0)
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, semaphore.Acquire(context.Background()))
+	}
+	semaphore.Release()
+}
@@ -29,6 +29,11 @@ func GetTxFromContext(ctx context.Context) (*sqlx.Tx, bool) {
 	return tx, ok
 }
 
+// BeforeCommitHook runs inside the transaction after the transaction function succeeds
+// and before the commit. Returning an error rolls the transaction back, which makes it
+// the place for cross-cutting invariants that must hold for the committed state.
+type BeforeCommitHook func(*TxWrapper) error
+
 // TransactionManager manages database transactions
 type TransactionManager struct {
 	db *sqlx.DB
@@ -47,8 +52,10 @@ func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(*TxWr
 	})
 }
 
-// WithTransactionOptions executes a function within a database transaction with custom options
-func (tm *TransactionManager) WithTransactionOptions(ctx context.Context, fn func(*TxWrapper) error, opts *sql.TxOptions) error {
+// WithTransactionOptions executes a function within a database transaction with custom options.
+// Optional beforeCommit hooks run in order after fn succeeds; the first failing hook
+// rolls the transaction back and its error is returned.
+func (tm *TransactionManager) WithTransactionOptions(ctx context.Context, fn func(*TxWrapper) error, opts *sql.TxOptions, beforeCommit ...BeforeCommitHook) error {
 	tx, err := tm.db.BeginTxx(ctx, opts)
 	if err != nil {
 		return err
@@ -67,6 +74,15 @@ func (tm *TransactionManager) WithTransactionOptions(ctx context.Context, fn fun
 		return err
 	}
 
+	// Check invariants before making the changes visible
+	for _, hook := range beforeCommit {
+		if err := hook(txWrapper); err != nil {
+			// Rollback error is ignored in favour of the hook error, as above
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
 	// Commit on success
 	return tx.Commit()
 }
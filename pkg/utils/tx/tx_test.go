@@ -0,0 +1,137 @@
+package tx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDriver is a database/sql driver that only supports transactions and
+// records how each one ended
+type recordingDriver struct {
+	mu      sync.Mutex
+	results []string
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+func (d *recordingDriver) record(result string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.results = append(d.results, result)
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not supported")
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return &recordingTx{driver: c.driver}, nil
+}
+
+type recordingTx struct {
+	driver *recordingDriver
+}
+
+func (t *recordingTx) Commit() error {
+	t.driver.record("commit")
+	return nil
+}
+
+func (t *recordingTx) Rollback() error {
+	t.driver.record("rollback")
+	return nil
+}
+
+var testDriver = &recordingDriver{}
+
+func init() {
+	sql.Register("tx-recording", testDriver)
+}
+
+func newTestManager(t *testing.T) *TransactionManager {
+	t.Helper()
+
+	db, err := sqlx.Open("tx-recording", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	testDriver.mu.Lock()
+	testDriver.results = nil
+	testDriver.mu.Unlock()
+
+	return NewTransactionManager(db)
+}
+
+func TestWithTransactionOptions_BeforeCommitHooks(t *testing.T) {
+	manager := newTestManager(t)
+
+	var calls []string
+	err := manager.WithTransactionOptions(context.Background(), func(*TxWrapper) error {
+		calls = append(calls, "fn")
+		return nil
+	}, nil, func(*TxWrapper) error {
+		calls = append(calls, "first hook")
+		return nil
+	}, func(*TxWrapper) error {
+		calls = append(calls, "second hook")
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fn", "first hook", "second hook"}, calls)
+	assert.Equal(t, []string{"commit"}, testDriver.results)
+}
+
+func TestWithTransactionOptions_BeforeCommitHookErrorRollsBack(t *testing.T) {
+	manager := newTestManager(t)
+	invariantErr := errors.New("too many active tokens")
+
+	laterHookCalled := false
+	err := manager.WithTransactionOptions(context.Background(), func(*TxWrapper) error {
+		return nil
+	}, nil, func(*TxWrapper) error {
+		return invariantErr
+	}, func(*TxWrapper) error {
+		laterHookCalled = true
+		return nil
+	})
+
+	assert.ErrorIs(t, err, invariantErr)
+	assert.False(t, laterHookCalled, "hooks after a failing hook must not run")
+	assert.Equal(t, []string{"rollback"}, testDriver.results)
+}
+
+func TestWithTransactionOptions_HooksSkippedWhenFnFails(t *testing.T) {
+	manager := newTestManager(t)
+	fnErr := errors.New("insert failed")
+
+	hookCalled := false
+	err := manager.WithTransactionOptions(context.Background(), func(*TxWrapper) error {
+		return fnErr
+	}, nil, func(*TxWrapper) error {
+		hookCalled = true
+		return nil
+	})
+
+	assert.ErrorIs(t, err, fnErr)
+	assert.False(t, hookCalled)
+	assert.Equal(t, []string{"rollback"}, testDriver.results)
+}
@@ -0,0 +1,66 @@
+// Package version holds the build metadata of the running binary. The variables are
+// set at build time through ldflags, for example
+//
+//	go build -ldflags "-X wallet-user-svc/pkg/version.Version=v1.4.0 -X wallet-user-svc/pkg/version.Commit=$(git rev-parse HEAD)"
+//
+// Values left unset fall back to what the Go toolchain embedded in the binary.
+package version
+
+import "runtime/debug"
+
+// Build metadata injected with -ldflags "-X"
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+// devVersion is reported when the binary was built without a version
+const devVersion = "dev"
+
+// Info is the build metadata of the running binary
+type Info struct {
+	Version   string
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// Get returns the build metadata, preferring the ldflags values over the module
+// version and VCS details embedded by the Go toolchain
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = build.GoVersion
+		if info.Version == "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = devVersion
+	}
+	return info
+}
+
+// String returns the version followed by the short commit, e.g. "v1.4.0 (3f2c9a1b7d4e)",
+// as sent to clients in the x-server-version header
+func (i Info) String() string {
+	if i.Commit == "" {
+		return i.Version
+	}
+	commit := i.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	return i.Version + " (" + commit + ")"
+}
@@ -0,0 +1,25 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet_PrefersLdflags(t *testing.T) {
+	t.Cleanup(func() { Version, Commit, BuildTime = "", "", "" })
+	Version, Commit, BuildTime = "v1.4.0", "3f2c9a1b7d4e5f60718293a4b5c6d7e8f9012345", "2026-10-01T12:00:00Z"
+
+	info := Get()
+	assert.Equal(t, "v1.4.0", info.Version)
+	assert.Equal(t, "3f2c9a1b7d4e5f60718293a4b5c6d7e8f9012345", info.Commit)
+	assert.Equal(t, "2026-10-01T12:00:00Z", info.BuildTime)
+	assert.NotEmpty(t, info.GoVersion)
+	assert.Equal(t, "v1.4.0 (3f2c9a1b7d4e)", info.String())
+}
+
+func TestGet_DefaultsToDev(t *testing.T) {
+	// Test binaries are built without a module version
+	assert.Equal(t, "dev", Get().Version)
+	assert.Equal(t, "dev", Info{Version: "dev"}.String())
+}